@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
@@ -95,17 +96,26 @@ func restServer(_ *cobra.Command, _ []string) {
 	rest.InitRestNewsletter(apiGroup, newsletterUsecase)
 	rest.InitRestFileManager(apiGroup)
 	rest.InitRestMonitor(apiGroup)
-	rest.InitRestTemplates(apiGroup)
-	rest.InitRestQueue(apiGroup)
+	templatesHandler := rest.InitRestTemplates(apiGroup)
+	queueHandler := rest.InitRestQueue(apiGroup)
 	rest.InitRestUpdater(apiGroup)
-	rest.InitRestWebhook(apiGroup)
+	webhookHandler := rest.InitRestWebhook(apiGroup)
 	rest.InitRestCache(apiGroup)
-	rest.InitRestBackup(apiGroup)
-	rest.InitRestSystem(apiGroup)
+	multiInstanceHandler := rest.InitRestMultiInstance(apiGroup)
+	rest.InitRestBackup(apiGroup, multiInstanceHandler.Manager())
+	rest.InitRestSystem(apiGroup, multiInstanceHandler.Manager(), queueHandler.Manager(), webhookHandler.Manager())
+	autoReplyHandler := rest.InitRestAutoReply(apiGroup, templatesHandler.Manager(), queueHandler.Manager())
+	whatsapp.SetAutoReplyManager(autoReplyHandler.Manager())
 	analyticsHandler := rest.InitRestAnalytics(apiGroup)
+	rest.InitRestReportSchedule(apiGroup, analyticsHandler.Manager(), webhookHandler.Manager(), templatesHandler.Manager(), queueHandler.Manager())
+	rest.InitRestCampaign(apiGroup, templatesHandler.Manager(), queueHandler.Manager())
+	instanceControlHandler := rest.InitRestInstanceControl(apiGroup)
+	maintenanceHandler := rest.InitRestMaintenance(apiGroup)
 
 	// Add analytics tracking middleware
 	apiGroup.Use(analyticsHandler.TrackingMiddleware())
+	apiGroup.Use(instanceControlHandler.TrackingMiddleware())
+	apiGroup.Use(maintenanceHandler.Middleware())
 
 	apiGroup.Get("/", func(c *fiber.Ctx) error {
 		// Get basic auth credentials if available
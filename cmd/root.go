@@ -144,6 +144,14 @@ func initEnvConfig() {
 	if viper.IsSet("whatsapp_account_validation") {
 		config.WhatsappAccountValidation = viper.GetBool("whatsapp_account_validation")
 	}
+
+	// Instance control settings (only set when running as a multi-instance child)
+	if envInstanceID := viper.GetString("whatsapp_instance_id"); envInstanceID != "" {
+		config.InstanceID = envInstanceID
+	}
+	if envInstanceSecret := viper.GetString("whatsapp_instance_secret"); envInstanceSecret != "" {
+		config.InstanceControlSecret = envInstanceSecret
+	}
 }
 
 func initFlags() {
@@ -312,6 +320,8 @@ func initApp() {
 		DB:       config.RedisDB,
 		Prefix:   config.RedisPrefix,
 		URL:      config.RedisURL,
+
+		StatsIntervalSeconds: config.RedisStatsIntervalSeconds,
 	}
 	cache.InitializeCache(cacheConfig)
 
@@ -58,6 +58,11 @@ var (
 	RedisPrefix   = "whatsapp"
 	RedisURL      = "rediss://:ATX2AAIjcDEyYzY5OGExZGE3Njc0NTJlODk2MDgxYmI3YzE3YTE3ZnAxMA@modern-mantis-13814.upstash.io:6379" // Upstash Redis URL (rediss:// for SSL)
 
+	// RedisStatsIntervalSeconds controls how often the cache periodically
+	// counts keys via SCAN. 0 uses the default of 30 seconds; a negative
+	// value disables the periodic collector entirely.
+	RedisStatsIntervalSeconds = 30
+
 	// Cloud Backup Settings (Backblaze B2 by default)
 	BackupEnabled         = true
 	BackupProvider        = "b2" // "b2" for Backblaze B2, "gcs" for Google Cloud Storage
@@ -69,4 +74,47 @@ var (
 	BackupRetentionDays   = 30
 	BackupScheduleEnabled = true
 	BackupScheduleCron    = "0 2 * * *" // Daily at 2 AM
+
+	// High Availability Settings
+	MultiInstanceHAEnabled = false // Enables automatic failover between instances
+
+	// Webhook Settings
+	WebhookMaxConcurrentDeliveries = 50 // Global cap on in-flight webhook deliveries across all endpoints
+
+	// Instance Control Settings
+	InstanceID            = "" // Set via WHATSAPP_INSTANCE_ID when running as a multi-instance child
+	InstanceControlSecret = "" // Set via WHATSAPP_INSTANCE_SECRET; authenticates the parent's internal control API calls
+
+	// Instance Port Allocation Settings - the range findAvailablePort searches
+	// when a new instance doesn't request a specific port. Keep this narrow in
+	// containerized environments where only a fixed range is exposed.
+	MultiInstancePortRangeStart = 3001 // Inclusive
+	MultiInstancePortRangeEnd   = 4000 // Exclusive
+
+	// Session Storage At-Rest Protection - covers the on-disk session.json
+	// written by pkg/isolation's SessionIsolationManager, which otherwise
+	// holds linked-device connection info in plain JSON. Both are opt-in so
+	// existing plaintext session files keep loading unchanged until enabled.
+	SessionStorageCompressionEnabled = false
+	SessionStorageEncryptionEnabled  = false
+	SessionStorageEncryptionKey      = "" // Required when SessionStorageEncryptionEnabled is true; passphrase is hashed into an AES-256 key
+
+	// Analytics Forwarding Settings - pushes tracked events to an external
+	// observability sink in addition to the on-disk store. Disabled by default.
+	AnalyticsForwarderEnabled       = false
+	AnalyticsForwarderSink          = "http" // "http" or "statsd"
+	AnalyticsForwarderHTTPURL       = ""     // Collector endpoint used when AnalyticsForwarderSink is "http"
+	AnalyticsForwarderStatsDAddr    = ""     // "host:port" of the StatsD server used when AnalyticsForwarderSink is "statsd"
+	AnalyticsForwarderBatchSize     = 50
+	AnalyticsForwarderFlushInterval = 10 // seconds
+	AnalyticsForwarderMaxRetries    = 3
+
+	// AnalyticsMaxEventDataBytes caps the serialized size of a tracked event's
+	// Data field. A caller passing something like a full message body would
+	// otherwise bloat events.json and memory unbounded; past this cap, large
+	// string fields are truncated instead of the event being dropped.
+	AnalyticsMaxEventDataBytes = 8192
+	// AnalyticsMaxFieldStringBytes is the per-field length a string value is
+	// truncated to once AnalyticsMaxEventDataBytes is exceeded.
+	AnalyticsMaxFieldStringBytes = 1024
 )
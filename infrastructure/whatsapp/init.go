@@ -14,6 +14,7 @@ import (
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/autoreply"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
@@ -44,8 +45,21 @@ var (
 	log           waLog.Logger
 	historySyncID int32
 	startupTime   = time.Now().Unix()
+
+	// autoReplyMgr is the optional rules engine consulted by handleAutoReply
+	// before falling back to the static WhatsappAutoReplyMessage config
+	// value. It's nil until SetAutoReplyManager is called, which the REST
+	// server does at startup; commands that never start the REST server
+	// simply keep the static fallback behavior.
+	autoReplyMgr *autoreply.Manager
 )
 
+// SetAutoReplyManager wires the auto-reply rules engine into inbound message
+// handling.
+func SetAutoReplyManager(m *autoreply.Manager) {
+	autoReplyMgr = m
+}
+
 // InitWaDB initializes the WhatsApp database connection
 func InitWaDB(ctx context.Context, DBURI string) *sqlstore.Container {
 	log = waLog.Stdout("Main", config.WhatsappLogLevel, true)
@@ -540,7 +554,7 @@ func handleAutoMarkRead(_ context.Context, evt *events.Message) {
 }
 
 func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) {
-	if config.WhatsappAutoReplyMessage == "" {
+	if config.WhatsappAutoReplyMessage == "" && autoReplyMgr == nil {
 		return
 	}
 
@@ -588,16 +602,21 @@ func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo d
 	}
 
 	// Check for genuine typed text on the unwrapped content
+	messageText := ""
 	if conv := innerMsg.GetConversation(); conv != "" {
 		hasText = true
+		messageText = conv
 	} else if ext := innerMsg.GetExtendedTextMessage(); ext != nil && ext.GetText() != "" {
 		hasText = true
+		messageText = ext.GetText()
 	} else if protoMsg := innerMsg.GetProtocolMessage(); protoMsg != nil {
 		if edited := protoMsg.GetEditedMessage(); edited != nil {
 			if ext := edited.GetExtendedTextMessage(); ext != nil && ext.GetText() != "" {
 				hasText = true
+				messageText = ext.GetText()
 			} else if conv := edited.GetConversation(); conv != "" {
 				hasText = true
+				messageText = conv
 			}
 		}
 	}
@@ -608,6 +627,23 @@ func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo d
 	// Format recipient JID
 	recipientJID := utils.FormatJID(evt.Info.Sender.String())
 
+	// Try the rules engine first: a matching rule renders its own template
+	// and enqueues the reply via the queue, so it doesn't send inline like
+	// the static fallback below does.
+	if autoReplyMgr != nil {
+		rule, err := autoReplyMgr.EvaluateAndReply(messageText, recipientJID.String())
+		if err != nil {
+			log.Errorf("Failed to evaluate auto-reply rules: %v", err)
+		} else if rule != nil {
+			log.Debugf("Auto-reply rule %s matched, reply enqueued for %s", rule.ID, recipientJID.String())
+			return
+		}
+	}
+
+	if config.WhatsappAutoReplyMessage == "" {
+		return
+	}
+
 	// Send the auto-reply message
 	response, err := cli.SendMessage(
 		ctx,
@@ -0,0 +1,284 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/sirupsen/logrus"
+)
+
+// Sink delivers a batch of analytics events to an external observability
+// system. Implementations should treat events as already-final: Send is
+// called with a batch removed from the Forwarder's buffer, so a failed Send
+// causes the whole batch to be retried, not individual events.
+type Sink interface {
+	Send(events []Event) error
+}
+
+// NewSink builds the Sink named by kind ("http" or "statsd"). It returns an
+// error for an unrecognized kind so callers can fail fast on misconfiguration
+// instead of silently forwarding nowhere.
+func NewSink(kind, target string) (Sink, error) {
+	switch kind {
+	case "http":
+		return NewHTTPSink(target), nil
+	case "statsd":
+		return NewStatsDSink(target)
+	default:
+		return nil, fmt.Errorf("unknown analytics forwarder sink %q", kind)
+	}
+}
+
+// HTTPSink posts batches of events as a JSON array to a collector endpoint.
+type HTTPSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPSink creates a Sink that POSTs event batches to url as JSON.
+func NewHTTPSink(url string) *HTTPSink {
+	return &HTTPSink{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *HTTPSink) Send(events []Event) error {
+	payload, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("marshal event batch: %w", err)
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("post event batch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// StatsDSink emits each event as a StatsD counter metric over UDP, in the
+// form "analytics.<type>.<category>.<action>:1|c".
+type StatsDSink struct {
+	addr string
+	conn net.Conn
+}
+
+// NewStatsDSink dials addr (a "host:port" pair) as a UDP socket. UDP dial
+// doesn't require the peer to be reachable, so this only fails on a
+// malformed address.
+func NewStatsDSink(addr string) (*StatsDSink, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("dial statsd address %q: %w", addr, err)
+	}
+	return &StatsDSink{addr: addr, conn: conn}, nil
+}
+
+func (s *StatsDSink) Send(events []Event) error {
+	var buf strings.Builder
+	for _, event := range events {
+		metric := fmt.Sprintf("analytics.%s.%s.%s",
+			sanitizeStatsDSegment(event.Type), sanitizeStatsDSegment(event.Category), sanitizeStatsDSegment(event.Action))
+		fmt.Fprintf(&buf, "%s:1|c\n", metric)
+	}
+
+	if _, err := s.conn.Write([]byte(buf.String())); err != nil {
+		return fmt.Errorf("write to statsd %s: %w", s.addr, err)
+	}
+	return nil
+}
+
+// sanitizeStatsDSegment replaces characters that would otherwise split a
+// StatsD metric name or line.
+func sanitizeStatsDSegment(segment string) string {
+	replacer := strings.NewReplacer(":", "_", "|", "_", "\n", "_", " ", "_")
+	if segment == "" {
+		return "unknown"
+	}
+	return replacer.Replace(segment)
+}
+
+// Forwarder batches events handed to it via Enqueue and periodically flushes
+// them to a Sink, retrying a failed flush with exponential backoff before
+// giving up on that batch.
+type Forwarder struct {
+	sink          Sink
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+
+	mutex    sync.Mutex
+	buffer   []Event
+	flushing bool
+
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewForwarder creates a Forwarder that flushes to sink whenever the
+// buffered batch reaches batchSize or flushInterval elapses, whichever comes
+// first.
+func NewForwarder(sink Sink, batchSize int, flushInterval time.Duration, maxRetries int) *Forwarder {
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+	if flushInterval <= 0 {
+		flushInterval = 10 * time.Second
+	}
+	if maxRetries <= 0 {
+		maxRetries = 3
+	}
+
+	return &Forwarder{
+		sink:          sink,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		maxRetries:    maxRetries,
+		stopChan:      make(chan struct{}),
+	}
+}
+
+// Start runs the periodic flush loop in the background until Stop is called.
+func (f *Forwarder) Start() {
+	go f.run()
+}
+
+// Stop ends the periodic flush loop and flushes any remaining buffered
+// events one last time. It waits for any flush already triggered via
+// triggerFlush to finish first, so the final flush doesn't race with one
+// still in flight.
+func (f *Forwarder) Stop() {
+	close(f.stopChan)
+	f.wg.Wait()
+	f.flush()
+}
+
+// Enqueue buffers event for the next flush, triggering an immediate flush if
+// the batch size has been reached.
+func (f *Forwarder) Enqueue(event Event) {
+	f.mutex.Lock()
+	f.buffer = append(f.buffer, event)
+	full := len(f.buffer) >= f.batchSize
+	f.mutex.Unlock()
+
+	if full {
+		f.triggerFlush()
+	}
+}
+
+func (f *Forwarder) run() {
+	ticker := time.NewTicker(f.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			f.triggerFlush()
+		case <-f.stopChan:
+			return
+		}
+	}
+}
+
+// triggerFlush runs flush on its own goroutine so a slow or unreachable sink
+// can't block the caller: Enqueue is called directly from
+// Analytics.consumeEvents, the single goroutine draining the analytics event
+// channel, and flush's blocking retry/backoff loop stalling that goroutine
+// would stop the channel from draining - exactly the backpressure/spillover
+// behavior meant only for a full channel, not a slow sink. At most one flush
+// runs at a time; if one is already in flight, this is a no-op, since that
+// flush will pick up everything buffered since it started.
+func (f *Forwarder) triggerFlush() {
+	f.mutex.Lock()
+	if f.flushing {
+		f.mutex.Unlock()
+		return
+	}
+	f.flushing = true
+	f.mutex.Unlock()
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		f.flush()
+
+		f.mutex.Lock()
+		f.flushing = false
+		f.mutex.Unlock()
+	}()
+}
+
+// flush sends the currently buffered events, retrying with exponential
+// backoff on failure. Events are dropped only after maxRetries is exhausted;
+// there's no further fallback, matching the webhook delivery pool's retry
+// behavior.
+func (f *Forwarder) flush() {
+	f.mutex.Lock()
+	if len(f.buffer) == 0 {
+		f.mutex.Unlock()
+		return
+	}
+	batch := f.buffer
+	f.buffer = nil
+	f.mutex.Unlock()
+
+	var err error
+	for attempt := 1; attempt <= f.maxRetries; attempt++ {
+		if err = f.sink.Send(batch); err == nil {
+			logrus.Debugf("[ANALYTICS] Forwarded %d events to external sink", len(batch))
+			return
+		}
+
+		if attempt < f.maxRetries {
+			delay := time.Duration(attempt) * time.Second
+			logrus.Warnf("[ANALYTICS] Forwarding attempt %d/%d failed, retrying in %s: %v", attempt, f.maxRetries, delay, err)
+			time.Sleep(delay)
+		}
+	}
+
+	logrus.Errorf("[ANALYTICS] Dropping %d events after %d failed forwarding attempts: %v", len(batch), f.maxRetries, err)
+}
+
+// newConfiguredForwarder builds a Forwarder from config, or returns nil if
+// forwarding is disabled or misconfigured.
+func newConfiguredForwarder() *Forwarder {
+	if !config.AnalyticsForwarderEnabled {
+		return nil
+	}
+
+	target := config.AnalyticsForwarderHTTPURL
+	if config.AnalyticsForwarderSink == "statsd" {
+		target = config.AnalyticsForwarderStatsDAddr
+	}
+	if target == "" {
+		logrus.Warn("[ANALYTICS] Forwarding is enabled but no sink target is configured, skipping")
+		return nil
+	}
+
+	sink, err := NewSink(config.AnalyticsForwarderSink, target)
+	if err != nil {
+		logrus.Errorf("[ANALYTICS] Failed to initialize forwarder sink: %v", err)
+		return nil
+	}
+
+	forwarder := NewForwarder(
+		sink,
+		config.AnalyticsForwarderBatchSize,
+		time.Duration(config.AnalyticsForwarderFlushInterval)*time.Second,
+		config.AnalyticsForwarderMaxRetries,
+	)
+	logrus.Infof("[ANALYTICS] Forwarding events to %s sink at %s", config.AnalyticsForwarderSink, target)
+	return forwarder
+}
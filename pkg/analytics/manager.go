@@ -3,8 +3,10 @@ package analytics
 import (
 	"encoding/json"
 	"fmt"
+	"math/rand"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,22 +15,56 @@ import (
 )
 
 type Event struct {
-	ID        string                 `json:"id"`
-	Type      string                 `json:"type"`
-	Category  string                 `json:"category"`
-	Action    string                 `json:"action"`
-	Data      map[string]interface{} `json:"data"`
-	Timestamp time.Time              `json:"timestamp"`
-	UserAgent string                 `json:"user_agent,omitempty"`
-	IP        string                 `json:"ip,omitempty"`
+	ID         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	Category   string                 `json:"category"`
+	Action     string                 `json:"action"`
+	Data       map[string]interface{} `json:"data"`
+	Timestamp  time.Time              `json:"timestamp"`
+	UserAgent  string                 `json:"user_agent,omitempty"`
+	IP         string                 `json:"ip,omitempty"`
+	SampleRate float64                `json:"sample_rate,omitempty"` // probability this event was kept; 1.0 (or unset) means unsampled
 }
 
 type Analytics struct {
-	dataPath string
-	events   []Event
-	mutex    sync.RWMutex
+	dataPath      string
+	events        []Event
+	samplingRates map[string]float64 // event type -> fraction of events to keep; missing type means unsampled
+	mutex         sync.RWMutex
+
+	// eventChan buffers events between TrackEvent (producer) and the
+	// background writer that appends them to a.events. When it's near full,
+	// enqueueEvent spills error/message events to disk rather than blocking
+	// the caller, and drops lower-priority api events instead.
+	eventChan         chan Event
+	spillPath         string
+	spillFile         *os.File
+	spillMutex        sync.Mutex
+	spillMetrics      SpillMetrics
+	spillMetricsMutex sync.RWMutex
+
+	// forwarder pushes tracked events to an external observability sink
+	// (HTTP collector or StatsD) in addition to the on-disk store. Nil when
+	// forwarding is disabled in config.
+	forwarder *Forwarder
 }
 
+// SpillMetrics reports how the analytics channel's backpressure handling has
+// behaved: how many events were written to the disk spill file, how many of
+// those were later recovered back into the channel, and how many low
+// priority events were dropped outright instead of being spilled.
+type SpillMetrics struct {
+	Spilled   int64 `json:"spilled"`
+	Recovered int64 `json:"recovered"`
+	Dropped   int64 `json:"dropped"`
+}
+
+const (
+	analyticsChannelCapacity = 1000
+	analyticsSpillThreshold  = 0.9 // fraction of capacity at which new events start spilling instead of enqueuing
+	analyticsDrainThreshold  = 0.5 // fraction of capacity below which spilled events are re-enqueued
+)
+
 type Report struct {
 	Period    string                 `json:"period"`
 	StartDate time.Time              `json:"start_date"`
@@ -44,6 +80,16 @@ func NewAnalytics() *Analytics {
 	analytics := &Analytics{
 		dataPath: dataPath,
 		events:   make([]Event, 0),
+		samplingRates: map[string]float64{
+			"api": 0.1, // high-volume API request events are sampled by default; messages and errors stay unsampled
+		},
+		eventChan: make(chan Event, analyticsChannelCapacity),
+		spillPath: filepath.Join(dataPath, "spill.jsonl"),
+		forwarder: newConfiguredForwarder(),
+	}
+
+	if analytics.forwarder != nil {
+		analytics.forwarder.Start()
 	}
 
 	// Load existing events
@@ -52,26 +98,274 @@ func NewAnalytics() *Analytics {
 	// Start periodic save
 	go analytics.startPeriodicSave()
 
+	// Start the background writer and spill drainer for the event channel
+	go analytics.consumeEvents()
+	go analytics.drainSpillPeriodically()
+
 	return analytics
 }
 
-// TrackEvent records a new analytics event
+// TrackEvent records a new analytics event. Event types with a configured
+// sampling rate below 1.0 (see SetSamplingRate) are randomly dropped, and the
+// rate applied is stored on kept events so reports can scale counts back up.
+// Kept events are handed to the background writer over a.eventChan rather
+// than appended directly; see enqueueEvent for backpressure handling.
 func (a *Analytics) TrackEvent(eventType, category, action string, data map[string]interface{}) {
-	a.mutex.Lock()
-	defer a.mutex.Unlock()
+	a.mutex.RLock()
+	rate := a.samplingRateLocked(eventType)
+	a.mutex.RUnlock()
+
+	if rate < 1.0 && rand.Float64() >= rate {
+		return
+	}
 
 	event := Event{
-		ID:        a.generateEventID(),
-		Type:      eventType,
-		Category:  category,
-		Action:    action,
-		Data:      data,
-		Timestamp: time.Now(),
+		ID:         a.generateEventID(),
+		Type:       eventType,
+		Category:   category,
+		Action:     action,
+		Data:       capEventData(eventType, data),
+		Timestamp:  time.Now(),
+		SampleRate: rate,
+	}
+
+	a.enqueueEvent(event)
+}
+
+// capEventData bounds data's serialized size to
+// config.AnalyticsMaxEventDataBytes, so a caller accidentally passing
+// something like a full message body doesn't bloat events.json and memory
+// unbounded. Oversized string fields are truncated to
+// config.AnalyticsMaxFieldStringBytes and a "truncated": true marker is added
+// rather than dropping the event outright, so the rest of its metadata stays
+// useful.
+func capEventData(eventType string, data map[string]interface{}) map[string]interface{} {
+	if len(data) == 0 {
+		return data
+	}
+
+	serialized, err := json.Marshal(data)
+	if err != nil || len(serialized) <= config.AnalyticsMaxEventDataBytes {
+		return data
+	}
+
+	truncated := make(map[string]interface{}, len(data)+1)
+	for key, value := range data {
+		if str, ok := value.(string); ok && len(str) > config.AnalyticsMaxFieldStringBytes {
+			truncated[key] = str[:config.AnalyticsMaxFieldStringBytes] + "...(truncated)"
+			continue
+		}
+		truncated[key] = value
+	}
+	truncated["truncated"] = true
+
+	logrus.Warnf("[ANALYTICS] Event %q data (%d bytes) exceeded cap of %d bytes, truncated large fields",
+		eventType, len(serialized), config.AnalyticsMaxEventDataBytes)
+
+	return truncated
+}
+
+// enqueueEvent hands event to the background writer. Once the channel is
+// analyticsSpillThreshold full, new events stop enqueuing directly: error and
+// message events are spilled to disk instead (never dropped), while lower
+// priority event types (e.g. api) are dropped outright rather than adding to
+// the pressure.
+func (a *Analytics) enqueueEvent(event Event) {
+	if len(a.eventChan) < int(float64(cap(a.eventChan))*analyticsSpillThreshold) {
+		select {
+		case a.eventChan <- event:
+			return
+		default:
+		}
+	}
+
+	if event.Type == "error" || event.Type == "message" {
+		a.spillEvent(event)
+		return
+	}
+
+	a.spillMetricsMutex.Lock()
+	a.spillMetrics.Dropped++
+	a.spillMetricsMutex.Unlock()
+	logrus.Warnf("[ANALYTICS] Dropping %s event, analytics channel is under backpressure", event.Type)
+}
+
+// consumeEvents is the sole writer to a.events, draining a.eventChan in the
+// background so TrackEvent callers never block on the append/lock.
+func (a *Analytics) consumeEvents() {
+	for event := range a.eventChan {
+		a.mutex.Lock()
+		a.events = append(a.events, event)
+		a.mutex.Unlock()
+
+		if a.forwarder != nil {
+			a.forwarder.Enqueue(event)
+		}
+
+		logrus.Debugf("[ANALYTICS] Tracked event: %s/%s/%s", event.Type, event.Category, event.Action)
+	}
+}
+
+// spillEvent appends event to the on-disk spill file, opening it on first
+// use. If the spill file itself can't be written, the event is dropped -
+// there's no further fallback.
+func (a *Analytics) spillEvent(event Event) {
+	a.spillMutex.Lock()
+	defer a.spillMutex.Unlock()
+
+	if a.spillFile == nil {
+		f, err := os.OpenFile(a.spillPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			logrus.Errorf("[ANALYTICS] Failed to open spill file, dropping %s event: %v", event.Type, err)
+			a.spillMetricsMutex.Lock()
+			a.spillMetrics.Dropped++
+			a.spillMetricsMutex.Unlock()
+			return
+		}
+		a.spillFile = f
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		logrus.Errorf("[ANALYTICS] Failed to marshal spilled event: %v", err)
+		return
+	}
+
+	if _, err := a.spillFile.Write(append(data, '\n')); err != nil {
+		logrus.Errorf("[ANALYTICS] Failed to write spilled event: %v", err)
+		return
 	}
 
-	a.events = append(a.events, event)
-	
-	logrus.Debugf("[ANALYTICS] Tracked event: %s/%s/%s", eventType, category, action)
+	a.spillMetricsMutex.Lock()
+	a.spillMetrics.Spilled++
+	a.spillMetricsMutex.Unlock()
+	logrus.Warnf("[ANALYTICS] Spilled %s event to disk under backpressure", event.Type)
+}
+
+// drainSpillPeriodically re-enqueues spilled events once channel pressure has
+// subsided, checking every few seconds.
+func (a *Analytics) drainSpillPeriodically() {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		a.drainSpill()
+	}
+}
+
+// drainSpill reads events back off the spill file and re-enqueues as many as
+// currently fit under analyticsDrainThreshold, leaving the rest on disk for
+// the next tick.
+func (a *Analytics) drainSpill() {
+	a.spillMutex.Lock()
+	defer a.spillMutex.Unlock()
+
+	if a.spillFile == nil {
+		return // nothing has been spilled since the last drain
+	}
+	if len(a.eventChan) >= int(float64(cap(a.eventChan))*analyticsDrainThreshold) {
+		return // still under pressure, try again next tick
+	}
+
+	if err := a.spillFile.Close(); err != nil {
+		logrus.Errorf("[ANALYTICS] Failed to close spill file for draining: %v", err)
+	}
+	a.spillFile = nil
+
+	data, err := os.ReadFile(a.spillPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logrus.Errorf("[ANALYTICS] Failed to read spill file: %v", err)
+		}
+		return
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	var recovered int64
+	var remaining []string
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			continue
+		}
+		select {
+		case a.eventChan <- event:
+			recovered++
+		default:
+			remaining = lines[i:]
+		}
+		if remaining != nil {
+			break
+		}
+	}
+
+	if err := os.Remove(a.spillPath); err != nil && !os.IsNotExist(err) {
+		logrus.Errorf("[ANALYTICS] Failed to remove drained spill file: %v", err)
+	}
+	if len(remaining) > 0 {
+		if f, err := os.OpenFile(a.spillPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err != nil {
+			logrus.Errorf("[ANALYTICS] Failed to persist undrained spill events: %v", err)
+		} else {
+			f.WriteString(strings.Join(remaining, "\n") + "\n")
+			a.spillFile = f
+		}
+	}
+
+	if recovered > 0 {
+		a.spillMetricsMutex.Lock()
+		a.spillMetrics.Recovered += recovered
+		a.spillMetricsMutex.Unlock()
+		logrus.Infof("[ANALYTICS] Recovered %d spilled analytics events", recovered)
+	}
+}
+
+// GetSpillMetrics returns a snapshot of the analytics channel's backpressure
+// handling: events spilled to disk, recovered back into the channel, and
+// dropped outright.
+func (a *Analytics) GetSpillMetrics() SpillMetrics {
+	a.spillMetricsMutex.RLock()
+	defer a.spillMetricsMutex.RUnlock()
+	return a.spillMetrics
+}
+
+// samplingRateLocked returns the configured sampling rate for eventType, or
+// 1.0 (unsampled) if none is configured. Callers must hold a.mutex.
+func (a *Analytics) samplingRateLocked(eventType string) float64 {
+	if rate, ok := a.samplingRates[eventType]; ok {
+		return rate
+	}
+	return 1.0
+}
+
+// SetSamplingRate configures the fraction (0 exclusive - 1 inclusive) of
+// eventType events that TrackEvent keeps; the rest are dropped. A rate of 1.0
+// disables sampling for that type.
+func (a *Analytics) SetSamplingRate(eventType string, rate float64) error {
+	if rate <= 0 || rate > 1 {
+		return fmt.Errorf("sampling rate must be greater than 0 and at most 1, got %v", rate)
+	}
+
+	a.mutex.Lock()
+	a.samplingRates[eventType] = rate
+	a.mutex.Unlock()
+
+	logrus.Infof("[ANALYTICS] Set sampling rate for %s events to %.2f%%", eventType, rate*100)
+	return nil
+}
+
+// GetSamplingRates returns a copy of the configured per-type sampling rates.
+func (a *Analytics) GetSamplingRates() map[string]float64 {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	rates := make(map[string]float64, len(a.samplingRates))
+	for eventType, rate := range a.samplingRates {
+		rates[eventType] = rate
+	}
+	return rates
 }
 
 // TrackMessageSent tracks when a message is sent
@@ -107,7 +401,7 @@ func (a *Analytics) TrackLogin(method string, success bool) {
 	if !success {
 		action = "failure"
 	}
-	
+
 	a.TrackEvent("auth", "login", action, map[string]interface{}{
 		"method": method,
 	})
@@ -119,21 +413,26 @@ func (a *Analytics) TrackError(errorType, message string, data map[string]interf
 		data = make(map[string]interface{})
 	}
 	data["error_message"] = message
-	
+
 	a.TrackEvent("error", errorType, "occurred", data)
 }
 
 // GenerateReport creates an analytics report for a given period
-func (a *Analytics) GenerateReport(period string, startDate, endDate time.Time) *Report {
+func (a *Analytics) GenerateReport(period string, startDate, endDate time.Time, filters map[string]interface{}) *Report {
 	a.mutex.RLock()
 	defer a.mutex.RUnlock()
 
-	// Filter events by date range
+	// Filter events by date range and, if given, by metadata: every key in
+	// filters must be present in event.Data with an equal value.
 	var filteredEvents []Event
 	for _, event := range a.events {
-		if event.Timestamp.After(startDate) && event.Timestamp.Before(endDate) {
-			filteredEvents = append(filteredEvents, event)
+		if !event.Timestamp.After(startDate) || !event.Timestamp.Before(endDate) {
+			continue
 		}
+		if !eventMatchesFilters(event, filters) {
+			continue
+		}
+		filteredEvents = append(filteredEvents, event)
 	}
 
 	report := &Report{
@@ -148,14 +447,26 @@ func (a *Analytics) GenerateReport(period string, startDate, endDate time.Time)
 	report.Summary["total_events"] = len(filteredEvents)
 	report.Summary["period_days"] = int(endDate.Sub(startDate).Hours() / 24)
 
-	// Count events by type
-	eventTypes := make(map[string]int)
+	// Count events by type, scaling sampled event types back up to an
+	// estimated true count (weight = 1 / SampleRate) instead of reporting
+	// the raw, undercounted number of stored events.
+	eventTypes := make(map[string]float64)
 	categories := make(map[string]int)
 	actions := make(map[string]int)
 	hourlyDistribution := make(map[int]int)
+	sampledTypes := make(map[string]bool)
 
 	for _, event := range filteredEvents {
-		eventTypes[event.Type]++
+		rate := event.SampleRate
+		if rate <= 0 {
+			rate = 1.0
+		}
+		weight := 1.0 / rate
+		if rate < 1.0 {
+			sampledTypes[event.Type] = true
+		}
+
+		eventTypes[event.Type] += weight
 		categories[event.Category]++
 		actions[event.Action]++
 		hourlyDistribution[event.Timestamp.Hour()]++
@@ -166,6 +477,15 @@ func (a *Analytics) GenerateReport(period string, startDate, endDate time.Time)
 	report.Details["actions"] = actions
 	report.Details["hourly_distribution"] = hourlyDistribution
 
+	if len(sampledTypes) > 0 {
+		sampled := make([]string, 0, len(sampledTypes))
+		for eventType := range sampledTypes {
+			sampled = append(sampled, eventType)
+		}
+		report.Summary["counts_are_estimated"] = true
+		report.Summary["sampled_event_types"] = sampled
+	}
+
 	// Message-specific analytics
 	messageStats := a.generateMessageStats(filteredEvents)
 	report.Details["messages"] = messageStats
@@ -186,24 +506,24 @@ func (a *Analytics) GetDailyReport() *Report {
 	now := time.Now()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	endOfDay := startOfDay.Add(24 * time.Hour)
-	
-	return a.GenerateReport("daily", startOfDay, endOfDay)
+
+	return a.GenerateReport("daily", startOfDay, endOfDay, nil)
 }
 
 // GetWeeklyReport generates a report for the past 7 days
 func (a *Analytics) GetWeeklyReport() *Report {
 	now := time.Now()
 	weekAgo := now.AddDate(0, 0, -7)
-	
-	return a.GenerateReport("weekly", weekAgo, now)
+
+	return a.GenerateReport("weekly", weekAgo, now, nil)
 }
 
 // GetMonthlyReport generates a report for the past 30 days
 func (a *Analytics) GetMonthlyReport() *Report {
 	now := time.Now()
 	monthAgo := now.AddDate(0, 0, -30)
-	
-	return a.GenerateReport("monthly", monthAgo, now)
+
+	return a.GenerateReport("monthly", monthAgo, now, nil)
 }
 
 // GetRealTimeStats returns current real-time statistics
@@ -235,6 +555,18 @@ func (a *Analytics) GetRealTimeStats() map[string]interface{} {
 
 // Private methods
 
+// eventMatchesFilters reports whether event.Data contains every key/value
+// pair in filters. A nil or empty filters map matches everything.
+func eventMatchesFilters(event Event, filters map[string]interface{}) bool {
+	for key, want := range filters {
+		got, exists := event.Data[key]
+		if !exists || fmt.Sprintf("%v", got) != fmt.Sprintf("%v", want) {
+			return false
+		}
+	}
+	return true
+}
+
 func (a *Analytics) generateEventID() string {
 	return fmt.Sprintf("evt_%d_%d", time.Now().UnixNano(), len(a.events))
 }
@@ -271,11 +603,11 @@ func (a *Analytics) generateMessageStats(events []Event) map[string]interface{}
 
 func (a *Analytics) generateAPIStats(events []Event) map[string]interface{} {
 	stats := map[string]interface{}{
-		"total_requests":   0,
-		"by_endpoint":      make(map[string]int),
-		"by_method":        make(map[string]int),
-		"by_status":        make(map[string]int),
-		"avg_duration_ms":  0.0,
+		"total_requests":  0,
+		"by_endpoint":     make(map[string]int),
+		"by_method":       make(map[string]int),
+		"by_status":       make(map[string]int),
+		"avg_duration_ms": 0.0,
 	}
 
 	totalDuration := int64(0)
@@ -333,9 +665,35 @@ func (a *Analytics) generateErrorStats(events []Event) map[string]interface{} {
 	return stats
 }
 
+// TrimEvents discards in-memory events older than olderThan, returning how
+// many were removed. It's meant for periodic housekeeping (see the queue
+// package's "cleanup" job) rather than the sampling/spill paths, which
+// already bound growth on the write side.
+func (a *Analytics) TrimEvents(olderThan time.Duration) int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	cutoff := time.Now().Add(-olderThan)
+	kept := a.events[:0]
+	removed := 0
+	for _, event := range a.events {
+		if event.Timestamp.Before(cutoff) {
+			removed++
+			continue
+		}
+		kept = append(kept, event)
+	}
+	a.events = kept
+
+	if removed > 0 {
+		logrus.Infof("[ANALYTICS] Trimmed %d events older than %s", removed, olderThan)
+	}
+	return removed
+}
+
 func (a *Analytics) loadEvents() {
 	filePath := filepath.Join(a.dataPath, "events.json")
-	
+
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		if !os.IsNotExist(err) {
@@ -357,7 +715,7 @@ func (a *Analytics) saveEvents() error {
 	defer a.mutex.RUnlock()
 
 	filePath := filepath.Join(a.dataPath, "events.json")
-	
+
 	data, err := json.MarshalIndent(a.events, "", "  ")
 	if err != nil {
 		return err
@@ -377,4 +735,4 @@ func (a *Analytics) startPeriodicSave() {
 			logrus.Debug("[ANALYTICS] Events saved successfully")
 		}
 	}
-}
\ No newline at end of file
+}
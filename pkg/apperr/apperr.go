@@ -0,0 +1,91 @@
+// Package apperr provides a shared taxonomy of typed application errors.
+//
+// Managers historically returned plain fmt.Errorf values, forcing the REST
+// layer to invent ad-hoc string codes like "QUEUE_ERROR" per handler and
+// leaving API consumers no reliable way to branch on failures other than
+// parsing the error message. AppError carries a stable machine-readable
+// code, a coarse category, and the HTTP status the REST layer should
+// respond with, so that mapping an error to a response is mechanical.
+package apperr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Category groups related codes for coarse-grained handling, e.g. deciding
+// whether a failure is retryable.
+type Category string
+
+const (
+	CategoryValidation Category = "validation"
+	CategoryNotFound   Category = "not_found"
+	CategoryConflict   Category = "conflict"
+	CategoryRateLimit  Category = "rate_limit"
+	CategoryInternal   Category = "internal"
+)
+
+// AppError is a typed error carrying a stable code, a category, and the
+// HTTP status the REST layer should respond with.
+type AppError struct {
+	Code       string
+	Category   Category
+	HTTPStatus int
+	Message    string
+	Err        error
+}
+
+func (e *AppError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// New creates an AppError with no wrapped cause.
+func New(code string, category Category, httpStatus int, message string) *AppError {
+	return &AppError{Code: code, Category: category, HTTPStatus: httpStatus, Message: message}
+}
+
+// Wrap creates an AppError that preserves an underlying cause for %w chains.
+func Wrap(code string, category Category, httpStatus int, message string, err error) *AppError {
+	return &AppError{Code: code, Category: category, HTTPStatus: httpStatus, Message: message, Err: err}
+}
+
+// NotFound builds a 404 AppError.
+func NotFound(code, message string) *AppError {
+	return New(code, CategoryNotFound, 404, message)
+}
+
+// Validation builds a 400 AppError for bad input.
+func Validation(code, message string) *AppError {
+	return New(code, CategoryValidation, 400, message)
+}
+
+// Conflict builds a 409 AppError for state conflicts (e.g. version mismatches).
+func Conflict(code, message string) *AppError {
+	return New(code, CategoryConflict, 409, message)
+}
+
+// RateLimited builds a 429 AppError.
+func RateLimited(code, message string) *AppError {
+	return New(code, CategoryRateLimit, 429, message)
+}
+
+// Internal builds a 500 AppError wrapping an underlying cause.
+func Internal(code, message string, err error) *AppError {
+	return Wrap(code, CategoryInternal, 500, message, err)
+}
+
+// As extracts an *AppError from err, unwrapping standard error chains.
+func As(err error) (*AppError, bool) {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
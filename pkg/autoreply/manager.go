@@ -0,0 +1,240 @@
+package autoreply
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/queue"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/templates"
+	"github.com/sirupsen/logrus"
+)
+
+// Rule maps inbound message text matching a condition to a template that
+// should be sent back in reply. Rules are evaluated in descending Priority
+// order (highest first, the same convention queue.Priority uses) and the
+// first enabled, matching rule wins.
+type Rule struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Operator and Value describe the match condition, evaluated against the
+	// inbound message text via templates.EvaluateCondition. Operator reuses
+	// templates.Condition's vocabulary: "eq", "ne", "gt", "lt", "contains"
+	// (the default) and "regex".
+	Operator   string `json:"operator,omitempty"`
+	Value      string `json:"value"`
+	TemplateID string `json:"template_id"`
+	Priority   int    `json:"priority"`
+	Enabled    bool   `json:"enabled"`
+	// IsFallback marks a rule that replies when no other enabled rule
+	// matches, instead of being matched against Operator/Value itself. At
+	// most one fallback is used per evaluation: the highest-priority
+	// enabled fallback rule.
+	IsFallback bool      `json:"is_fallback,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// matches reports whether text satisfies the rule's condition. Fallback
+// rules are never matched directly; Manager.findMatch routes to them
+// separately once no ordinary rule matches.
+func (r *Rule) matches(text string) bool {
+	operator := r.Operator
+	if operator == "" {
+		operator = "contains"
+	}
+
+	condition := templates.Condition{Field: "message", Operator: operator, Value: r.Value}
+	return templates.EvaluateCondition(condition, map[string]interface{}{"message": text})
+}
+
+// Manager stores auto-reply rules and evaluates them against inbound message
+// text. A match is rendered through templateMgr and handed to queueMgr as a
+// "send_message" job rather than sent inline, so an auto-reply competes for
+// delivery the same way any other queued message does.
+type Manager struct {
+	mutex       sync.RWMutex
+	rules       map[string]*Rule
+	templateMgr *templates.TemplateManager
+	queueMgr    *queue.QueueManager
+}
+
+// NewManager constructs an auto-reply rules engine backed by templateMgr for
+// rendering replies and queueMgr for delivering them.
+func NewManager(templateMgr *templates.TemplateManager, queueMgr *queue.QueueManager) *Manager {
+	return &Manager{
+		rules:       make(map[string]*Rule),
+		templateMgr: templateMgr,
+		queueMgr:    queueMgr,
+	}
+}
+
+// AddRule validates and stores a new rule, assigning it an ID if the caller
+// didn't supply one.
+func (m *Manager) AddRule(rule *Rule) error {
+	if rule.TemplateID == "" {
+		return fmt.Errorf("template_id is required")
+	}
+	if !rule.IsFallback && rule.Value == "" {
+		return fmt.Errorf("value is required unless is_fallback is set")
+	}
+	if _, err := m.templateMgr.GetTemplate(rule.TemplateID); err != nil {
+		return fmt.Errorf("template not found: %s", rule.TemplateID)
+	}
+
+	if rule.ID == "" {
+		rule.ID = m.generateRuleID()
+	}
+	rule.Enabled = true
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = time.Now()
+
+	m.mutex.Lock()
+	m.rules[rule.ID] = rule
+	m.mutex.Unlock()
+
+	logrus.Infof("[AUTOREPLY] Added rule %s (template: %s)", rule.ID, rule.TemplateID)
+	return nil
+}
+
+// UpdateRule applies a partial update: only keys present in updates are
+// changed, mirroring webhook.WebhookManager.UpdateEndpoint's explicit
+// allowlist of settable fields.
+func (m *Manager) UpdateRule(id string, updates map[string]interface{}) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	rule, exists := m.rules[id]
+	if !exists {
+		return fmt.Errorf("auto-reply rule not found: %s", id)
+	}
+
+	if name, ok := updates["name"].(string); ok {
+		rule.Name = name
+	}
+	if operator, ok := updates["operator"].(string); ok {
+		rule.Operator = operator
+	}
+	if value, ok := updates["value"].(string); ok {
+		rule.Value = value
+	}
+	if templateID, ok := updates["template_id"].(string); ok {
+		if _, err := m.templateMgr.GetTemplate(templateID); err != nil {
+			return fmt.Errorf("template not found: %s", templateID)
+		}
+		rule.TemplateID = templateID
+	}
+	if priority, ok := updates["priority"].(float64); ok {
+		rule.Priority = int(priority)
+	}
+	if enabled, ok := updates["enabled"].(bool); ok {
+		rule.Enabled = enabled
+	}
+	if isFallback, ok := updates["is_fallback"].(bool); ok {
+		rule.IsFallback = isFallback
+	}
+
+	rule.UpdatedAt = time.Now()
+	return nil
+}
+
+// RemoveRule deletes a rule.
+func (m *Manager) RemoveRule(id string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, exists := m.rules[id]; !exists {
+		return fmt.Errorf("auto-reply rule not found: %s", id)
+	}
+
+	delete(m.rules, id)
+	logrus.Infof("[AUTOREPLY] Removed rule %s", id)
+	return nil
+}
+
+// GetRule retrieves a rule by ID.
+func (m *Manager) GetRule(id string) (*Rule, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	rule, exists := m.rules[id]
+	if !exists {
+		return nil, fmt.Errorf("auto-reply rule not found: %s", id)
+	}
+	return rule, nil
+}
+
+// ListRules returns every rule, highest priority first.
+func (m *Manager) ListRules() []*Rule {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	rules := make([]*Rule, 0, len(m.rules))
+	for _, rule := range m.rules {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Priority > rules[j].Priority })
+	return rules
+}
+
+// findMatch returns the highest-priority enabled rule whose condition
+// matches text. If no ordinary rule matches, it falls back to the
+// highest-priority enabled rule with IsFallback set, if any.
+func (m *Manager) findMatch(text string) *Rule {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	rules := make([]*Rule, 0, len(m.rules))
+	for _, rule := range m.rules {
+		if rule.Enabled {
+			rules = append(rules, rule)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Priority > rules[j].Priority })
+
+	var fallback *Rule
+	for _, rule := range rules {
+		if rule.IsFallback {
+			if fallback == nil {
+				fallback = rule
+			}
+			continue
+		}
+		if rule.matches(text) {
+			return rule
+		}
+	}
+	return fallback
+}
+
+// EvaluateAndReply finds the rule matching text and, if one is found,
+// renders its template and enqueues the rendered text as a "send_message"
+// job addressed to recipientPhone. It returns the matched rule (nil if
+// nothing matched, including no fallback) so callers can log or fall back
+// to their own default behavior.
+func (m *Manager) EvaluateAndReply(text, recipientPhone string) (*Rule, error) {
+	rule := m.findMatch(text)
+	if rule == nil {
+		return nil, nil
+	}
+
+	rendered, err := m.templateMgr.RenderTemplate(rule.TemplateID, map[string]string{"message": text})
+	if err != nil {
+		return rule, fmt.Errorf("failed to render auto-reply template %s: %w", rule.TemplateID, err)
+	}
+
+	if _, err := m.queueMgr.AddJob("send_message", map[string]interface{}{
+		"phone":   recipientPhone,
+		"message": rendered,
+	}, queue.PriorityNormal); err != nil {
+		return rule, fmt.Errorf("failed to enqueue auto-reply: %w", err)
+	}
+
+	logrus.Infof("[AUTOREPLY] Matched rule %s, enqueued reply to %s", rule.ID, recipientPhone)
+	return rule, nil
+}
+
+func (m *Manager) generateRuleID() string {
+	return fmt.Sprintf("autoreply_%d", time.Now().UnixNano())
+}
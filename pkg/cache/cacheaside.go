@@ -0,0 +1,31 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// GetOrSet implements the cache-aside pattern for an arbitrary value type: it
+// first tries to read key from cache; on a miss it calls loader, caches the
+// result for expiration, and returns it. When the cache is disabled or not
+// initialized, Get always misses so loader runs on every call - callers
+// don't need to special-case that.
+func GetOrSet[T any](key string, expiration time.Duration, loader func() (T, error)) (T, error) {
+	var value T
+	if err := Get(key, &value); err == nil {
+		return value, nil
+	}
+
+	value, err := loader()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	if err := Set(key, value, expiration); err != nil {
+		logrus.Warnf("[CACHE] Failed to cache key %s: %v", key, err)
+	}
+
+	return value, nil
+}
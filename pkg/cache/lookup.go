@@ -0,0 +1,49 @@
+package cache
+
+import "time"
+
+const (
+	contactKeyPrefix = "contact:"
+	chatKeyPrefix    = "chat:"
+
+	// DefaultLookupTTL is used by GetContact/GetChat when ttl <= 0. Contact
+	// and chat metadata changes infrequently enough that a few minutes of
+	// staleness is an acceptable tradeoff for the hit rate on this hot path.
+	DefaultLookupTTL = 5 * time.Minute
+)
+
+// GetContact wraps GetOrSet with a contact-specific key namespace: it
+// returns the cached contact for jid, calling loader on a miss and
+// populating the cache for ttl (DefaultLookupTTL if ttl <= 0). The generic
+// type parameter lets callers pass whatever contact representation they
+// already use instead of this package owning a domain-specific struct.
+func GetContact[T any](jid string, ttl time.Duration, loader func() (T, error)) (T, error) {
+	if ttl <= 0 {
+		ttl = DefaultLookupTTL
+	}
+	return GetOrSet(contactKeyPrefix+jid, ttl, loader)
+}
+
+// InvalidateContact evicts jid's cached contact. Call this from contact-update
+// handling so the next GetContact call re-runs its loader instead of serving
+// stale data for up to ttl.
+func InvalidateContact(jid string) error {
+	return Delete(contactKeyPrefix + jid)
+}
+
+// GetChat wraps GetOrSet with a chat-specific key namespace: it returns the
+// cached chat metadata for jid, calling loader on a miss and populating the
+// cache for ttl (DefaultLookupTTL if ttl <= 0).
+func GetChat[T any](jid string, ttl time.Duration, loader func() (T, error)) (T, error) {
+	if ttl <= 0 {
+		ttl = DefaultLookupTTL
+	}
+	return GetOrSet(chatKeyPrefix+jid, ttl, loader)
+}
+
+// InvalidateChat evicts jid's cached chat metadata. Call this from chat-update
+// handling so the next GetChat call re-runs its loader instead of serving
+// stale data for up to ttl.
+func InvalidateChat(jid string) error {
+	return Delete(chatKeyPrefix + jid)
+}
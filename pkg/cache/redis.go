@@ -13,10 +13,11 @@ import (
 )
 
 type RedisManager struct {
-	client   *redis.Client
-	enabled  bool
-	prefix   string
-	ctx      context.Context
+	client            *redis.Client
+	enabled           bool
+	prefix            string
+	ctx               context.Context
+	statsIntervalSecs int
 }
 
 type CacheConfig struct {
@@ -27,18 +28,23 @@ type CacheConfig struct {
 	DB       int    `json:"db"`
 	Prefix   string `json:"prefix"`
 	URL      string `json:"url"` // For Upstash Redis URL
+
+	// StatsIntervalSeconds controls how often the periodic key-count
+	// collector runs (see startStatsCollection). 0 uses the default of 30
+	// seconds; a negative value disables the collector entirely.
+	StatsIntervalSeconds int `json:"stats_interval_seconds"`
 }
 
 type CacheStats struct {
-	Connected       bool          `json:"connected"`
-	TotalKeys       int64         `json:"total_keys"`
-	UsedMemory      string        `json:"used_memory"`
-	HitRate         float64       `json:"hit_rate"`
-	MissRate        float64       `json:"miss_rate"`
-	TotalHits       int64         `json:"total_hits"`
-	TotalMisses     int64         `json:"total_misses"`
-	TotalOperations int64         `json:"total_operations"`
-	LastUpdated     time.Time     `json:"last_updated"`
+	Connected       bool      `json:"connected"`
+	TotalKeys       int64     `json:"total_keys"`
+	UsedMemory      string    `json:"used_memory"`
+	HitRate         float64   `json:"hit_rate"`
+	MissRate        float64   `json:"miss_rate"`
+	TotalHits       int64     `json:"total_hits"`
+	TotalMisses     int64     `json:"total_misses"`
+	TotalOperations int64     `json:"total_operations"`
+	LastUpdated     time.Time `json:"last_updated"`
 }
 
 var (
@@ -50,11 +56,12 @@ var (
 
 func NewRedisManager(config CacheConfig) *RedisManager {
 	ctx := context.Background()
-	
+
 	rm := &RedisManager{
-		enabled: config.Enabled,
-		prefix:  config.Prefix,
-		ctx:     ctx,
+		enabled:           config.Enabled,
+		prefix:            config.Prefix,
+		ctx:               ctx,
+		statsIntervalSecs: config.StatsIntervalSeconds,
 	}
 
 	if !config.Enabled {
@@ -65,10 +72,10 @@ func NewRedisManager(config CacheConfig) *RedisManager {
 	// Try to connect to Redis with retries
 	var rdb *redis.Client
 	var err error
-	
+
 	for attempt := 1; attempt <= 3; attempt++ {
 		logrus.Infof("[CACHE] Attempting to connect to Redis (attempt %d/3)...", attempt)
-		
+
 		// Initialize Redis client - prioritize URL for cloud Redis like Upstash
 		if config.URL != "" {
 			// Use Redis URL (for Upstash or other cloud Redis)
@@ -95,21 +102,21 @@ func NewRedisManager(config CacheConfig) *RedisManager {
 
 		// Test connection with timeout
 		pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second) // Increased timeout for cloud Redis
-		
+
 		logrus.Infof("[CACHE] Testing Redis connection (attempt %d)...", attempt)
 		_, err = rdb.Ping(pingCtx).Result()
 		cancel()
-		
+
 		if err == nil {
 			logrus.Infof("[CACHE] Successfully connected to Redis on attempt %d", attempt)
 			break
 		}
-		
+
 		logrus.Warnf("[CACHE] Redis connection attempt %d failed: %v", attempt, err)
-		
+
 		// Close the failed connection
 		rdb.Close()
-		
+
 		if attempt < 3 {
 			// Wait before retrying with exponential backoff
 			waitTime := time.Duration(attempt) * 3 * time.Second
@@ -129,8 +136,10 @@ func NewRedisManager(config CacheConfig) *RedisManager {
 	rm.client = rdb
 	globalRedisManager = rm
 
-	// Start stats collection
-	go rm.startStatsCollection()
+	// Start stats collection unless explicitly disabled
+	if rm.statsIntervalSecs >= 0 {
+		go rm.startStatsCollection()
+	}
 
 	logrus.Info("[CACHE] Redis cache initialized successfully")
 	return rm
@@ -144,7 +153,7 @@ func (rm *RedisManager) Set(key string, value interface{}, expiration time.Durat
 	}
 
 	fullKey := rm.getFullKey(key)
-	
+
 	// Serialize value to JSON
 	data, err := json.Marshal(value)
 	if err != nil {
@@ -171,7 +180,7 @@ func (rm *RedisManager) Get(key string, dest interface{}) error {
 	}
 
 	fullKey := rm.getFullKey(key)
-	
+
 	data, err := rm.client.Get(rm.ctx, fullKey).Result()
 	if err != nil {
 		if err == redis.Nil {
@@ -195,6 +204,177 @@ func (rm *RedisManager) Get(key string, dest interface{}) error {
 	return nil
 }
 
+// VersionedValue wraps a cached value with a monotonically increasing
+// version so callers can implement optimistic concurrency control.
+type VersionedValue struct {
+	Version int64           `json:"version"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// SetVersioned stores a value along with a version number, but only if the
+// key's current version matches expectedVersion (use 0 to require the key
+// not exist yet). On success it returns the new version. On a version
+// mismatch it returns an error so the caller can re-read and retry.
+func (rm *RedisManager) SetVersioned(key string, value interface{}, expectedVersion int64, expiration time.Duration) (int64, error) {
+	if !rm.enabled {
+		return 0, fmt.Errorf("redis cache is disabled")
+	}
+
+	fullKey := rm.getFullKey(key)
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	newVersion := expectedVersion + 1
+	entry := VersionedValue{Version: newVersion, Value: data}
+	entryData, err := json.Marshal(entry)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal versioned entry: %w", err)
+	}
+
+	txErr := rm.client.Watch(rm.ctx, func(tx *redis.Tx) error {
+		current, getErr := tx.Get(rm.ctx, fullKey).Result()
+		if getErr != nil && getErr != redis.Nil {
+			return fmt.Errorf("failed to read current version: %w", getErr)
+		}
+
+		currentVersion := int64(0)
+		if getErr == nil {
+			var currentEntry VersionedValue
+			if unmarshalErr := json.Unmarshal([]byte(current), &currentEntry); unmarshalErr != nil {
+				return fmt.Errorf("key %s holds a value not written by SetVersioned: %w", key, unmarshalErr)
+			}
+			currentVersion = currentEntry.Version
+		}
+
+		if currentVersion != expectedVersion {
+			return fmt.Errorf("version conflict: expected %d, found %d", expectedVersion, currentVersion)
+		}
+
+		_, execErr := tx.TxPipelined(rm.ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(rm.ctx, fullKey, entryData, expiration)
+			return nil
+		})
+		return execErr
+	}, fullKey)
+
+	if txErr != nil {
+		cacheStats.TotalMisses++
+		return 0, txErr
+	}
+
+	cacheStats.TotalOperations++
+	logrus.Debugf("[CACHE] Set versioned key: %s (version %d)", key, newVersion)
+	return newVersion, nil
+}
+
+// GetVersioned retrieves a value previously stored with SetVersioned,
+// unmarshaling it into dest and returning its current version.
+func (rm *RedisManager) GetVersioned(key string, dest interface{}) (int64, error) {
+	if !rm.enabled {
+		return 0, fmt.Errorf("cache miss")
+	}
+
+	fullKey := rm.getFullKey(key)
+
+	data, err := rm.client.Get(rm.ctx, fullKey).Result()
+	if err != nil {
+		cacheStats.TotalMisses++
+		if err == redis.Nil {
+			return 0, fmt.Errorf("cache miss")
+		}
+		return 0, fmt.Errorf("failed to get cache: %w", err)
+	}
+
+	var entry VersionedValue
+	if err := json.Unmarshal([]byte(data), &entry); err != nil {
+		cacheStats.TotalMisses++
+		return 0, fmt.Errorf("failed to unmarshal versioned entry: %w", err)
+	}
+
+	if err := json.Unmarshal(entry.Value, dest); err != nil {
+		cacheStats.TotalMisses++
+		return 0, fmt.Errorf("failed to unmarshal value: %w", err)
+	}
+
+	cacheStats.TotalHits++
+	cacheStats.TotalOperations++
+	return entry.Version, nil
+}
+
+// CompareAndSwap atomically replaces the value stored under key with
+// newValue, but only if the value currently there marshals to the same JSON
+// as expected. Pass nil for expected to require that key doesn't exist yet.
+// It returns false, nil (not an error) on a mismatch, so callers doing
+// optimistic read-modify-write can treat "someone else got there first" as a
+// normal outcome to re-read and retry, without a separate lock.
+func (rm *RedisManager) CompareAndSwap(key string, expected, newValue interface{}, ttl time.Duration) (bool, error) {
+	if !rm.enabled {
+		return false, fmt.Errorf("redis cache is disabled")
+	}
+
+	fullKey := rm.getFullKey(key)
+
+	var expectedData []byte
+	if expected != nil {
+		data, err := json.Marshal(expected)
+		if err != nil {
+			return false, fmt.Errorf("failed to marshal expected value: %w", err)
+		}
+		expectedData = data
+	}
+
+	newData, err := json.Marshal(newValue)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal new value: %w", err)
+	}
+
+	swapped := false
+	txErr := rm.client.Watch(rm.ctx, func(tx *redis.Tx) error {
+		current, getErr := tx.Get(rm.ctx, fullKey).Result()
+		if getErr != nil && getErr != redis.Nil {
+			return fmt.Errorf("failed to read current value: %w", getErr)
+		}
+
+		var match bool
+		switch {
+		case getErr == redis.Nil:
+			match = expected == nil
+		case expected == nil:
+			match = false
+		default:
+			match = current == string(expectedData)
+		}
+
+		if !match {
+			return nil
+		}
+
+		_, execErr := tx.TxPipelined(rm.ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(rm.ctx, fullKey, newData, ttl)
+			return nil
+		})
+		if execErr != nil {
+			return execErr
+		}
+		swapped = true
+		return nil
+	}, fullKey)
+
+	if txErr != nil {
+		cacheStats.TotalMisses++
+		return false, txErr
+	}
+
+	cacheStats.TotalOperations++
+	if swapped {
+		logrus.Debugf("[CACHE] Compare-and-swap succeeded for key: %s", key)
+	}
+	return swapped, nil
+}
+
 // Delete removes a key from Redis
 func (rm *RedisManager) Delete(key string) error {
 	if !rm.enabled {
@@ -202,7 +382,7 @@ func (rm *RedisManager) Delete(key string) error {
 	}
 
 	fullKey := rm.getFullKey(key)
-	
+
 	err := rm.client.Del(rm.ctx, fullKey).Err()
 	if err != nil {
 		return fmt.Errorf("failed to delete cache: %w", err)
@@ -220,7 +400,7 @@ func (rm *RedisManager) Exists(key string) (bool, error) {
 	}
 
 	fullKey := rm.getFullKey(key)
-	
+
 	count, err := rm.client.Exists(rm.ctx, fullKey).Result()
 	if err != nil {
 		return false, fmt.Errorf("failed to check existence: %w", err)
@@ -237,7 +417,7 @@ func (rm *RedisManager) SetHash(key string, fields map[string]interface{}, expir
 	}
 
 	fullKey := rm.getFullKey(key)
-	
+
 	// Convert values to strings
 	stringFields := make(map[string]interface{})
 	for k, v := range fields {
@@ -270,7 +450,7 @@ func (rm *RedisManager) GetHash(key string) (map[string]string, error) {
 	}
 
 	fullKey := rm.getFullKey(key)
-	
+
 	result, err := rm.client.HGetAll(rm.ctx, fullKey).Result()
 	if err != nil {
 		cacheStats.TotalMisses++
@@ -295,7 +475,7 @@ func (rm *RedisManager) SetList(key string, values []interface{}, expiration tim
 	}
 
 	fullKey := rm.getFullKey(key)
-	
+
 	// Clear existing list
 	rm.client.Del(rm.ctx, fullKey)
 
@@ -324,7 +504,7 @@ func (rm *RedisManager) GetList(key string) ([]string, error) {
 	}
 
 	fullKey := rm.getFullKey(key)
-	
+
 	result, err := rm.client.LRange(rm.ctx, fullKey, 0, -1).Result()
 	if err != nil {
 		cacheStats.TotalMisses++
@@ -349,7 +529,7 @@ func (rm *RedisManager) Increment(key string, delta int64) (int64, error) {
 	}
 
 	fullKey := rm.getFullKey(key)
-	
+
 	result, err := rm.client.IncrBy(rm.ctx, fullKey, delta).Result()
 	if err != nil {
 		return 0, fmt.Errorf("failed to increment: %w", err)
@@ -367,7 +547,7 @@ func (rm *RedisManager) SetExpiration(key string, expiration time.Duration) erro
 	}
 
 	fullKey := rm.getFullKey(key)
-	
+
 	err := rm.client.Expire(rm.ctx, fullKey, expiration).Err()
 	if err != nil {
 		return fmt.Errorf("failed to set expiration: %w", err)
@@ -437,7 +617,7 @@ func (rm *RedisManager) ListKeys(pattern string) ([]string, error) {
 	}
 
 	fullPattern := rm.getFullKey(pattern)
-	
+
 	keys, err := rm.client.Keys(rm.ctx, fullPattern).Result()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list keys: %w", err)
@@ -479,7 +659,7 @@ func (rm *RedisManager) TestConnection() map[string]interface{} {
 	defer cancel()
 
 	_, err := rm.client.Ping(ctx).Result()
-	
+
 	if err == nil {
 		return map[string]interface{}{
 			"enabled":   true,
@@ -506,7 +686,12 @@ func (rm *RedisManager) getFullKey(key string) string {
 }
 
 func (rm *RedisManager) startStatsCollection() {
-	ticker := time.NewTicker(30 * time.Second)
+	interval := time.Duration(rm.statsIntervalSecs) * time.Second
+	if rm.statsIntervalSecs == 0 {
+		interval = 30 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for range ticker.C {
@@ -525,13 +710,53 @@ func (rm *RedisManager) startStatsCollection() {
 			cacheStats.UsedMemory = usedMemoryIdx
 		}
 
-		// Count keys with our prefix
-		pattern := rm.getFullKey("*")
-		keys, err := rm.client.Keys(rm.ctx, pattern).Result()
+		// Count keys with our prefix using SCAN so this doesn't block Redis
+		// the way a KEYS command would on a large keyspace.
+		count, err := rm.CountKeys("*")
 		if err == nil {
-			cacheStats.TotalKeys = int64(len(keys))
+			cacheStats.TotalKeys = count
+		}
+	}
+}
+
+// CountKeys counts keys matching pattern (relative to this manager's
+// prefix) using SCAN instead of KEYS, so counting doesn't block Redis while
+// iterating a large keyspace.
+func (rm *RedisManager) CountKeys(pattern string) (int64, error) {
+	if !rm.enabled {
+		return 0, fmt.Errorf("redis cache is disabled")
+	}
+
+	fullPattern := rm.getFullKey(pattern)
+
+	var count int64
+	var cursor uint64
+	for {
+		keys, nextCursor, err := rm.client.Scan(rm.ctx, cursor, fullPattern, 1000).Result()
+		if err != nil {
+			return 0, fmt.Errorf("failed to scan keys: %w", err)
+		}
+
+		count += int64(len(keys))
+		cursor = nextCursor
+		if cursor == 0 {
+			break
 		}
 	}
+
+	return count, nil
+}
+
+// ResetStats zeroes out the accumulated hit/miss/operation counters, e.g.
+// between test runs or after a deploy, without touching the actual cached
+// data in Redis.
+func (rm *RedisManager) ResetStats() {
+	cacheStats.TotalHits = 0
+	cacheStats.TotalMisses = 0
+	cacheStats.TotalOperations = 0
+	cacheStats.HitRate = 0
+	cacheStats.MissRate = 0
+	cacheStats.LastUpdated = time.Now()
 }
 
 func findInfoValue(info, key string) string {
@@ -612,4 +837,20 @@ func ListKeys(pattern string) ([]string, error) {
 		return nil, fmt.Errorf("cache not initialized")
 	}
 	return globalRedisManager.ListKeys(pattern)
-}
\ No newline at end of file
+}
+
+// CountKeys counts keys matching a pattern using SCAN
+func CountKeys(pattern string) (int64, error) {
+	if globalRedisManager == nil {
+		return 0, fmt.Errorf("cache not initialized")
+	}
+	return globalRedisManager.CountKeys(pattern)
+}
+
+// ResetStats zeroes out the global cache hit/miss/operation counters
+func ResetStats() {
+	if globalRedisManager == nil {
+		return
+	}
+	globalRedisManager.ResetStats()
+}
@@ -0,0 +1,530 @@
+package campaign
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/apperr"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/queue"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/templates"
+	"github.com/sirupsen/logrus"
+)
+
+// Status is the lifecycle state of a Campaign.
+type Status string
+
+const (
+	StatusDraft     Status = "draft"
+	StatusRunning   Status = "running"
+	StatusPaused    Status = "paused"
+	StatusCanceled  Status = "canceled"
+	StatusCompleted Status = "completed"
+)
+
+// RecipientStatus tracks one recipient's progress through delivery.
+type RecipientStatus string
+
+const (
+	RecipientPending RecipientStatus = "pending"
+	RecipientQueued  RecipientStatus = "queued"
+	RecipientSent    RecipientStatus = "sent"
+	RecipientFailed  RecipientStatus = "failed"
+)
+
+// Recipient is one campaign target: a phone number plus the per-recipient
+// variables its message is rendered with.
+type Recipient struct {
+	Phone     string            `json:"phone"`
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// recipientState is a Recipient plus its delivery progress within a
+// Campaign.
+type recipientState struct {
+	Recipient
+	Status RecipientStatus `json:"status"`
+	JobID  string          `json:"job_id,omitempty"`
+	Error  string          `json:"error,omitempty"`
+	SentAt *time.Time      `json:"sent_at,omitempty"`
+}
+
+// Campaign is a templated bulk-messaging run: one template rendered once per
+// recipient and enqueued for delivery, paced to RatePerMinute.
+type Campaign struct {
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	TemplateID    string            `json:"template_id"`
+	Recipients    []*recipientState `json:"recipients"`
+	RatePerMinute int               `json:"rate_per_minute"`
+	Priority      queue.Priority    `json:"priority"`
+	Status        Status            `json:"status"`
+	// NextIndex is the cursor into Recipients the pump resumes from, so a
+	// paused-then-resumed (or restarted) campaign doesn't re-scan recipients
+	// it already dispatched.
+	NextIndex   int        `json:"next_index"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// Progress is the point-in-time delivery summary derived from a Campaign's
+// recipient statuses and rate, as returned alongside it by GetCampaign.
+type Progress struct {
+	Total     int        `json:"total"`
+	Sent      int        `json:"sent"`
+	Failed    int        `json:"failed"`
+	Remaining int        `json:"remaining"`
+	ETA       *time.Time `json:"eta,omitempty"`
+}
+
+// Snapshot is a Campaign plus its current Progress, the shape GetCampaign
+// and ListCampaigns hand back to callers.
+type Snapshot struct {
+	*Campaign
+	Progress Progress `json:"progress"`
+}
+
+// Manager creates and runs Campaigns: rendering each recipient's message
+// through templates and pacing delivery through queue, mirroring how
+// autoreply.Manager and reportschedule.Manager turn templates+queue into
+// delivered messages.
+type Manager struct {
+	mutex       sync.RWMutex
+	campaigns   map[string]*Campaign
+	dataPath    string
+	templateMgr *templates.TemplateManager
+	queueMgr    *queue.QueueManager
+
+	runnersMutex sync.Mutex
+	runners      map[string]chan struct{}
+}
+
+// NewManager constructs a campaign manager backed by templateMgr for
+// rendering and queueMgr for throttled delivery.
+func NewManager(templateMgr *templates.TemplateManager, queueMgr *queue.QueueManager) *Manager {
+	dataPath := filepath.Join(config.PathStorages, "campaigns")
+	os.MkdirAll(dataPath, 0755)
+
+	m := &Manager{
+		campaigns:   make(map[string]*Campaign),
+		dataPath:    dataPath,
+		templateMgr: templateMgr,
+		queueMgr:    queueMgr,
+		runners:     make(map[string]chan struct{}),
+	}
+
+	m.loadCampaigns()
+	queueMgr.OnJobEvent(m.handleJobEvent)
+
+	for _, c := range m.campaigns {
+		if c.Status == StatusRunning {
+			m.startPump(c)
+		}
+	}
+
+	return m
+}
+
+// CreateCampaign validates and stores a new campaign in draft status; call
+// StartCampaign to begin sending.
+func (m *Manager) CreateCampaign(name, templateID string, recipients []Recipient, ratePerMinute int, priority queue.Priority) (*Campaign, error) {
+	if name == "" {
+		return nil, apperr.Validation("CAMPAIGN_MISSING_NAME", "name is required")
+	}
+	if _, err := m.templateMgr.GetTemplate(templateID); err != nil {
+		return nil, apperr.Validation("CAMPAIGN_INVALID_TEMPLATE", fmt.Sprintf("template not found: %s", templateID))
+	}
+	if len(recipients) == 0 {
+		return nil, apperr.Validation("CAMPAIGN_NO_RECIPIENTS", "at least one recipient is required")
+	}
+	if ratePerMinute <= 0 {
+		return nil, apperr.Validation("CAMPAIGN_INVALID_RATE", "rate_per_minute must be positive")
+	}
+
+	states := make([]*recipientState, len(recipients))
+	for i, r := range recipients {
+		states[i] = &recipientState{Recipient: r, Status: RecipientPending}
+	}
+
+	c := &Campaign{
+		ID:            m.generateCampaignID(),
+		Name:          name,
+		TemplateID:    templateID,
+		Recipients:    states,
+		RatePerMinute: ratePerMinute,
+		Priority:      priority,
+		Status:        StatusDraft,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	m.mutex.Lock()
+	m.campaigns[c.ID] = c
+	m.mutex.Unlock()
+
+	if err := m.saveCampaign(c); err != nil {
+		return nil, fmt.Errorf("failed to persist campaign: %w", err)
+	}
+
+	logrus.Infof("[CAMPAIGN] Created campaign %s (%s) with %d recipients", c.ID, c.Name, len(c.Recipients))
+	return c, nil
+}
+
+// StartCampaign begins (or restarts, after StopCampaign wasn't called)
+// dispatching a draft or paused campaign's pending recipients.
+func (m *Manager) StartCampaign(id string) error {
+	c, err := m.getCampaign(id)
+	if err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	if c.Status == StatusRunning {
+		m.mutex.Unlock()
+		return apperr.Conflict("CAMPAIGN_ALREADY_RUNNING", fmt.Sprintf("campaign %s is already running", id))
+	}
+	if c.Status == StatusCompleted || c.Status == StatusCanceled {
+		m.mutex.Unlock()
+		return apperr.Conflict("CAMPAIGN_FINISHED", fmt.Sprintf("campaign %s has already %s", id, c.Status))
+	}
+	if c.StartedAt == nil {
+		now := time.Now()
+		c.StartedAt = &now
+	}
+	c.Status = StatusRunning
+	c.UpdatedAt = time.Now()
+	m.mutex.Unlock()
+
+	if err := m.saveCampaign(c); err != nil {
+		return fmt.Errorf("failed to persist campaign: %w", err)
+	}
+
+	m.startPump(c)
+	logrus.Infof("[CAMPAIGN] Started campaign %s", id)
+	return nil
+}
+
+// PauseCampaign stops dispatching further recipients; recipients already
+// enqueued keep processing. ResumeCampaign continues from where it left off.
+func (m *Manager) PauseCampaign(id string) error {
+	c, err := m.getCampaign(id)
+	if err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	if c.Status != StatusRunning {
+		m.mutex.Unlock()
+		return apperr.Conflict("CAMPAIGN_NOT_RUNNING", fmt.Sprintf("campaign %s is not running", id))
+	}
+	c.Status = StatusPaused
+	c.UpdatedAt = time.Now()
+	m.mutex.Unlock()
+
+	m.stopPump(id)
+	if err := m.saveCampaign(c); err != nil {
+		return fmt.Errorf("failed to persist campaign: %w", err)
+	}
+
+	logrus.Infof("[CAMPAIGN] Paused campaign %s", id)
+	return nil
+}
+
+// ResumeCampaign is StartCampaign restricted to campaigns already paused.
+func (m *Manager) ResumeCampaign(id string) error {
+	c, err := m.getCampaign(id)
+	if err != nil {
+		return err
+	}
+
+	m.mutex.RLock()
+	status := c.Status
+	m.mutex.RUnlock()
+	if status != StatusPaused {
+		return apperr.Conflict("CAMPAIGN_NOT_PAUSED", fmt.Sprintf("campaign %s is not paused", id))
+	}
+
+	return m.StartCampaign(id)
+}
+
+// CancelCampaign permanently stops a campaign; it cannot be resumed.
+func (m *Manager) CancelCampaign(id string) error {
+	c, err := m.getCampaign(id)
+	if err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	if c.Status == StatusCompleted || c.Status == StatusCanceled {
+		m.mutex.Unlock()
+		return apperr.Conflict("CAMPAIGN_FINISHED", fmt.Sprintf("campaign %s has already %s", id, c.Status))
+	}
+	c.Status = StatusCanceled
+	c.UpdatedAt = time.Now()
+	m.mutex.Unlock()
+
+	m.stopPump(id)
+	if err := m.saveCampaign(c); err != nil {
+		return fmt.Errorf("failed to persist campaign: %w", err)
+	}
+
+	logrus.Infof("[CAMPAIGN] Canceled campaign %s", id)
+	return nil
+}
+
+// GetCampaign retrieves a campaign along with its current progress.
+func (m *Manager) GetCampaign(id string) (*Snapshot, error) {
+	c, err := m.getCampaign(id)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return &Snapshot{Campaign: c, Progress: computeProgress(c)}, nil
+}
+
+// ListCampaigns returns every campaign along with its current progress.
+func (m *Manager) ListCampaigns() []*Snapshot {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	list := make([]*Snapshot, 0, len(m.campaigns))
+	for _, c := range m.campaigns {
+		list = append(list, &Snapshot{Campaign: c, Progress: computeProgress(c)})
+	}
+	return list
+}
+
+func (m *Manager) getCampaign(id string) (*Campaign, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	c, exists := m.campaigns[id]
+	if !exists {
+		return nil, apperr.NotFound("CAMPAIGN_NOT_FOUND", fmt.Sprintf("campaign not found: %s", id))
+	}
+	return c, nil
+}
+
+// computeProgress must be called with m.mutex held (for read or write).
+func computeProgress(c *Campaign) Progress {
+	progress := Progress{Total: len(c.Recipients)}
+
+	for _, r := range c.Recipients {
+		switch r.Status {
+		case RecipientSent:
+			progress.Sent++
+		case RecipientFailed:
+			progress.Failed++
+		}
+	}
+	progress.Remaining = progress.Total - progress.Sent - progress.Failed
+
+	if progress.Remaining > 0 && c.Status == StatusRunning && c.RatePerMinute > 0 {
+		minutesLeft := float64(progress.Remaining) / float64(c.RatePerMinute)
+		eta := time.Now().Add(time.Duration(minutesLeft * float64(time.Minute)))
+		progress.ETA = &eta
+	}
+
+	return progress
+}
+
+// startPump launches the goroutine that dispatches c's pending recipients at
+// its configured rate. It's a no-op if a pump is already running for c.
+func (m *Manager) startPump(c *Campaign) {
+	m.runnersMutex.Lock()
+	if _, running := m.runners[c.ID]; running {
+		m.runnersMutex.Unlock()
+		return
+	}
+	stop := make(chan struct{})
+	m.runners[c.ID] = stop
+	m.runnersMutex.Unlock()
+
+	interval := time.Minute / time.Duration(c.RatePerMinute)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if m.sendNext(c) {
+					return
+				}
+			}
+		}
+	}()
+}
+
+// stopPump signals id's pump goroutine to exit, if one is running.
+func (m *Manager) stopPump(id string) {
+	m.runnersMutex.Lock()
+	defer m.runnersMutex.Unlock()
+
+	if stop, running := m.runners[id]; running {
+		close(stop)
+		delete(m.runners, id)
+	}
+}
+
+// sendNext renders and enqueues the next pending recipient of c, marking it
+// completed and stopping the pump once there are none left. It returns true
+// once the pump should stop.
+func (m *Manager) sendNext(c *Campaign) bool {
+	m.mutex.Lock()
+	var next *recipientState
+	for c.NextIndex < len(c.Recipients) {
+		candidate := c.Recipients[c.NextIndex]
+		c.NextIndex++
+		if candidate.Status == RecipientPending {
+			next = candidate
+			break
+		}
+	}
+
+	if next == nil {
+		now := time.Now()
+		c.Status = StatusCompleted
+		c.CompletedAt = &now
+		c.UpdatedAt = now
+		m.mutex.Unlock()
+
+		if err := m.saveCampaign(c); err != nil {
+			logrus.Errorf("[CAMPAIGN] Failed to persist completed campaign %s: %v", c.ID, err)
+		}
+		m.stopPump(c.ID)
+		logrus.Infof("[CAMPAIGN] Campaign %s completed", c.ID)
+		return true
+	}
+	m.mutex.Unlock()
+
+	rendered, err := m.templateMgr.RenderTemplate(c.TemplateID, next.Variables)
+	if err != nil {
+		m.mutex.Lock()
+		next.Status = RecipientFailed
+		next.Error = fmt.Sprintf("failed to render template: %v", err)
+		c.UpdatedAt = time.Now()
+		m.mutex.Unlock()
+
+		if saveErr := m.saveCampaign(c); saveErr != nil {
+			logrus.Errorf("[CAMPAIGN] Failed to persist campaign %s: %v", c.ID, saveErr)
+		}
+		return false
+	}
+
+	job, err := m.queueMgr.AddJob("send_message", map[string]interface{}{
+		"phone":   next.Phone,
+		"message": rendered,
+	}, c.Priority)
+
+	m.mutex.Lock()
+	if err != nil {
+		next.Status = RecipientFailed
+		next.Error = err.Error()
+	} else {
+		next.Status = RecipientQueued
+		next.JobID = job.ID
+	}
+	c.UpdatedAt = time.Now()
+	m.mutex.Unlock()
+
+	if saveErr := m.saveCampaign(c); saveErr != nil {
+		logrus.Errorf("[CAMPAIGN] Failed to persist campaign %s: %v", c.ID, saveErr)
+	}
+	return false
+}
+
+// handleJobEvent updates the recipient owning a completed or failed job with
+// its final delivery outcome. Registered against queueMgr in NewManager.
+func (m *Manager) handleJobEvent(event queue.JobEvent) {
+	if event.Event != "completed" && event.Event != "failed" {
+		return
+	}
+
+	m.mutex.Lock()
+	var owner *Campaign
+	var recipient *recipientState
+	for _, c := range m.campaigns {
+		for _, r := range c.Recipients {
+			if r.JobID == event.JobID {
+				owner, recipient = c, r
+				break
+			}
+		}
+		if recipient != nil {
+			break
+		}
+	}
+	if recipient == nil {
+		m.mutex.Unlock()
+		return
+	}
+
+	now := time.Now()
+	if event.Event == "completed" {
+		recipient.Status = RecipientSent
+	} else {
+		recipient.Status = RecipientFailed
+		if job, err := m.queueMgr.GetJob(event.JobID); err == nil {
+			recipient.Error = job.Error
+		} else {
+			recipient.Error = "delivery failed"
+		}
+	}
+	recipient.SentAt = &now
+	owner.UpdatedAt = now
+	m.mutex.Unlock()
+
+	if err := m.saveCampaign(owner); err != nil {
+		logrus.Errorf("[CAMPAIGN] Failed to persist campaign %s after job event: %v", owner.ID, err)
+	}
+}
+
+func (m *Manager) saveCampaign(c *Campaign) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(m.dataPath, c.ID+".json"), data, 0644)
+}
+
+func (m *Manager) loadCampaigns() {
+	matches, err := filepath.Glob(filepath.Join(m.dataPath, "*.json"))
+	if err != nil {
+		logrus.Errorf("[CAMPAIGN] Failed to list campaign files: %v", err)
+		return
+	}
+
+	for _, path := range matches {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logrus.Errorf("[CAMPAIGN] Failed to read campaign file %s: %v", path, err)
+			continue
+		}
+
+		var c Campaign
+		if err := json.Unmarshal(data, &c); err != nil {
+			logrus.Errorf("[CAMPAIGN] Failed to parse campaign file %s: %v", path, err)
+			continue
+		}
+
+		m.campaigns[c.ID] = &c
+	}
+
+	logrus.Infof("[CAMPAIGN] Loaded %d campaigns", len(m.campaigns))
+}
+
+func (m *Manager) generateCampaignID() string {
+	return fmt.Sprintf("campaign_%d", time.Now().UnixNano())
+}
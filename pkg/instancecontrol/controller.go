@@ -0,0 +1,126 @@
+// Package instancecontrol backs the internal control surface a parent
+// InstanceManager uses to supervise a child instance process - readiness,
+// in-flight request count, and graceful drain - authenticated with a shared
+// secret instead of relying purely on process signals and the filesystem.
+package instancecontrol
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxTimestampSkew bounds how old (or how far in the future, to tolerate
+// clock drift between parent and child) a signed request's timestamp may be
+// before Verify rejects it. Without this, a captured (timestamp, signature)
+// pair would authenticate forever, turning a one-time credential leak into
+// permanent access to every endpoint in the control group.
+const maxTimestampSkew = 30 * time.Second
+
+// Controller tracks in-flight requests and drain state for the current
+// process and verifies HMAC-signed requests from the parent.
+type Controller struct {
+	secret    string
+	startedAt time.Time
+
+	mutex    sync.RWMutex
+	inFlight int
+	draining bool
+}
+
+// NewController creates a controller secured by secret. An empty secret
+// causes every signature check to fail, so an unconfigured child rejects all
+// control requests instead of silently trusting them.
+func NewController(secret string) *Controller {
+	return &Controller{
+		secret:    secret,
+		startedAt: time.Now(),
+	}
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of payload using the controller's
+// shared secret, for the parent side of the control channel to call.
+func Sign(secret, payload string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(payload))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Payload builds the canonical string signed for a control request, binding
+// the signature to the request's method and path (not just its timestamp)
+// so a signature captured for one endpoint can't be replayed against
+// another - e.g. a leaked signature for GET /ready can't be used to
+// authorize POST /stop.
+func Payload(method, path, timestamp string) string {
+	return method + "\n" + path + "\n" + timestamp
+}
+
+// Verify reports whether signature is a valid HMAC-SHA256, under the
+// controller's secret, of the method/path/timestamp triple identifying this
+// request. It also rejects timestamps older or newer than maxTimestampSkew,
+// so a captured (timestamp, signature) pair stops working shortly after it
+// was issued instead of remaining valid forever.
+func (c *Controller) Verify(method, path, timestamp, signature string) bool {
+	if c.secret == "" {
+		return false
+	}
+
+	unixSeconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := time.Since(time.Unix(unixSeconds, 0))
+	if age < -maxTimestampSkew || age > maxTimestampSkew {
+		return false
+	}
+
+	expected := Sign(c.secret, Payload(method, path, timestamp))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// BeginRequest marks one more request as in flight.
+func (c *Controller) BeginRequest() {
+	c.mutex.Lock()
+	c.inFlight++
+	c.mutex.Unlock()
+}
+
+// EndRequest marks an in-flight request as finished.
+func (c *Controller) EndRequest() {
+	c.mutex.Lock()
+	c.inFlight--
+	c.mutex.Unlock()
+}
+
+// InFlight returns the number of requests currently being served.
+func (c *Controller) InFlight() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.inFlight
+}
+
+// BeginDrain marks the instance as draining, so Ready starts reporting not
+// ready ahead of a graceful shutdown.
+func (c *Controller) BeginDrain() {
+	c.mutex.Lock()
+	c.draining = true
+	c.mutex.Unlock()
+}
+
+// IsDraining reports whether BeginDrain has been called.
+func (c *Controller) IsDraining() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.draining
+}
+
+// Uptime returns how long this controller (and thus the process) has been
+// running.
+func (c *Controller) Uptime() time.Duration {
+	return time.Since(c.startedAt)
+}
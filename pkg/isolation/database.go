@@ -8,31 +8,31 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/sirupsen/logrus"
-	_ "github.com/mattn/go-sqlite3"
 	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/sirupsen/logrus"
 )
 
 type DatabaseIsolationManager struct {
-	databases    map[string]*IsolatedDatabase
-	mutex        sync.RWMutex
-	basePath     string
-	dbType       string // "sqlite" or "postgres"
-	postgresURI  string // PostgreSQL connection string
+	databases   map[string]*IsolatedDatabase
+	mutex       sync.RWMutex
+	basePath    string
+	dbType      string // "sqlite" or "postgres"
+	postgresURI string // PostgreSQL connection string
 }
 
 type IsolatedDatabase struct {
-	InstanceID     string `json:"instance_id"`
-	DatabasePath   string `json:"database_path"`
-	KeysPath       string `json:"keys_path"`
-	DatabaseName   string `json:"database_name"`   // For PostgreSQL
-	KeysDBName     string `json:"keys_db_name"`    // For PostgreSQL
-	Connection     *sql.DB `json:"-"`
-	KeysConn       *sql.DB `json:"-"`
-	DBType         string `json:"db_type"`         // "sqlite" or "postgres"
-	ConnectionURI  string `json:"connection_uri"`  // Full connection string
-	KeysURI        string `json:"keys_uri"`        // Keys connection string
-	mutex          sync.RWMutex `json:"-"`
+	InstanceID    string       `json:"instance_id"`
+	DatabasePath  string       `json:"database_path"`
+	KeysPath      string       `json:"keys_path"`
+	DatabaseName  string       `json:"database_name"` // For PostgreSQL
+	KeysDBName    string       `json:"keys_db_name"`  // For PostgreSQL
+	Connection    *sql.DB      `json:"-"`
+	KeysConn      *sql.DB      `json:"-"`
+	DBType        string       `json:"db_type"`        // "sqlite" or "postgres"
+	ConnectionURI string       `json:"connection_uri"` // Full connection string
+	KeysURI       string       `json:"keys_uri"`       // Keys connection string
+	mutex         sync.RWMutex `json:"-"`
 }
 
 func NewDatabaseIsolationManager(basePath string) *DatabaseIsolationManager {
@@ -139,8 +139,16 @@ func (dim *DatabaseIsolationManager) createPostgresDatabase(instanceID string) (
 
 // createPostgresDatabaseSchema creates a new database in PostgreSQL
 func (dim *DatabaseIsolationManager) createPostgresDatabaseSchema(dbName string) error {
+	return createPostgresDatabaseAt(dim.postgresURI, dbName)
+}
+
+// createPostgresDatabaseAt creates dbName on an arbitrary PostgreSQL server,
+// rather than the manager's own configured dim.postgresURI. This lets
+// MigrateDatabase target a Postgres cluster the manager wasn't originally
+// configured with.
+func createPostgresDatabaseAt(baseURI, dbName string) error {
 	// Connect to PostgreSQL server (without specific database)
-	db, err := sql.Open("postgres", dim.postgresURI)
+	db, err := sql.Open("postgres", baseURI)
 	if err != nil {
 		return fmt.Errorf("failed to connect to PostgreSQL: %w", err)
 	}
@@ -167,8 +175,14 @@ func (dim *DatabaseIsolationManager) createPostgresDatabaseSchema(dbName string)
 
 // buildPostgresURI builds a PostgreSQL connection URI for a specific database
 func (dim *DatabaseIsolationManager) buildPostgresURI(dbName string) string {
-	// Parse the base URI and replace the database name
-	baseURI := dim.postgresURI
+	return buildPostgresURIForDB(dim.postgresURI, dbName)
+}
+
+// buildPostgresURIForDB builds a PostgreSQL connection URI for dbName against
+// an arbitrary base URI, rather than the manager's own configured
+// dim.postgresURI. This lets MigrateDatabase target a Postgres cluster the
+// manager wasn't originally configured with.
+func buildPostgresURIForDB(baseURI, dbName string) string {
 	if strings.Contains(baseURI, "?") {
 		// Has query parameters
 		parts := strings.Split(baseURI, "?")
@@ -249,8 +263,16 @@ func (dim *DatabaseIsolationManager) DeleteIsolatedDatabase(instanceID string) e
 
 // dropPostgresDatabase drops a PostgreSQL database
 func (dim *DatabaseIsolationManager) dropPostgresDatabase(dbName string) error {
+	return dropPostgresDatabaseAt(dim.postgresURI, dbName)
+}
+
+// dropPostgresDatabaseAt drops dbName on an arbitrary PostgreSQL server,
+// rather than the manager's own configured dim.postgresURI. This lets
+// MigrateDatabase clean up after itself on a Postgres cluster the manager
+// wasn't originally configured with.
+func dropPostgresDatabaseAt(baseURI, dbName string) error {
 	// Connect to PostgreSQL server (without specific database)
-	db, err := sql.Open("postgres", dim.postgresURI)
+	db, err := sql.Open("postgres", baseURI)
 	if err != nil {
 		return fmt.Errorf("failed to connect to PostgreSQL: %w", err)
 	}
@@ -276,6 +298,130 @@ func (dim *DatabaseIsolationManager) dropPostgresDatabase(dbName string) error {
 	return nil
 }
 
+// VacuumReport summarizes a VacuumDatabase run: on-disk size before and
+// after compaction for the main and keys databases.
+type VacuumReport struct {
+	InstanceID     string `json:"instance_id"`
+	DBType         string `json:"db_type"`
+	MainSizeBefore int64  `json:"main_size_before"`
+	MainSizeAfter  int64  `json:"main_size_after"`
+	KeysSizeBefore int64  `json:"keys_size_before"`
+	KeysSizeAfter  int64  `json:"keys_size_after"`
+}
+
+// VacuumDatabase compacts the isolated database for instanceID to reclaim
+// space left behind by deleted rows. SQLite instances get a WAL checkpoint
+// followed by VACUUM on both database files; PostgreSQL instances get
+// VACUUM (ANALYZE) on both databases. Returns a before/after size report.
+func (dim *DatabaseIsolationManager) VacuumDatabase(instanceID string) (*VacuumReport, error) {
+	db, err := dim.GetIsolatedDatabase(instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	report := &VacuumReport{InstanceID: instanceID, DBType: db.DBType}
+
+	switch db.DBType {
+	case "postgres":
+		if report.MainSizeBefore, err = dim.postgresDatabaseSize(db.DatabaseName); err != nil {
+			return nil, fmt.Errorf("failed to measure main database size: %w", err)
+		}
+		if report.KeysSizeBefore, err = dim.postgresDatabaseSize(db.KeysDBName); err != nil {
+			return nil, fmt.Errorf("failed to measure keys database size: %w", err)
+		}
+
+		if err := dim.vacuumPostgresDatabase(db.ConnectionURI); err != nil {
+			return nil, fmt.Errorf("failed to vacuum main database: %w", err)
+		}
+		if err := dim.vacuumPostgresDatabase(db.KeysURI); err != nil {
+			return nil, fmt.Errorf("failed to vacuum keys database: %w", err)
+		}
+
+		if report.MainSizeAfter, err = dim.postgresDatabaseSize(db.DatabaseName); err != nil {
+			return nil, fmt.Errorf("failed to measure main database size: %w", err)
+		}
+		if report.KeysSizeAfter, err = dim.postgresDatabaseSize(db.KeysDBName); err != nil {
+			return nil, fmt.Errorf("failed to measure keys database size: %w", err)
+		}
+
+	default: // sqlite
+		report.MainSizeBefore = fileSize(db.DatabasePath)
+		report.KeysSizeBefore = fileSize(db.KeysPath)
+
+		if err := dim.vacuumSQLiteFile(db.DatabasePath); err != nil {
+			return nil, fmt.Errorf("failed to vacuum main database: %w", err)
+		}
+		if err := dim.vacuumSQLiteFile(db.KeysPath); err != nil {
+			return nil, fmt.Errorf("failed to vacuum keys database: %w", err)
+		}
+
+		report.MainSizeAfter = fileSize(db.DatabasePath)
+		report.KeysSizeAfter = fileSize(db.KeysPath)
+	}
+
+	logrus.Infof("[DB_ISOLATION] Vacuumed %s database for instance %s: main %d -> %d bytes, keys %d -> %d bytes",
+		db.DBType, instanceID, report.MainSizeBefore, report.MainSizeAfter, report.KeysSizeBefore, report.KeysSizeAfter)
+
+	return report, nil
+}
+
+// vacuumSQLiteFile opens its own short-lived connection to path (rather than
+// reusing a long-lived pooled connection) since VACUUM rewrites the entire
+// file and shouldn't compete with other in-flight queries on it.
+func (dim *DatabaseIsolationManager) vacuumSQLiteFile(path string) error {
+	conn, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_foreign_keys=on", path))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return fmt.Errorf("wal checkpoint failed: %w", err)
+	}
+	if _, err := conn.Exec("VACUUM"); err != nil {
+		return fmt.Errorf("vacuum failed: %w", err)
+	}
+	return nil
+}
+
+func (dim *DatabaseIsolationManager) vacuumPostgresDatabase(connectionURI string) error {
+	conn, err := sql.Open("postgres", connectionURI)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Exec("VACUUM (ANALYZE)"); err != nil {
+		return fmt.Errorf("vacuum failed: %w", err)
+	}
+	return nil
+}
+
+func (dim *DatabaseIsolationManager) postgresDatabaseSize(dbName string) (int64, error) {
+	conn, err := sql.Open("postgres", dim.postgresURI)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	var size int64
+	if err := conn.QueryRow("SELECT pg_database_size($1)", dbName).Scan(&size); err != nil {
+		return 0, fmt.Errorf("failed to query database size: %w", err)
+	}
+	return size, nil
+}
+
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
 // BackupDatabase creates a backup of the isolated database
 func (dim *DatabaseIsolationManager) BackupDatabase(instanceID, backupPath string) error {
 	db, err := dim.GetIsolatedDatabase(instanceID)
@@ -345,6 +491,194 @@ func (dim *DatabaseIsolationManager) RestoreDatabase(instanceID, backupPath stri
 	return nil
 }
 
+// migratableTables lists, in dependency order, the tables copied by
+// MigrateDatabase. mainTables live in the main database, keysTables in the
+// keys database - the same split createBasicTables uses.
+var migratableTables = struct {
+	main []string
+	keys []string
+}{
+	main: []string{"instance_info", "sessions", "messages", "contacts"},
+	keys: []string{"encryption_keys", "session_keys"},
+}
+
+// MigrateDatabase moves a SQLite instance onto a PostgreSQL cluster at
+// targetURI: it creates the destination databases, copies every row of each
+// table from the SQLite main and keys databases into their PostgreSQL
+// counterparts, verifies the row counts match, and then repoints db at the
+// new PostgreSQL connections. The instance must be stopped before calling
+// this - it copies each table once and doesn't stream writes made while the
+// copy is in progress, so a live instance can lose rows written mid-migration.
+//
+// The original SQLite files are left on disk untouched; callers that want to
+// reclaim the space can remove them once they've confirmed the migration.
+func (dim *DatabaseIsolationManager) MigrateDatabase(instanceID string, targetURI string) error {
+	db, err := dim.GetIsolatedDatabase(instanceID)
+	if err != nil {
+		return err
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	if db.DBType != "sqlite" {
+		return fmt.Errorf("instance %s is not a SQLite database (already %s)", instanceID, db.DBType)
+	}
+
+	dbName := fmt.Sprintf("whatsapp_%s", strings.ReplaceAll(instanceID, "-", "_"))
+	keysDBName := fmt.Sprintf("keys_%s", strings.ReplaceAll(instanceID, "-", "_"))
+
+	if err := createPostgresDatabaseAt(targetURI, dbName); err != nil {
+		return fmt.Errorf("failed to create main database: %w", err)
+	}
+	if err := createPostgresDatabaseAt(targetURI, keysDBName); err != nil {
+		return fmt.Errorf("failed to create keys database: %w", err)
+	}
+
+	target := &IsolatedDatabase{
+		InstanceID:    instanceID,
+		DatabaseName:  dbName,
+		KeysDBName:    keysDBName,
+		DBType:        "postgres",
+		ConnectionURI: buildPostgresURIForDB(targetURI, dbName),
+		KeysURI:       buildPostgresURIForDB(targetURI, keysDBName),
+	}
+	if err := dim.initializeDatabase(target); err != nil {
+		return fmt.Errorf("failed to initialize target database: %w", err)
+	}
+
+	// From here on, db is left with its SQLite connections closed until
+	// either the migration succeeds (db is repointed at target) or the
+	// rollback below reopens them - so every remaining error path must go
+	// through it instead of returning directly, or db would be stuck with
+	// DBType "sqlite" and nil connections until the process restarts.
+	migrated := false
+	defer func() {
+		if migrated {
+			return
+		}
+		if err := dim.initializeDatabase(db); err != nil {
+			logrus.Errorf("[DB_ISOLATION] Failed to reopen SQLite connections for instance %s after aborted migration: %v", instanceID, err)
+		}
+		if target.Connection != nil {
+			target.Connection.Close()
+		}
+		if target.KeysConn != nil {
+			target.KeysConn.Close()
+		}
+		if err := dropPostgresDatabaseAt(targetURI, dbName); err != nil {
+			logrus.Errorf("[DB_ISOLATION] Failed to clean up orphaned database %s after aborted migration: %v", dbName, err)
+		}
+		if err := dropPostgresDatabaseAt(targetURI, keysDBName); err != nil {
+			logrus.Errorf("[DB_ISOLATION] Failed to clean up orphaned database %s after aborted migration: %v", keysDBName, err)
+		}
+	}()
+
+	// Close the SQLite connections before reading from the files directly,
+	// so a still-open pooled connection can't hold a lock that starves the
+	// copy's own reads.
+	if db.Connection != nil {
+		db.Connection.Close()
+		db.Connection = nil
+	}
+	if db.KeysConn != nil {
+		db.KeysConn.Close()
+		db.KeysConn = nil
+	}
+
+	sourceConn, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_foreign_keys=on&mode=ro", db.DatabasePath))
+	if err != nil {
+		return fmt.Errorf("failed to open source database: %w", err)
+	}
+	defer sourceConn.Close()
+
+	sourceKeysConn, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_foreign_keys=on&mode=ro", db.KeysPath))
+	if err != nil {
+		return fmt.Errorf("failed to open source keys database: %w", err)
+	}
+	defer sourceKeysConn.Close()
+
+	for _, table := range migratableTables.main {
+		if err := migrateTable(sourceConn, target.Connection, table); err != nil {
+			return fmt.Errorf("failed to migrate table %s: %w", table, err)
+		}
+	}
+	for _, table := range migratableTables.keys {
+		if err := migrateTable(sourceKeysConn, target.KeysConn, table); err != nil {
+			return fmt.Errorf("failed to migrate keys table %s: %w", table, err)
+		}
+	}
+
+	db.DatabaseName = target.DatabaseName
+	db.KeysDBName = target.KeysDBName
+	db.DBType = "postgres"
+	db.ConnectionURI = target.ConnectionURI
+	db.KeysURI = target.KeysURI
+	db.Connection = target.Connection
+	db.KeysConn = target.KeysConn
+	migrated = true
+
+	logrus.Infof("[DB_ISOLATION] Migrated instance %s from SQLite to PostgreSQL database %s", instanceID, dbName)
+	return nil
+}
+
+// migrateTable copies every row of table from src to dst using a
+// column-agnostic SELECT * / INSERT, then verifies the destination ended up
+// with the same row count as the source.
+func migrateTable(src, dst *sql.DB, table string) error {
+	rows, err := src.Query(fmt.Sprintf("SELECT * FROM %s", table))
+	if err != nil {
+		return fmt.Errorf("failed to read source rows: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("failed to read source columns: %w", err)
+	}
+
+	placeholders := make([]string, len(columns))
+	for i := range columns {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	insertSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		table, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+
+	var copied int64
+	values := make([]interface{}, len(columns))
+	scanArgs := make([]interface{}, len(columns))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(scanArgs...); err != nil {
+			return fmt.Errorf("failed to scan source row: %w", err)
+		}
+		if _, err := dst.Exec(insertSQL, values...); err != nil {
+			return fmt.Errorf("failed to insert row: %w", err)
+		}
+		copied++
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate source rows: %w", err)
+	}
+
+	var sourceCount int64
+	if err := src.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", table)).Scan(&sourceCount); err != nil {
+		return fmt.Errorf("failed to count source rows: %w", err)
+	}
+	var destCount int64
+	if err := dst.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", table)).Scan(&destCount); err != nil {
+		return fmt.Errorf("failed to count destination rows: %w", err)
+	}
+	if sourceCount != destCount {
+		return fmt.Errorf("row count mismatch: source has %d, destination has %d (copied %d)", sourceCount, destCount, copied)
+	}
+
+	return nil
+}
+
 // ListDatabases returns all isolated databases
 func (dim *DatabaseIsolationManager) ListDatabases() []*IsolatedDatabase {
 	dim.mutex.RLock()
@@ -566,4 +900,4 @@ func (dim *DatabaseIsolationManager) Stop() {
 	}
 
 	logrus.Info("[DB_ISOLATION] Database isolation manager stopped")
-}
\ No newline at end of file
+}
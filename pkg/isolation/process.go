@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"sync"
 	"syscall"
 	"time"
@@ -15,10 +16,11 @@ import (
 )
 
 type ProcessIsolationManager struct {
-	processes map[string]*IsolatedProcess
-	mutex     sync.RWMutex
-	ctx       context.Context
-	cancel    context.CancelFunc
+	processes              map[string]*IsolatedProcess
+	mutex                  sync.RWMutex
+	ctx                    context.Context
+	cancel                 context.CancelFunc
+	defaultGracefulTimeout time.Duration
 }
 
 type IsolatedProcess struct {
@@ -37,7 +39,25 @@ type IsolatedProcess struct {
 	LogPath     string            `json:"log_path"`
 	Resources   ResourceLimits    `json:"resources"`
 	Monitoring  ProcessMonitoring `json:"monitoring"`
-	mutex       sync.RWMutex      `json:"-"`
+	Security    SecurityIsolation `json:"security"`
+	// GracefulTimeout is how long StopProcess waits after the initial
+	// termination signal before escalating to a force kill.
+	GracefulTimeout time.Duration `json:"graceful_timeout"`
+	mutex           sync.RWMutex  `json:"-"`
+}
+
+// defaultGracefulTimeout is used when neither the manager's IsolationConfig
+// nor a process's own config specify a graceful-stop timeout.
+const defaultGracefulTimeout = 10 * time.Second
+
+// SecurityIsolation controls whether a process is started under a dedicated
+// uid/gid and, on Linux, in new namespaces. Currently only enforced on
+// Linux; other platforms silently skip it (see applySecurityIsolation).
+type SecurityIsolation struct {
+	Enabled       bool   `json:"enabled"`
+	UID           uint32 `json:"uid,omitempty"`
+	GID           uint32 `json:"gid,omitempty"`
+	NewNamespaces bool   `json:"new_namespaces,omitempty"` // unshare mount and PID namespaces (Linux only)
 }
 
 type ProcessStatus string
@@ -71,19 +91,38 @@ type IsolationConfig struct {
 	DefaultMemoryLimit   int           `json:"default_memory_limit_mb"`
 	DefaultCPULimit      float64       `json:"default_cpu_limit"`
 	DefaultTimeout       time.Duration `json:"default_timeout"`
-	MonitoringInterval   time.Duration `json:"monitoring_interval"`
-	AutoRestart          bool          `json:"auto_restart"`
-	MaxRestarts          int           `json:"max_restarts"`
-	PathStorages         string        `json:"path_storages"`
+	// DefaultGracefulTimeout is how long StopProcess waits after the initial
+	// termination signal before force-killing a process that didn't specify
+	// its own override at creation time. Falls back to defaultGracefulTimeout
+	// when zero.
+	DefaultGracefulTimeout time.Duration `json:"default_graceful_timeout"`
+	MonitoringInterval     time.Duration `json:"monitoring_interval"`
+	AutoRestart            bool          `json:"auto_restart"`
+	MaxRestarts            int           `json:"max_restarts"`
+	PathStorages           string        `json:"path_storages"`
+
+	// EnableSecurityIsolation runs child processes under a dedicated uid/gid
+	// (dropping privileges) and, if IsolationNewNamespaces is set, in new
+	// mount and PID namespaces. Linux only; ignored elsewhere.
+	EnableSecurityIsolation bool   `json:"enable_security_isolation"`
+	IsolationUID            uint32 `json:"isolation_uid,omitempty"`
+	IsolationGID            uint32 `json:"isolation_gid,omitempty"`
+	IsolationNewNamespaces  bool   `json:"isolation_new_namespaces,omitempty"`
 }
 
 func NewProcessIsolationManager(config IsolationConfig) *ProcessIsolationManager {
 	ctx, cancel := context.WithCancel(context.Background())
 
+	gracefulTimeout := config.DefaultGracefulTimeout
+	if gracefulTimeout <= 0 {
+		gracefulTimeout = defaultGracefulTimeout
+	}
+
 	pim := &ProcessIsolationManager{
-		processes: make(map[string]*IsolatedProcess),
-		ctx:       ctx,
-		cancel:    cancel,
+		processes:              make(map[string]*IsolatedProcess),
+		ctx:                    ctx,
+		cancel:                 cancel,
+		defaultGracefulTimeout: gracefulTimeout,
 	}
 
 	// Start monitoring
@@ -134,6 +173,17 @@ func (pim *ProcessIsolationManager) CreateIsolatedProcess(id, name, command stri
 		Monitoring: ProcessMonitoring{
 			LastCheck: time.Now(),
 		},
+		Security: SecurityIsolation{
+			Enabled:       config.EnableSecurityIsolation,
+			UID:           config.IsolationUID,
+			GID:           config.IsolationGID,
+			NewNamespaces: config.IsolationNewNamespaces,
+		},
+		GracefulTimeout: config.DefaultGracefulTimeout,
+	}
+
+	if process.GracefulTimeout <= 0 {
+		process.GracefulTimeout = pim.defaultGracefulTimeout
 	}
 
 	pim.processes[id] = process
@@ -188,11 +238,29 @@ func (pim *ProcessIsolationManager) StartProcess(id string) error {
 		return fmt.Errorf("failed to apply resource limits: %w", err)
 	}
 
+	// Apply uid/gid and namespace isolation where supported. Failing to apply
+	// it is not fatal - we fall back to starting the process without it.
+	securityApplied := false
+	if process.Security.Enabled {
+		if err := pim.applySecurityIsolation(cmd, process.Security); err != nil {
+			logrus.Warnf("[ISOLATION] Security isolation unavailable for %s, starting without it: %v", id, err)
+		} else {
+			securityApplied = true
+		}
+	}
+
 	// Start the process
 	if err := cmd.Start(); err != nil {
-		logFile.Close()
-		process.Status = ProcessStatusError
-		return fmt.Errorf("failed to start process: %w", err)
+		if securityApplied {
+			logrus.Warnf("[ISOLATION] Failed to start %s with security isolation applied, retrying without it: %v", id, err)
+			cmd.SysProcAttr = nil
+			err = cmd.Start()
+		}
+		if err != nil {
+			logFile.Close()
+			process.Status = ProcessStatusError
+			return fmt.Errorf("failed to start process: %w", err)
+		}
 	}
 
 	process.Process = cmd.Process
@@ -228,25 +296,25 @@ func (pim *ProcessIsolationManager) StopProcess(id string) error {
 	logrus.Infof("[ISOLATION] Stopping isolated process: %s", id)
 
 	if process.Process != nil {
-		// Try graceful shutdown first
-		if err := process.Process.Signal(os.Interrupt); err != nil {
-			// Force kill if graceful shutdown fails
-			process.Process.Kill()
+		timeout := process.GracefulTimeout
+		if timeout <= 0 {
+			timeout = pim.defaultGracefulTimeout
 		}
 
-		// Wait for process to exit with timeout
 		done := make(chan error, 1)
 		go func() {
 			_, err := process.Process.Wait()
 			done <- err
 		}()
 
+		pim.terminateGracefully(id, process.Process)
+
 		select {
 		case <-done:
-			// Process exited
-		case <-time.After(10 * time.Second):
-			// Timeout, force kill
-			process.Process.Kill()
+			logrus.Infof("[ISOLATION] Process %s exited gracefully", id)
+		case <-time.After(timeout):
+			logrus.Warnf("[ISOLATION] Process %s did not exit within %s, force killing", id, timeout)
+			pim.forceKill(id, process.Process)
 			<-done
 		}
 
@@ -261,6 +329,43 @@ func (pim *ProcessIsolationManager) StopProcess(id string) error {
 	return nil
 }
 
+// terminateGracefully sends the initial termination signal for the escalation
+// ladder (SIGTERM → wait → SIGKILL). Windows has no SIGTERM equivalent
+// reachable through os.Process.Signal, so it issues a non-forceful taskkill
+// instead of jumping straight to a bare Kill.
+func (pim *ProcessIsolationManager) terminateGracefully(id string, process *os.Process) {
+	if runtime.GOOS == "windows" {
+		logrus.Infof("[ISOLATION] Requesting graceful termination for %s (PID: %d) via taskkill", id, process.Pid)
+		if err := exec.Command("taskkill", "/pid", strconv.Itoa(process.Pid)).Run(); err != nil {
+			logrus.Warnf("[ISOLATION] Graceful taskkill failed for %s, will force kill on timeout: %v", id, err)
+		}
+		return
+	}
+
+	logrus.Infof("[ISOLATION] Sending SIGTERM to %s (PID: %d)", id, process.Pid)
+	if err := process.Signal(syscall.SIGTERM); err != nil {
+		logrus.Warnf("[ISOLATION] Failed to send SIGTERM to %s, force killing: %v", id, err)
+		pim.forceKill(id, process)
+	}
+}
+
+// forceKill escalates to an unconditional kill: SIGKILL on Unix, `taskkill
+// /F` on Windows.
+func (pim *ProcessIsolationManager) forceKill(id string, process *os.Process) {
+	if runtime.GOOS == "windows" {
+		logrus.Warnf("[ISOLATION] Force killing %s (PID: %d) via taskkill /F", id, process.Pid)
+		if err := exec.Command("taskkill", "/pid", strconv.Itoa(process.Pid), "/f").Run(); err != nil {
+			logrus.Warnf("[ISOLATION] taskkill /F failed for %s: %v", id, err)
+		}
+		return
+	}
+
+	logrus.Warnf("[ISOLATION] Sending SIGKILL to %s (PID: %d)", id, process.Pid)
+	if err := process.Kill(); err != nil {
+		logrus.Warnf("[ISOLATION] Failed to SIGKILL %s: %v", id, err)
+	}
+}
+
 // RestartProcess restarts an isolated process
 func (pim *ProcessIsolationManager) RestartProcess(id string) error {
 	process, exists := pim.processes[id]
@@ -376,6 +481,17 @@ func (pim *ProcessIsolationManager) applyUnixResourceLimits(cmd *exec.Cmd, limit
 	return nil
 }
 
+// applySecurityIsolation configures cmd to run under a dedicated uid/gid and,
+// if requested, in new namespaces. It only does anything on Linux; on other
+// platforms it logs and returns nil so callers degrade gracefully.
+func (pim *ProcessIsolationManager) applySecurityIsolation(cmd *exec.Cmd, security SecurityIsolation) error {
+	if runtime.GOOS != "linux" {
+		logrus.Warnf("[ISOLATION] Process uid/gid and namespace isolation is only supported on Linux, skipping on %s", runtime.GOOS)
+		return nil
+	}
+	return applyLinuxSecurityIsolation(cmd, security)
+}
+
 func (pim *ProcessIsolationManager) applyWindowsResourceLimits(cmd *exec.Cmd, limits ResourceLimits) error {
 	// Windows-specific resource limits would be implemented using Job Objects
 	// For simplicity, we'll just log the limits
@@ -0,0 +1,31 @@
+//go:build linux
+
+package isolation
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// applyLinuxSecurityIsolation configures cmd to run under a dedicated uid/gid
+// (dropping privileges) and, if requested, in new mount and PID namespaces.
+// A non-nil error means the caller must not rely on the isolation being
+// active; the process can still be started without it.
+func applyLinuxSecurityIsolation(cmd *exec.Cmd, security SecurityIsolation) error {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+
+	if security.UID != 0 || security.GID != 0 {
+		cmd.SysProcAttr.Credential = &syscall.Credential{
+			Uid: security.UID,
+			Gid: security.GID,
+		}
+	}
+
+	if security.NewNamespaces {
+		cmd.SysProcAttr.Cloneflags = syscall.CLONE_NEWNS | syscall.CLONE_NEWPID
+	}
+
+	return nil
+}
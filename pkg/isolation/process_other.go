@@ -0,0 +1,16 @@
+//go:build !linux
+
+package isolation
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// applyLinuxSecurityIsolation is unreachable on non-Linux platforms -
+// applySecurityIsolation checks runtime.GOOS before calling it - but the
+// error return documents why: dedicated uid/gid and namespace isolation via
+// syscall.SysProcAttr are Linux-specific.
+func applyLinuxSecurityIsolation(cmd *exec.Cmd, security SecurityIsolation) error {
+	return fmt.Errorf("process uid/gid and namespace isolation is only supported on Linux")
+}
@@ -1,13 +1,21 @@
 package isolation
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
 
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	"github.com/sirupsen/logrus"
 )
 
@@ -18,17 +26,17 @@ type SessionIsolationManager struct {
 }
 
 type IsolatedSession struct {
-	InstanceID    string                 `json:"instance_id"`
-	SessionPath   string                 `json:"session_path"`
-	QRCodePath    string                 `json:"qr_code_path"`
-	MediaPath     string                 `json:"media_path"`
-	StaticPath    string                 `json:"static_path"`
-	SessionData   map[string]interface{} `json:"session_data"`
-	LastUpdated   time.Time              `json:"last_updated"`
-	IsActive      bool                   `json:"is_active"`
-	ConnectionID  string                 `json:"connection_id"`
-	DeviceInfo    DeviceInfo             `json:"device_info"`
-	mutex         sync.RWMutex           `json:"-"`
+	InstanceID   string                 `json:"instance_id"`
+	SessionPath  string                 `json:"session_path"`
+	QRCodePath   string                 `json:"qr_code_path"`
+	MediaPath    string                 `json:"media_path"`
+	StaticPath   string                 `json:"static_path"`
+	SessionData  map[string]interface{} `json:"session_data"`
+	LastUpdated  time.Time              `json:"last_updated"`
+	IsActive     bool                   `json:"is_active"`
+	ConnectionID string                 `json:"connection_id"`
+	DeviceInfo   DeviceInfo             `json:"device_info"`
+	mutex        sync.RWMutex           `json:"-"`
 }
 
 type DeviceInfo struct {
@@ -162,7 +170,9 @@ func (sim *SessionIsolationManager) UpdateDeviceInfo(instanceID string, deviceIn
 	return sim.saveSessionData(session)
 }
 
-// BackupSession creates a backup of the session data
+// BackupSession creates a backup of the session data. session.json is copied
+// byte-for-byte, so whatever compression/encryption encodeSessionData applied
+// on write is carried into the backup unchanged - it's opaque to BackupSession.
 func (sim *SessionIsolationManager) BackupSession(instanceID, backupPath string) error {
 	session, err := sim.GetIsolatedSession(instanceID)
 	if err != nil {
@@ -193,7 +203,10 @@ func (sim *SessionIsolationManager) BackupSession(instanceID, backupPath string)
 	return nil
 }
 
-// RestoreSession restores session data from backup
+// RestoreSession restores session data from backup. The restored session.json
+// is decoded by loadSessionData below, which transparently reverses whatever
+// compression/encryption it was written with regardless of the current config
+// - only encryption needs the same key to still be configured.
 func (sim *SessionIsolationManager) RestoreSession(instanceID, backupPath string) error {
 	session, err := sim.GetIsolatedSession(instanceID)
 	if err != nil {
@@ -308,7 +321,12 @@ func (sim *SessionIsolationManager) saveSessionData(session *IsolatedSession) er
 		return fmt.Errorf("failed to marshal session data: %w", err)
 	}
 
-	return os.WriteFile(session.SessionPath, data, 0644)
+	encoded, err := encodeSessionData(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode session data: %w", err)
+	}
+
+	return os.WriteFile(session.SessionPath, encoded, 0644)
 }
 
 func (sim *SessionIsolationManager) loadSessionData(session *IsolatedSession) error {
@@ -321,7 +339,159 @@ func (sim *SessionIsolationManager) loadSessionData(session *IsolatedSession) er
 		return fmt.Errorf("failed to read session data: %w", err)
 	}
 
-	return json.Unmarshal(data, session)
+	decoded, err := decodeSessionData(data)
+	if err != nil {
+		return fmt.Errorf("failed to decode session data: %w", err)
+	}
+
+	return json.Unmarshal(decoded, session)
+}
+
+// sessionDataMagic prefixes an encoded session.json whenever compression or
+// encryption is applied, so loadSessionData can tell an encoded file apart
+// from the plain JSON this package wrote before either was introduced (and
+// from a file written while both were disabled) without needing a separate
+// on-disk flag.
+var sessionDataMagic = []byte("WSES1")
+
+const (
+	sessionFlagCompressed byte = 1 << iota
+	sessionFlagEncrypted
+)
+
+// encodeSessionData applies compression and/or encryption to plaintext JSON
+// according to the current config, gated behind SessionStorageCompressionEnabled
+// and SessionStorageEncryptionEnabled so existing plaintext session files keep
+// working until an operator opts in.
+func encodeSessionData(plaintext []byte) ([]byte, error) {
+	if !config.SessionStorageCompressionEnabled && !config.SessionStorageEncryptionEnabled {
+		return plaintext, nil
+	}
+
+	data := plaintext
+	var flags byte
+
+	if config.SessionStorageCompressionEnabled {
+		compressed, err := compressSessionBytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compress: %w", err)
+		}
+		data = compressed
+		flags |= sessionFlagCompressed
+	}
+
+	if config.SessionStorageEncryptionEnabled {
+		encrypted, err := encryptSessionBytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt: %w", err)
+		}
+		data = encrypted
+		flags |= sessionFlagEncrypted
+	}
+
+	envelope := make([]byte, 0, len(sessionDataMagic)+1+len(data))
+	envelope = append(envelope, sessionDataMagic...)
+	envelope = append(envelope, flags)
+	envelope = append(envelope, data...)
+	return envelope, nil
+}
+
+// decodeSessionData reverses encodeSessionData. Data without the magic prefix
+// is assumed to already be plain JSON (written before compression/encryption
+// were enabled) and is returned unchanged.
+func decodeSessionData(raw []byte) ([]byte, error) {
+	if !bytes.HasPrefix(raw, sessionDataMagic) {
+		return raw, nil
+	}
+
+	rest := raw[len(sessionDataMagic):]
+	if len(rest) < 1 {
+		return nil, fmt.Errorf("truncated session data envelope")
+	}
+	flags, data := rest[0], rest[1:]
+
+	if flags&sessionFlagEncrypted != 0 {
+		decrypted, err := decryptSessionBytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt: %w", err)
+		}
+		data = decrypted
+	}
+
+	if flags&sessionFlagCompressed != 0 {
+		decompressed, err := decompressSessionBytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress: %w", err)
+		}
+		data = decompressed
+	}
+
+	return data, nil
+}
+
+func compressSessionBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decompressSessionBytes(data []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	return io.ReadAll(gz)
+}
+
+// sessionEncryptionCipher builds the AES-GCM cipher used to protect session
+// data at rest, deriving a 32-byte key from the configured passphrase so
+// operators don't need to manage a raw key of the exact AES-256 length.
+func sessionEncryptionCipher() (cipher.AEAD, error) {
+	if config.SessionStorageEncryptionKey == "" {
+		return nil, fmt.Errorf("session storage encryption is enabled but no encryption key is configured")
+	}
+
+	key := sha256.Sum256([]byte(config.SessionStorageEncryptionKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encryptSessionBytes(plaintext []byte) ([]byte, error) {
+	gcm, err := sessionEncryptionCipher()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func decryptSessionBytes(ciphertext []byte) ([]byte, error) {
+	gcm, err := sessionEncryptionCipher()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, sealed, nil)
 }
 
 func copyDir(src, dst string) error {
@@ -358,4 +528,4 @@ func (sim *SessionIsolationManager) Stop() {
 	}
 
 	logrus.Info("[SESSION_ISOLATION] Session isolation manager stopped")
-}
\ No newline at end of file
+}
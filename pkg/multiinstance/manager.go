@@ -2,51 +2,114 @@ package multiinstance
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/analytics"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/instancecontrol"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/isolation"
-	"github.com/sirupsen/logrus"
 	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/sirupsen/logrus"
 )
 
 type InstanceManager struct {
-	instances         map[string]*WhatsAppInstance
-	isolationMgr      *isolation.ProcessIsolationManager
-	dbIsolationMgr    *isolation.DatabaseIsolationManager
+	instances           map[string]*WhatsAppInstance
+	isolationMgr        *isolation.ProcessIsolationManager
+	dbIsolationMgr      *isolation.DatabaseIsolationManager
 	sessionIsolationMgr *isolation.SessionIsolationManager
-	mutex             sync.RWMutex
-	basePath          string
-	ctx               context.Context
-	cancel            context.CancelFunc
-	isolationConfig   isolation.IsolationConfig
+	analytics           *analytics.Analytics
+	mutex               sync.RWMutex
+	basePath            string
+	ctx                 context.Context
+	cancel              context.CancelFunc
+	isolationConfig     isolation.IsolationConfig
 }
 
 type WhatsAppInstance struct {
-	ID          string            `json:"id"`
-	Name        string            `json:"name"`
-	Phone       string            `json:"phone"`
-	Status      InstanceStatus    `json:"status"`
-	Port        int               `json:"port"`
-	PID         int               `json:"pid"`
-	WorkingDir  string            `json:"working_dir"`
-	ConfigPath  string            `json:"config_path"`
-	LogPath     string            `json:"log_path"`
-	CreatedAt   time.Time         `json:"created_at"`
-	StartedAt   *time.Time        `json:"started_at,omitempty"`
-	LastSeen    *time.Time        `json:"last_seen,omitempty"`
-	Config      InstanceConfig    `json:"config"`
-	Process     *os.Process       `json:"-"`
-	Metadata    map[string]string `json:"metadata"`
-	mutex       sync.RWMutex      `json:"-"`
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Phone      string            `json:"phone"`
+	Status     InstanceStatus    `json:"status"`
+	Port       int               `json:"port"`
+	PID        int               `json:"pid"`
+	WorkingDir string            `json:"working_dir"`
+	ConfigPath string            `json:"config_path"`
+	LogPath    string            `json:"log_path"`
+	CreatedAt  time.Time         `json:"created_at"`
+	StartedAt  *time.Time        `json:"started_at,omitempty"`
+	LastSeen   *time.Time        `json:"last_seen,omitempty"`
+	Config     InstanceConfig    `json:"config"`
+	Process    *os.Process       `json:"-"`
+	Metadata   map[string]string `json:"metadata"`
+	// ControlSecret authenticates the parent's calls to this instance's
+	// internal control API (readiness, drain). Never serialized.
+	ControlSecret string `json:"-"`
+
+	// StatusHistory is a bounded log of this instance's Status transitions,
+	// oldest first, so an operator debugging a flapping instance has an
+	// actual timeline instead of just the current state. Populated by
+	// setStatus; capped at maxStatusHistoryEntries.
+	StatusHistory []StatusTransition `json:"status_history,omitempty"`
+	// RestartCount counts how many times this instance has been restarted
+	// (manually or via RestartInstance's crash-recovery path), for the
+	// lifetime of this InstanceManager process.
+	RestartCount int `json:"restart_count"`
+	// LastExitCode is the isolated process's exit code from its most recent
+	// crash, as reported by the process isolation layer. Zero until the
+	// first crash is observed.
+	LastExitCode int `json:"last_exit_code"`
+
+	mutex sync.RWMutex `json:"-"`
+}
+
+// maxStatusHistoryEntries bounds how many transitions StatusHistory keeps per
+// instance, so a flapping instance's history doesn't grow without bound.
+const maxStatusHistoryEntries = 50
+
+// StatusTransition records one change of an instance's Status, including why
+// it happened, so GET /instances/:id/history can show operators the timeline
+// of a flapping instance instead of just its current state.
+type StatusTransition struct {
+	From      InstanceStatus `json:"from"`
+	To        InstanceStatus `json:"to"`
+	Timestamp time.Time      `json:"timestamp"`
+	Reason    string         `json:"reason,omitempty"`
+}
+
+// setStatus transitions instance to status and appends the transition to its
+// bounded StatusHistory. A no-op if status matches the current one, so
+// periodic re-confirmation (e.g. the monitoring loop re-observing "running")
+// doesn't spam the history. Callers must already hold instance.mutex.
+func (instance *WhatsAppInstance) setStatus(status InstanceStatus, reason string) {
+	if instance.Status == status {
+		return
+	}
+
+	instance.StatusHistory = append(instance.StatusHistory, StatusTransition{
+		From:      instance.Status,
+		To:        status,
+		Timestamp: time.Now(),
+		Reason:    reason,
+	})
+	if len(instance.StatusHistory) > maxStatusHistoryEntries {
+		instance.StatusHistory = instance.StatusHistory[len(instance.StatusHistory)-maxStatusHistoryEntries:]
+	}
+
+	instance.Status = status
 }
 
 type InstanceStatus string
@@ -61,29 +124,29 @@ const (
 )
 
 type InstanceConfig struct {
-	Port                int               `json:"port"`
-	Debug               bool              `json:"debug"`
-	OS                  string            `json:"os"`
-	BasicAuth           []string          `json:"basic_auth"`
-	BasePath            string            `json:"base_path"`
-	DBURI               string            `json:"db_uri"`
-	DBKeysURI           string            `json:"db_keys_uri"`
-	AutoReply           string            `json:"auto_reply"`
-	AutoMarkRead        bool              `json:"auto_mark_read"`
-	Webhooks            []string          `json:"webhooks"`
-	WebhookSecret       string            `json:"webhook_secret"`
-	AccountValidation   bool              `json:"account_validation"`
-	Environment         map[string]string `json:"environment"`
+	Port              int               `json:"port"`
+	Debug             bool              `json:"debug"`
+	OS                string            `json:"os"`
+	BasicAuth         []string          `json:"basic_auth"`
+	BasePath          string            `json:"base_path"`
+	DBURI             string            `json:"db_uri"`
+	DBKeysURI         string            `json:"db_keys_uri"`
+	AutoReply         string            `json:"auto_reply"`
+	AutoMarkRead      bool              `json:"auto_mark_read"`
+	Webhooks          []string          `json:"webhooks"`
+	WebhookSecret     string            `json:"webhook_secret"`
+	AccountValidation bool              `json:"account_validation"`
+	Environment       map[string]string `json:"environment"`
 }
 
 type InstanceStats struct {
-	TotalInstances   int                        `json:"total_instances"`
-	RunningInstances int                        `json:"running_instances"`
-	StoppedInstances int                        `json:"stopped_instances"`
-	ErrorInstances   int                        `json:"error_instances"`
-	InstancesByStatus map[InstanceStatus]int    `json:"instances_by_status"`
-	ResourceUsage    map[string]ResourceUsage   `json:"resource_usage"`
-	LastUpdated      time.Time                  `json:"last_updated"`
+	TotalInstances    int                      `json:"total_instances"`
+	RunningInstances  int                      `json:"running_instances"`
+	StoppedInstances  int                      `json:"stopped_instances"`
+	ErrorInstances    int                      `json:"error_instances"`
+	InstancesByStatus map[InstanceStatus]int   `json:"instances_by_status"`
+	ResourceUsage     map[string]ResourceUsage `json:"resource_usage"`
+	LastUpdated       time.Time                `json:"last_updated"`
 }
 
 type ResourceUsage struct {
@@ -94,20 +157,25 @@ type ResourceUsage struct {
 
 func NewInstanceManager() *InstanceManager {
 	ctx, cancel := context.WithCancel(context.Background())
-	
+
 	basePath := filepath.Join(config.PathStorages, "instances")
 	os.MkdirAll(basePath, 0755)
 
 	// Initialize process isolation configuration
 	isolationConfig := isolation.IsolationConfig{
 		EnableResourceLimits: true,
-		DefaultMemoryLimit:   512, // 512MB per instance
+		DefaultMemoryLimit:   512,  // 512MB per instance
 		DefaultCPULimit:      50.0, // 50% CPU limit
 		DefaultTimeout:       30 * time.Minute,
 		MonitoringInterval:   10 * time.Second,
 		AutoRestart:          true,
 		MaxRestarts:          3,
 		PathStorages:         config.PathStorages,
+
+		// Disabled by default: dropping to a dedicated uid/gid requires the
+		// parent to run as root, which most deployments don't. Operators that
+		// want the stronger isolation can flip this on for their environment.
+		EnableSecurityIsolation: false,
 	}
 
 	// Initialize database isolation manager based on configuration
@@ -126,6 +194,7 @@ func NewInstanceManager() *InstanceManager {
 		instances:       make(map[string]*WhatsAppInstance),
 		isolationMgr:    isolation.NewProcessIsolationManager(isolationConfig),
 		dbIsolationMgr:  dbIsolationMgr,
+		analytics:       analytics.NewAnalytics(),
 		basePath:        basePath,
 		ctx:             ctx,
 		cancel:          cancel,
@@ -150,9 +219,15 @@ func (im *InstanceManager) CreateInstance(name, phone string, config InstanceCon
 	// Generate unique ID
 	instanceID := im.generateInstanceID(name)
 
-	// Check if port is available
+	// Assign a port automatically, or validate a specifically requested one
 	if config.Port == 0 {
-		config.Port = im.findAvailablePort()
+		port, err := im.findAvailablePort()
+		if err != nil {
+			return nil, err
+		}
+		config.Port = port
+	} else if !im.isPortAvailable(config.Port) {
+		return nil, fmt.Errorf("requested port %d is not available", config.Port)
 	}
 
 	// Create instance directory
@@ -179,24 +254,30 @@ func (im *InstanceManager) CreateInstance(name, phone string, config InstanceCon
 	if config.DBURI == "" {
 		config.DBURI = fmt.Sprintf("file:%s?_foreign_keys=on", isolatedDB.DatabasePath)
 	}
-	
+
 	// Set isolated session storage
 	if config.DBKeysURI == "" {
 		config.DBKeysURI = fmt.Sprintf("file:%s?_foreign_keys=on", isolatedDB.KeysPath)
 	}
 
+	controlSecret, err := generateControlSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate instance control secret: %w", err)
+	}
+
 	instance := &WhatsAppInstance{
-		ID:         instanceID,
-		Name:       name,
-		Phone:      phone,
-		Status:     StatusStopped,
-		Port:       config.Port,
-		WorkingDir: instanceDir,
-		ConfigPath: filepath.Join(instanceDir, "config.json"),
-		LogPath:    filepath.Join(instanceDir, "logs", "app.log"),
-		CreatedAt:  time.Now(),
-		Config:     config,
-		Metadata:   make(map[string]string),
+		ID:            instanceID,
+		Name:          name,
+		Phone:         phone,
+		Status:        StatusStopped,
+		Port:          config.Port,
+		WorkingDir:    instanceDir,
+		ConfigPath:    filepath.Join(instanceDir, "config.json"),
+		LogPath:       filepath.Join(instanceDir, "logs", "app.log"),
+		CreatedAt:     time.Now(),
+		Config:        config,
+		Metadata:      make(map[string]string),
+		ControlSecret: controlSecret,
 	}
 
 	// Save instance configuration
@@ -205,7 +286,7 @@ func (im *InstanceManager) CreateInstance(name, phone string, config InstanceCon
 	}
 
 	im.instances[instanceID] = instance
-	
+
 	// Save instances list
 	im.saveInstances()
 
@@ -213,6 +294,108 @@ func (im *InstanceManager) CreateInstance(name, phone string, config InstanceCon
 	return instance, nil
 }
 
+// CloneInstance provisions a new instance pre-configured from an existing
+// one: webhooks, auto-reply, and other InstanceConfig fields are copied,
+// but the source's port and database URIs are dropped so CreateInstance
+// allocates a fresh port and an isolated DB/session store. No session
+// credentials are copied - the clone starts unauthenticated.
+func (im *InstanceManager) CloneInstance(sourceID, newName, newPhone string) (*WhatsAppInstance, error) {
+	source, err := im.GetInstance(sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find source instance: %w", err)
+	}
+
+	source.mutex.RLock()
+	clonedConfig := source.Config
+	source.mutex.RUnlock()
+
+	clonedConfig.Port = 0
+	clonedConfig.DBURI = ""
+	clonedConfig.DBKeysURI = ""
+
+	instance, err := im.CreateInstance(newName, newPhone, clonedConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloned instance: %w", err)
+	}
+
+	logrus.Infof("[MULTIINSTANCE] Cloned instance %s into %s (%s)", sourceID, newName, instance.ID)
+	return instance, nil
+}
+
+// Failover moves a crashed instance's isolated database (session data,
+// keys, chat history) onto a standby instance and starts it, so a
+// critical number stays reachable without manual intervention. It is
+// gated behind config.MultiInstanceHAEnabled since it overwrites the
+// standby's existing session data.
+func (im *InstanceManager) Failover(fromID, toID string) error {
+	if !config.MultiInstanceHAEnabled {
+		return fmt.Errorf("failover is disabled: set MultiInstanceHAEnabled to enable it")
+	}
+
+	from, err := im.GetInstance(fromID)
+	if err != nil {
+		return fmt.Errorf("failed to find crashed instance: %w", err)
+	}
+
+	to, err := im.GetInstance(toID)
+	if err != nil {
+		return fmt.Errorf("failed to find standby instance: %w", err)
+	}
+
+	from.mutex.RLock()
+	fromStatus := from.Status
+	from.mutex.RUnlock()
+
+	if fromStatus == StatusRunning {
+		return fmt.Errorf("instance %s is still running, stop it before failing over", fromID)
+	}
+
+	if err := im.StopInstance(toID); err != nil && fromStatus != StatusStopped {
+		logrus.Debugf("[MULTIINSTANCE] Standby instance %s was not running: %v", toID, err)
+	}
+
+	backupPath := filepath.Join(im.basePath, "failover", fmt.Sprintf("%s-%d", fromID, time.Now().UnixNano()))
+	defer os.RemoveAll(backupPath)
+
+	if err := im.dbIsolationMgr.BackupDatabase(fromID, backupPath); err != nil {
+		return fmt.Errorf("failed to back up crashed instance's database: %w", err)
+	}
+
+	if err := im.dbIsolationMgr.RestoreDatabase(toID, backupPath); err != nil {
+		return fmt.Errorf("failed to restore database onto standby instance: %w", err)
+	}
+
+	im.analytics.TrackEvent("instance", "failover", "started", map[string]interface{}{
+		"from_instance": fromID,
+		"to_instance":   toID,
+	})
+
+	if err := im.StartInstance(toID); err != nil {
+		im.analytics.TrackEvent("instance", "failover", "failed", map[string]interface{}{
+			"from_instance": fromID,
+			"to_instance":   toID,
+			"error":         err.Error(),
+		})
+		return fmt.Errorf("failed to start standby instance: %w", err)
+	}
+
+	to.mutex.Lock()
+	if to.Metadata == nil {
+		to.Metadata = make(map[string]string)
+	}
+	to.Metadata["failover_from"] = fromID
+	to.Metadata["failover_at"] = time.Now().Format(time.RFC3339)
+	to.mutex.Unlock()
+
+	im.analytics.TrackEvent("instance", "failover", "completed", map[string]interface{}{
+		"from_instance": fromID,
+		"to_instance":   toID,
+	})
+
+	logrus.Infof("[MULTIINSTANCE] Failed over instance %s to standby %s", fromID, toID)
+	return nil
+}
+
 // StartInstance starts a WhatsApp instance using process isolation
 func (im *InstanceManager) StartInstance(instanceID string) error {
 	im.mutex.RLock()
@@ -230,62 +413,62 @@ func (im *InstanceManager) StartInstance(instanceID string) error {
 		return fmt.Errorf("instance is already running")
 	}
 
-	instance.Status = StatusStarting
+	instance.setStatus(StatusStarting, "start requested")
 	logrus.Infof("[MULTIINSTANCE] Starting isolated instance: %s", instanceID)
 
 	// Get executable path
 	executable, err := os.Executable()
 	if err != nil {
-		instance.Status = StatusError
+		instance.setStatus(StatusError, fmt.Sprintf("failed to get executable path: %v", err))
 		return fmt.Errorf("failed to get executable path: %w", err)
 	}
 
 	// Prepare command arguments for isolated process
 	args := []string{"rest"}
 	args = append(args, "--port", fmt.Sprintf("%d", instance.Config.Port))
-	
+
 	if instance.Config.Debug {
 		args = append(args, "--debug", "true")
 	}
-	
+
 	if instance.Config.OS != "" {
 		args = append(args, "--os", instance.Config.OS)
 	}
-	
+
 	if len(instance.Config.BasicAuth) > 0 {
 		for _, auth := range instance.Config.BasicAuth {
 			args = append(args, "--basic-auth", auth)
 		}
 	}
-	
+
 	if instance.Config.BasePath != "" {
 		args = append(args, "--base-path", instance.Config.BasePath)
 	}
-	
+
 	args = append(args, "--db-uri", instance.Config.DBURI)
-	
+
 	if instance.Config.DBKeysURI != "" {
 		args = append(args, "--db-keys-uri", instance.Config.DBKeysURI)
 	}
-	
+
 	if instance.Config.AutoReply != "" {
 		args = append(args, "--autoreply", instance.Config.AutoReply)
 	}
-	
+
 	if instance.Config.AutoMarkRead {
 		args = append(args, "--auto-mark-read", "true")
 	}
-	
+
 	if len(instance.Config.Webhooks) > 0 {
 		for _, webhook := range instance.Config.Webhooks {
 			args = append(args, "--webhook", webhook)
 		}
 	}
-	
+
 	if instance.Config.WebhookSecret != "" {
 		args = append(args, "--webhook-secret", instance.Config.WebhookSecret)
 	}
-	
+
 	if !instance.Config.AccountValidation {
 		args = append(args, "--account-validation", "false")
 	}
@@ -295,7 +478,7 @@ func (im *InstanceManager) StartInstance(instanceID string) error {
 	for key, value := range instance.Config.Environment {
 		instanceEnv[key] = value
 	}
-	
+
 	// Set instance-specific paths for complete isolation
 	instanceEnv["WHATSAPP_INSTANCE_ID"] = instanceID
 	instanceEnv["WHATSAPP_INSTANCE_NAME"] = instance.Name
@@ -303,6 +486,7 @@ func (im *InstanceManager) StartInstance(instanceID string) error {
 	instanceEnv["WHATSAPP_STORAGE_PATH"] = filepath.Join(instance.WorkingDir, "storages")
 	instanceEnv["WHATSAPP_STATIC_PATH"] = filepath.Join(instance.WorkingDir, "statics")
 	instanceEnv["WHATSAPP_LOG_PATH"] = filepath.Join(instance.WorkingDir, "logs")
+	instanceEnv["WHATSAPP_INSTANCE_SECRET"] = instance.ControlSecret
 
 	// Create isolated process using the isolation manager
 	isolatedProcess, err := im.isolationMgr.CreateIsolatedProcess(
@@ -313,7 +497,7 @@ func (im *InstanceManager) StartInstance(instanceID string) error {
 		im.isolationConfig,
 	)
 	if err != nil {
-		instance.Status = StatusError
+		instance.setStatus(StatusError, fmt.Sprintf("failed to create isolated process: %v", err))
 		return fmt.Errorf("failed to create isolated process: %w", err)
 	}
 
@@ -324,19 +508,19 @@ func (im *InstanceManager) StartInstance(instanceID string) error {
 
 	// Start the isolated process
 	if err := im.isolationMgr.StartProcess(instanceID); err != nil {
-		instance.Status = StatusError
+		instance.setStatus(StatusError, fmt.Sprintf("failed to start isolated process: %v", err))
 		return fmt.Errorf("failed to start isolated process: %w", err)
 	}
 
 	// Get the process details from isolation manager
 	isolatedProcess, err = im.isolationMgr.GetProcess(instanceID)
 	if err != nil {
-		instance.Status = StatusError
+		instance.setStatus(StatusError, fmt.Sprintf("failed to get isolated process: %v", err))
 		return fmt.Errorf("failed to get isolated process: %w", err)
 	}
 
 	instance.PID = isolatedProcess.PID
-	instance.Status = StatusRunning
+	instance.setStatus(StatusRunning, "started successfully")
 	now := time.Now()
 	instance.StartedAt = &now
 	instance.LastSeen = &now
@@ -355,6 +539,13 @@ func (im *InstanceManager) StopInstance(instanceID string) error {
 		return fmt.Errorf("instance not found: %s", instanceID)
 	}
 
+	// Ask the instance to drain in-flight requests over the authenticated
+	// control channel before falling back to a process signal. Done before
+	// acquiring instance.mutex below since it locks the same mutex itself.
+	if _, err := im.DrainInstance(instanceID); err != nil {
+		logrus.Debugf("[MULTIINSTANCE] Graceful drain of %s unavailable, falling back to signal: %v", instanceID, err)
+	}
+
 	instance.mutex.Lock()
 	defer instance.mutex.Unlock()
 
@@ -362,7 +553,7 @@ func (im *InstanceManager) StopInstance(instanceID string) error {
 		return fmt.Errorf("instance is not running")
 	}
 
-	instance.Status = StatusStopping
+	instance.setStatus(StatusStopping, "stop requested")
 	logrus.Infof("[MULTIINSTANCE] Stopping isolated instance: %s", instanceID)
 
 	// Stop the isolated process
@@ -371,7 +562,7 @@ func (im *InstanceManager) StopInstance(instanceID string) error {
 		// Continue with cleanup even if stop failed
 	}
 
-	instance.Status = StatusStopped
+	instance.setStatus(StatusStopped, "stopped")
 	instance.Process = nil
 	instance.PID = 0
 
@@ -379,6 +570,65 @@ func (im *InstanceManager) StopInstance(instanceID string) error {
 	return nil
 }
 
+// CheckReadiness calls a running instance's internal, HMAC-authenticated
+// readiness endpoint instead of inferring health from process signals or the
+// shared filesystem. It returns the decoded response body on success.
+func (im *InstanceManager) CheckReadiness(instanceID string) (map[string]interface{}, error) {
+	return im.callControlAPI(instanceID, http.MethodGet, "ready")
+}
+
+// DrainInstance calls a running instance's internal graceful-stop endpoint,
+// asking it to stop accepting new work and exit once its in-flight requests
+// finish, rather than sending it a kill signal.
+func (im *InstanceManager) DrainInstance(instanceID string) (map[string]interface{}, error) {
+	return im.callControlAPI(instanceID, http.MethodPost, "stop")
+}
+
+// callControlAPI signs and sends a request to instanceID's internal control
+// API on localhost, authenticating with the instance's ControlSecret.
+func (im *InstanceManager) callControlAPI(instanceID, method, action string) (map[string]interface{}, error) {
+	instance, err := im.GetInstance(instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find instance: %w", err)
+	}
+
+	instance.mutex.RLock()
+	port := instance.Port
+	secret := instance.ControlSecret
+	basePath := instance.Config.BasePath
+	instance.mutex.RUnlock()
+
+	path := fmt.Sprintf("%s/internal/instance/%s", basePath, action)
+	req, err := http.NewRequest(method, "http://127.0.0.1:"+strconv.Itoa(port)+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build control request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("X-Instance-Timestamp", timestamp)
+	req.Header.Set("X-Instance-Signature", instancecontrol.Sign(secret, instancecontrol.Payload(method, path, timestamp)))
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("control request to instance %s failed: %w", instanceID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("instance %s control API returned status %d", instanceID, resp.StatusCode)
+	}
+
+	var body struct {
+		Results map[string]interface{} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode control response: %w", err)
+	}
+
+	return body.Results, nil
+}
+
 // RestartInstance restarts a WhatsApp instance using process isolation
 func (im *InstanceManager) RestartInstance(instanceID string) error {
 	instance, exists := im.instances[instanceID]
@@ -386,7 +636,9 @@ func (im *InstanceManager) RestartInstance(instanceID string) error {
 		return fmt.Errorf("instance not found: %s", instanceID)
 	}
 
-	instance.Status = StatusRestarting
+	instance.mutex.Lock()
+	instance.setStatus(StatusRestarting, "restart requested")
+	instance.mutex.Unlock()
 	logrus.Infof("[MULTIINSTANCE] Restarting isolated instance: %s", instanceID)
 
 	// Use the isolation manager's restart functionality
@@ -402,7 +654,8 @@ func (im *InstanceManager) RestartInstance(instanceID string) error {
 
 	instance.mutex.Lock()
 	instance.PID = isolatedProcess.PID
-	instance.Status = StatusRunning
+	instance.setStatus(StatusRunning, "restarted")
+	instance.RestartCount++
 	now := time.Now()
 	instance.StartedAt = &now
 	instance.LastSeen = &now
@@ -412,6 +665,187 @@ func (im *InstanceManager) RestartInstance(instanceID string) error {
 	return nil
 }
 
+// UpdateInstanceConfig validates updates against the stored InstanceConfig,
+// persists them, and - if the instance is running - restarts it so the new
+// config actually takes effect. A plain RestartInstance isn't enough here:
+// it re-execs the isolated process with the args and environment it was
+// created with, so applying a config change requires dropping the isolation
+// manager's process registration first and letting StartInstance rebuild it
+// from the (now updated) InstanceConfig. This lets callers tweak a running
+// instance's feature flags without a delete-and-recreate.
+func (im *InstanceManager) UpdateInstanceConfig(instanceID string, updates map[string]interface{}) error {
+	im.mutex.RLock()
+	instance, exists := im.instances[instanceID]
+	im.mutex.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("instance not found: %s", instanceID)
+	}
+
+	instance.mutex.Lock()
+	if err := applyInstanceConfigUpdates(&instance.Config, updates); err != nil {
+		instance.mutex.Unlock()
+		return err
+	}
+	wasRunning := instance.Status == StatusRunning
+	instance.mutex.Unlock()
+
+	if err := im.saveInstanceConfig(instance); err != nil {
+		return fmt.Errorf("failed to save updated instance config: %w", err)
+	}
+	im.saveInstances()
+
+	logrus.Infof("[MULTIINSTANCE] Updated config for instance %s", instanceID)
+
+	if !wasRunning {
+		return nil
+	}
+
+	if err := im.StopInstance(instanceID); err != nil {
+		return fmt.Errorf("failed to stop instance to apply config: %w", err)
+	}
+	if err := im.isolationMgr.DeleteProcess(instanceID); err != nil {
+		logrus.Warnf("[MULTIINSTANCE] Failed to clear stale process registration for %s: %v", instanceID, err)
+	}
+	if err := im.StartInstance(instanceID); err != nil {
+		return fmt.Errorf("failed to restart instance with updated config: %w", err)
+	}
+
+	return nil
+}
+
+// applyInstanceConfigUpdates validates and applies each key present in
+// updates onto cfg, following the same explicit per-field allowlist as
+// webhook.WebhookManager.UpdateEndpoint. Unlike that allowlist, a malformed
+// value here fails the whole update instead of being silently skipped, since
+// a bad instance config can leave the isolated process unable to start.
+func applyInstanceConfigUpdates(cfg *InstanceConfig, updates map[string]interface{}) error {
+	if raw, ok := updates["port"]; ok {
+		port, ok := raw.(float64)
+		if !ok || port <= 0 || port > 65535 {
+			return fmt.Errorf("port must be a number between 1 and 65535")
+		}
+		if int(port) != cfg.Port && !isPortBindable(int(port)) {
+			return fmt.Errorf("port %d is not bindable", int(port))
+		}
+		cfg.Port = int(port)
+	}
+	if raw, ok := updates["debug"]; ok {
+		debug, ok := raw.(bool)
+		if !ok {
+			return fmt.Errorf("debug must be a boolean")
+		}
+		cfg.Debug = debug
+	}
+	if raw, ok := updates["os"]; ok {
+		osName, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("os must be a string")
+		}
+		cfg.OS = osName
+	}
+	if raw, ok := updates["basic_auth"]; ok {
+		basicAuth, err := toStringSlice(raw)
+		if err != nil {
+			return fmt.Errorf("basic_auth %w", err)
+		}
+		cfg.BasicAuth = basicAuth
+	}
+	if raw, ok := updates["base_path"]; ok {
+		basePath, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("base_path must be a string")
+		}
+		cfg.BasePath = basePath
+	}
+	if raw, ok := updates["db_uri"]; ok {
+		dbURI, ok := raw.(string)
+		if !ok || dbURI == "" {
+			return fmt.Errorf("db_uri must be a non-empty string")
+		}
+		cfg.DBURI = dbURI
+	}
+	if raw, ok := updates["db_keys_uri"]; ok {
+		dbKeysURI, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("db_keys_uri must be a string")
+		}
+		cfg.DBKeysURI = dbKeysURI
+	}
+	if raw, ok := updates["auto_reply"]; ok {
+		autoReply, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("auto_reply must be a string")
+		}
+		cfg.AutoReply = autoReply
+	}
+	if raw, ok := updates["auto_mark_read"]; ok {
+		autoMarkRead, ok := raw.(bool)
+		if !ok {
+			return fmt.Errorf("auto_mark_read must be a boolean")
+		}
+		cfg.AutoMarkRead = autoMarkRead
+	}
+	if raw, ok := updates["webhooks"]; ok {
+		webhooks, err := toStringSlice(raw)
+		if err != nil {
+			return fmt.Errorf("webhooks %w", err)
+		}
+		cfg.Webhooks = webhooks
+	}
+	if raw, ok := updates["webhook_secret"]; ok {
+		webhookSecret, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("webhook_secret must be a string")
+		}
+		cfg.WebhookSecret = webhookSecret
+	}
+	if raw, ok := updates["account_validation"]; ok {
+		accountValidation, ok := raw.(bool)
+		if !ok {
+			return fmt.Errorf("account_validation must be a boolean")
+		}
+		cfg.AccountValidation = accountValidation
+	}
+	if raw, ok := updates["environment"]; ok {
+		env, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("environment must be an object of string values")
+		}
+		parsed := make(map[string]string, len(env))
+		for key, value := range env {
+			str, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("environment.%s must be a string", key)
+			}
+			parsed[key] = str
+		}
+		cfg.Environment = parsed
+	}
+
+	return nil
+}
+
+// toStringSlice converts a decoded JSON array (BodyParser hands updates
+// through as map[string]interface{}, so array fields arrive as
+// []interface{}) into a []string, failing if any element isn't a string.
+func toStringSlice(raw interface{}) ([]string, error) {
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("must be an array of strings")
+	}
+
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		str, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be an array of strings")
+		}
+		result = append(result, str)
+	}
+	return result, nil
+}
+
 // DeleteInstance removes a WhatsApp instance and its isolated process
 func (im *InstanceManager) DeleteInstance(instanceID string) error {
 	im.mutex.Lock()
@@ -445,7 +879,7 @@ func (im *InstanceManager) DeleteInstance(instanceID string) error {
 
 	// Remove from instances map
 	delete(im.instances, instanceID)
-	
+
 	// Save instances list
 	im.saveInstances()
 
@@ -466,8 +900,51 @@ func (im *InstanceManager) GetInstance(instanceID string) (*WhatsAppInstance, er
 	return instance, nil
 }
 
+// VacuumInstanceDatabase compacts the isolated database backing instanceID,
+// reclaiming disk space without stopping the instance.
+func (im *InstanceManager) VacuumInstanceDatabase(instanceID string) (*isolation.VacuumReport, error) {
+	if _, err := im.GetInstance(instanceID); err != nil {
+		return nil, err
+	}
+
+	return im.dbIsolationMgr.VacuumDatabase(instanceID)
+}
+
+// MigrateInstanceDatabase migrates the isolated SQLite database backing
+// instanceID onto a PostgreSQL cluster at targetURI, then repoints the
+// instance's own config at the new connection strings so its next start
+// picks them up. The instance must be stopped first, since the migration
+// only copies existing rows and can't capture writes made while it runs.
+func (im *InstanceManager) MigrateInstanceDatabase(instanceID string, targetURI string) error {
+	instance, err := im.GetInstance(instanceID)
+	if err != nil {
+		return err
+	}
+
+	if instance.Status != StatusStopped {
+		return fmt.Errorf("instance %s must be stopped before migrating its database (status: %s)", instanceID, instance.Status)
+	}
+
+	if err := im.dbIsolationMgr.MigrateDatabase(instanceID, targetURI); err != nil {
+		return err
+	}
+
+	migratedDB, err := im.dbIsolationMgr.GetIsolatedDatabase(instanceID)
+	if err != nil {
+		return fmt.Errorf("migration succeeded but failed to read back new connection info: %w", err)
+	}
+
+	im.mutex.Lock()
+	instance.Config.DBURI = migratedDB.ConnectionURI
+	instance.Config.DBKeysURI = migratedDB.KeysURI
+	im.mutex.Unlock()
+
+	logrus.Infof("[MULTI_INSTANCE] Migrated instance %s database to PostgreSQL", instanceID)
+	return nil
+}
+
 // ListInstances returns all instances
-func (im *InstanceManager) ListInstances() []*WhatsAppInstance {
+func (im *InstanceManager) ListInstances(sortBy, sortDir string) []*WhatsAppInstance {
 	im.mutex.RLock()
 	defer im.mutex.RUnlock()
 
@@ -476,9 +953,41 @@ func (im *InstanceManager) ListInstances() []*WhatsAppInstance {
 		instances = append(instances, instance)
 	}
 
+	sort.Slice(instances, func(i, j int) bool {
+		cmp := compareInstances(instances[i], instances[j], sortBy)
+		if sortDir == "desc" {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+
 	return instances
 }
 
+// compareInstances orders two instances by sortBy ("name", "status", or the
+// default "created_at"), falling back to ID as a tie-breaker so the order is
+// stable across calls regardless of map iteration order.
+func compareInstances(a, b *WhatsAppInstance, sortBy string) int {
+	switch sortBy {
+	case "name":
+		if a.Name != b.Name {
+			return strings.Compare(a.Name, b.Name)
+		}
+	case "status":
+		if a.Status != b.Status {
+			return strings.Compare(string(a.Status), string(b.Status))
+		}
+	default:
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			if a.CreatedAt.Before(b.CreatedAt) {
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(a.ID, b.ID)
+}
+
 // GetStats returns instance statistics
 func (im *InstanceManager) GetStats() *InstanceStats {
 	im.mutex.RLock()
@@ -494,7 +1003,7 @@ func (im *InstanceManager) GetStats() *InstanceStats {
 	for _, instance := range im.instances {
 		instance.mutex.RLock()
 		stats.InstancesByStatus[instance.Status]++
-		
+
 		switch instance.Status {
 		case StatusRunning:
 			stats.RunningInstances++
@@ -520,6 +1029,14 @@ func (im *InstanceManager) GetStats() *InstanceStats {
 
 // Private methods
 
+func generateControlSecret() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
+
 func (im *InstanceManager) generateInstanceID(name string) string {
 	// Create a safe ID from name + timestamp
 	safeName := strings.ToLower(strings.ReplaceAll(name, " ", "_"))
@@ -528,23 +1045,47 @@ func (im *InstanceManager) generateInstanceID(name string) string {
 	return fmt.Sprintf("%s_%s", safeName, timestamp)
 }
 
-func (im *InstanceManager) findAvailablePort() int {
-	// Start from port 3001 and find the first available port
-	for port := 3001; port < 4000; port++ {
+// findAvailablePort searches [config.MultiInstancePortRangeStart,
+// config.MultiInstancePortRangeEnd) for the first port that's neither
+// assigned to an existing instance nor already bound by something else on
+// the host, and returns an error instead of a fallback guess when the range
+// is exhausted - reusing a port silently is worse than failing loudly,
+// especially in containerized environments where the allowed range is
+// already constrained.
+func (im *InstanceManager) findAvailablePort() (int, error) {
+	start := config.MultiInstancePortRangeStart
+	end := config.MultiInstancePortRangeEnd
+
+	for port := start; port < end; port++ {
 		if im.isPortAvailable(port) {
-			return port
+			return port, nil
 		}
 	}
-	return 3001 // Fallback
+	return 0, fmt.Errorf("no available port in range %d-%d", start, end)
 }
 
+// isPortAvailable reports whether port is free to assign: not already
+// recorded against another instance, and actually bindable on the host right
+// now.
 func (im *InstanceManager) isPortAvailable(port int) bool {
-	// Check if port is already used by existing instances
 	for _, instance := range im.instances {
 		if instance.Config.Port == port {
 			return false
 		}
 	}
+	return isPortBindable(port)
+}
+
+// isPortBindable attempts a real TCP bind to confirm the OS will hand out
+// port, catching cases (already in use by an unrelated process, outside the
+// container's allowed range, privileged and unavailable, etc.) that a
+// registry-only check can't see.
+func isPortBindable(port int) bool {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+	_ = listener.Close()
 	return true
 }
 
@@ -553,13 +1094,13 @@ func (im *InstanceManager) saveInstanceConfig(instance *WhatsAppInstance) error
 	if err != nil {
 		return err
 	}
-	
+
 	return os.WriteFile(instance.ConfigPath, data, 0644)
 }
 
 func (im *InstanceManager) saveInstances() error {
 	instancesFile := filepath.Join(im.basePath, "instances.json")
-	
+
 	// Create a simplified version for saving
 	saveData := make(map[string]interface{})
 	for id, instance := range im.instances {
@@ -575,18 +1116,18 @@ func (im *InstanceManager) saveInstances() error {
 			"metadata":    instance.Metadata,
 		}
 	}
-	
+
 	data, err := json.MarshalIndent(saveData, "", "  ")
 	if err != nil {
 		return err
 	}
-	
+
 	return os.WriteFile(instancesFile, data, 0644)
 }
 
 func (im *InstanceManager) loadInstances() {
 	instancesFile := filepath.Join(im.basePath, "instances.json")
-	
+
 	data, err := os.ReadFile(instancesFile)
 	if err != nil {
 		if !os.IsNotExist(err) {
@@ -603,7 +1144,7 @@ func (im *InstanceManager) loadInstances() {
 
 	for id, data := range saveData {
 		instanceData := data.(map[string]interface{})
-		
+
 		// Load instance config
 		configPath := instanceData["config_path"].(string)
 		configData, err := os.ReadFile(configPath)
@@ -649,12 +1190,17 @@ func (im *InstanceManager) monitorInstance(instance *WhatsAppInstance, cmd *exec
 
 	// Wait for process to exit
 	err := cmd.Wait()
-	
+
 	instance.mutex.Lock()
 	if instance.Status == StatusStopping {
-		instance.Status = StatusStopped
+		instance.setStatus(StatusStopped, "stopped")
 	} else {
-		instance.Status = StatusError
+		exitCode := -1
+		if cmd.ProcessState != nil {
+			exitCode = cmd.ProcessState.ExitCode()
+		}
+		instance.LastExitCode = exitCode
+		instance.setStatus(StatusError, fmt.Sprintf("process exited unexpectedly (exit code %d)", exitCode))
 	}
 	instance.Process = nil
 	instance.PID = 0
@@ -692,22 +1238,23 @@ func (im *InstanceManager) updateInstanceStatus() {
 			isolatedProcess, err := im.isolationMgr.GetProcess(instance.ID)
 			if err != nil {
 				// Process not found in isolation manager
-				instance.Status = StatusError
+				instance.setStatus(StatusError, "isolated process not found")
 				instance.Process = nil
 				instance.PID = 0
 			} else {
 				// Update status from isolated process
 				switch isolatedProcess.Status {
 				case isolation.ProcessStatusRunning:
-					instance.Status = StatusRunning
+					instance.setStatus(StatusRunning, "process running")
 					instance.PID = isolatedProcess.PID
 					now := time.Now()
 					instance.LastSeen = &now
 				case isolation.ProcessStatusStopped:
-					instance.Status = StatusStopped
+					instance.setStatus(StatusStopped, "process stopped")
 					instance.PID = 0
 				case isolation.ProcessStatusCrashed, isolation.ProcessStatusError:
-					instance.Status = StatusError
+					instance.LastExitCode = isolatedProcess.ExitCode
+					instance.setStatus(StatusError, fmt.Sprintf("process %s (exit code %d)", isolatedProcess.Status, isolatedProcess.ExitCode))
 					instance.PID = 0
 				}
 			}
@@ -719,20 +1266,20 @@ func (im *InstanceManager) updateInstanceStatus() {
 // Stop gracefully stops the instance manager and all isolated processes
 func (im *InstanceManager) Stop() {
 	logrus.Info("[MULTIINSTANCE] Stopping instance manager...")
-	
+
 	// Stop all running instances
 	for _, instance := range im.instances {
 		if instance.Status == StatusRunning {
 			im.StopInstance(instance.ID)
 		}
 	}
-	
+
 	// Stop the isolation manager
 	im.isolationMgr.Stop()
-	
+
 	// Stop the database isolation manager
 	im.dbIsolationMgr.Stop()
-	
+
 	im.cancel()
 	logrus.Info("[MULTIINSTANCE] Instance manager stopped")
-}
\ No newline at end of file
+}
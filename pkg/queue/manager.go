@@ -1,21 +1,41 @@
 package queue
 
 import (
+	"container/heap"
 	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/apperr"
+	_ "github.com/mattn/go-sqlite3"
 	"github.com/sirupsen/logrus"
 )
 
+// Priority is a numeric priority on a 0 (lowest) - 100 (highest) scale,
+// letting callers interleave scheduled campaigns and transactional sends at
+// whatever granularity they need instead of being limited to four tiers.
 type Priority int
 
 const (
-	PriorityLow Priority = iota
-	PriorityNormal
-	PriorityHigh
-	PriorityUrgent
+	PriorityMin Priority = 0
+	PriorityMax Priority = 100
+
+	// PriorityLow, PriorityNormal, PriorityHigh and PriorityUrgent are
+	// convenience aliases spanning the full range, kept for existing
+	// integrations that only need the original four tiers.
+	PriorityLow    Priority = 25
+	PriorityNormal Priority = 50
+	PriorityHigh   Priority = 75
+	PriorityUrgent Priority = PriorityMax
 )
 
 type JobStatus string
@@ -30,29 +50,62 @@ const (
 )
 
 type Job struct {
-	ID          string                 `json:"id"`
-	Type        string                 `json:"type"`
-	Priority    Priority               `json:"priority"`
-	Status      JobStatus              `json:"status"`
-	Data        map[string]interface{} `json:"data"`
-	CreatedAt   time.Time              `json:"created_at"`
-	ScheduledAt time.Time              `json:"scheduled_at"`
-	StartedAt   *time.Time             `json:"started_at,omitempty"`
-	CompletedAt *time.Time             `json:"completed_at,omitempty"`
-	Attempts    int                    `json:"attempts"`
-	MaxAttempts int                    `json:"max_attempts"`
-	Error       string                 `json:"error,omitempty"`
-	Result      interface{}            `json:"result,omitempty"`
-	Timeout     time.Duration          `json:"timeout"`
-	Metadata    map[string]interface{} `json:"metadata"`
+	ID             string                 `json:"id"`
+	Type           string                 `json:"type"`
+	Priority       Priority               `json:"priority"`
+	Status         JobStatus              `json:"status"`
+	Data           map[string]interface{} `json:"data"`
+	CreatedAt      time.Time              `json:"created_at"`
+	ScheduledAt    time.Time              `json:"scheduled_at"`
+	StartedAt      *time.Time             `json:"started_at,omitempty"`
+	CompletedAt    *time.Time             `json:"completed_at,omitempty"`
+	Attempts       int                    `json:"attempts"`
+	MaxAttempts    int                    `json:"max_attempts"`
+	Error          string                 `json:"error,omitempty"`
+	Result         interface{}            `json:"result,omitempty"`
+	Timeout        time.Duration          `json:"timeout"`
+	Metadata       map[string]interface{} `json:"metadata"`
+	Dependencies   []string               `json:"dependencies,omitempty"`     // IDs of jobs that must complete before this one becomes eligible to run
+	LeaseExpiresAt *time.Time             `json:"lease_expires_at,omitempty"` // set while leased to an external worker via LeaseJob; cleared on Ack/Nack
 }
 
 type JobHandler func(ctx context.Context, job *Job) error
 
+// jobHeap is a container/heap.Interface backing the queue manager's single
+// priority queue: it orders jobs by descending Priority, breaking ties by
+// the earliest ScheduledAt, so heap.Pop always returns the most urgent
+// eligible-by-schedule job regardless of which of the 0-100 priority values
+// it was submitted with.
+type jobHeap []*Job
+
+func (h jobHeap) Len() int { return len(h) }
+
+func (h jobHeap) Less(i, j int) bool {
+	if h[i].Priority != h[j].Priority {
+		return h[i].Priority > h[j].Priority
+	}
+	return h[i].ScheduledAt.Before(h[j].ScheduledAt)
+}
+
+func (h jobHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *jobHeap) Push(x interface{}) {
+	*h = append(*h, x.(*Job))
+}
+
+func (h *jobHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	job := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return job
+}
+
 type QueueManager struct {
-	queues      map[Priority][]*Job
+	jobs        jobHeap
 	handlers    map[string]JobHandler
-	workers     map[Priority]int
+	workerCount int
 	running     bool
 	mutex       sync.RWMutex
 	jobMutex    sync.RWMutex
@@ -60,91 +113,403 @@ type QueueManager struct {
 	cancel      context.CancelFunc
 	stats       *QueueStats
 	rateLimiter map[string]*RateLimiter
+
+	quietHoursMutex sync.RWMutex
+	quietHours      *QuietHoursPolicy
+
+	resultStore *sql.DB
+
+	recurringMutex sync.RWMutex
+	recurring      map[string]*RecurringJob
+
+	eventListenersMutex sync.RWMutex
+	eventListeners      []JobEventListener
+
+	retryMutex  sync.RWMutex
+	retryPolicy *RetryPolicy
+
+	// processing tracks jobs currently being worked by an in-process worker
+	// goroutine, keyed by job ID. getNextJob pops a job off the heap before
+	// handing it to a worker, so this is the only place such a job remains
+	// discoverable while it's in flight - which is what lets the stuck-job
+	// reaper find and recover it if the worker never reports back.
+	processingMutex sync.RWMutex
+	processing      map[string]*Job
+}
+
+// RetryPolicy controls the delay a failed job waits before its next
+// automatic retry attempt.
+type RetryPolicy struct {
+	BaseDelay time.Duration `json:"base_delay"`
+	// Jitter enables full jitter: the actual delay is randomized uniformly
+	// within [0, computed] instead of always being exactly computed, so a
+	// burst of jobs that fail together (e.g. a downstream API blip) don't
+	// all retry in lockstep and hammer it again the moment it recovers.
+	Jitter bool `json:"jitter"`
+}
+
+// JobEvent describes a queue lifecycle transition - enqueued, started,
+// completed, failed, or retrying - for consumers that want to observe queue
+// activity in real time (e.g. streaming it to a dashboard) instead of
+// polling ListJobs.
+type JobEvent struct {
+	JobID    string    `json:"job_id"`
+	Type     string    `json:"type"`
+	Priority Priority  `json:"priority"`
+	Status   JobStatus `json:"status"`
+	Event    string    `json:"event"`
+	At       time.Time `json:"at"`
+}
+
+type JobEventListener func(JobEvent)
+
+// OnJobEvent registers a listener invoked on every job lifecycle transition.
+// Listeners run synchronously on the goroutine making the transition, so
+// they must not block or call back into the QueueManager.
+func (qm *QueueManager) OnJobEvent(listener JobEventListener) {
+	qm.eventListenersMutex.Lock()
+	defer qm.eventListenersMutex.Unlock()
+	qm.eventListeners = append(qm.eventListeners, listener)
+}
+
+func (qm *QueueManager) emitJobEvent(job *Job, eventName string) {
+	qm.eventListenersMutex.RLock()
+	listeners := qm.eventListeners
+	qm.eventListenersMutex.RUnlock()
+
+	if len(listeners) == 0 {
+		return
+	}
+
+	event := JobEvent{
+		JobID:    job.ID,
+		Type:     job.Type,
+		Priority: job.Priority,
+		Status:   job.Status,
+		Event:    eventName,
+		At:       time.Now(),
+	}
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
+
+// RecurringJob describes a job that AddJob-s itself on a fixed interval,
+// e.g. periodic housekeeping. It's driven by startRecurringScheduler rather
+// than the priority queues themselves - each due job is submitted as a
+// normal one-off Job via AddJob.
+type RecurringJob struct {
+	ID        string                 `json:"id"`
+	JobType   string                 `json:"job_type"`
+	Data      map[string]interface{} `json:"data"`
+	Priority  Priority               `json:"priority"`
+	Interval  time.Duration          `json:"interval"`
+	NextRunAt time.Time              `json:"next_run_at"`
+	LastJobID string                 `json:"last_job_id,omitempty"`
 }
 
 type QueueStats struct {
-	TotalJobs     int64                    `json:"total_jobs"`
-	CompletedJobs int64                    `json:"completed_jobs"`
-	FailedJobs    int64                    `json:"failed_jobs"`
-	PendingJobs   map[Priority]int         `json:"pending_jobs"`
-	ProcessingJobs int                     `json:"processing_jobs"`
-	JobsByType    map[string]int64         `json:"jobs_by_type"`
-	AverageTime   map[string]time.Duration `json:"average_time"`
-	LastUpdated   time.Time                `json:"last_updated"`
-	mutex         sync.RWMutex
+	TotalJobs      int64                    `json:"total_jobs"`
+	CompletedJobs  int64                    `json:"completed_jobs"`
+	FailedJobs     int64                    `json:"failed_jobs"`
+	DeferredJobs   int64                    `json:"deferred_jobs"`
+	PendingJobs    map[Priority]int         `json:"pending_jobs"`
+	ProcessingJobs int                      `json:"processing_jobs"`
+	JobsByType     map[string]int64         `json:"jobs_by_type"`
+	AverageTime    map[string]time.Duration `json:"average_time"`
+	// ByTypeAndPriority breaks processing-time percentiles and success rates
+	// down by "type:tier" (e.g. "send_message:urgent"), so callers can see
+	// whether high-priority jobs actually get the latency benefit they
+	// should over normal-priority ones.
+	ByTypeAndPriority map[string]*JobTypePriorityStats `json:"by_type_and_priority"`
+	LastUpdated       time.Time                        `json:"last_updated"`
+	mutex             sync.RWMutex
+	detail            map[string]*jobTypePriorityTracker
+	typeMedian        map[string]*p2Estimator
+}
+
+// JobTypePriorityStats is a point-in-time snapshot of processing-time
+// percentiles and outcome counts for one (job type, priority tier)
+// combination, as returned by GetQueueStats.
+type JobTypePriorityStats struct {
+	Count        int64         `json:"count"`
+	SuccessCount int64         `json:"success_count"`
+	FailureCount int64         `json:"failure_count"`
+	P50          time.Duration `json:"p50"`
+	P95          time.Duration `json:"p95"`
+	P99          time.Duration `json:"p99"`
+}
+
+// jobTypePriorityTracker accumulates processing-time percentiles and
+// outcome counts for one (job type, priority tier) combination. It's kept
+// under the same qm.stats.mutex as the rest of QueueStats rather than its
+// own lock, since it's only ever touched alongside other stats updates.
+type jobTypePriorityTracker struct {
+	count        int64
+	successCount int64
+	failureCount int64
+	p50          *p2Estimator
+	p95          *p2Estimator
+	p99          *p2Estimator
+}
+
+func newJobTypePriorityTracker() *jobTypePriorityTracker {
+	return &jobTypePriorityTracker{
+		p50: newP2Estimator(0.50),
+		p95: newP2Estimator(0.95),
+		p99: newP2Estimator(0.99),
+	}
+}
+
+func (t *jobTypePriorityTracker) record(success bool, duration time.Duration) {
+	t.count++
+	if success {
+		t.successCount++
+	} else {
+		t.failureCount++
+	}
+	millis := float64(duration.Milliseconds())
+	t.p50.Add(millis)
+	t.p95.Add(millis)
+	t.p99.Add(millis)
+}
+
+func (t *jobTypePriorityTracker) snapshot() *JobTypePriorityStats {
+	return &JobTypePriorityStats{
+		Count:        t.count,
+		SuccessCount: t.successCount,
+		FailureCount: t.failureCount,
+		P50:          time.Duration(t.p50.Value()) * time.Millisecond,
+		P95:          time.Duration(t.p95.Value()) * time.Millisecond,
+		P99:          time.Duration(t.p99.Value()) * time.Millisecond,
+	}
+}
+
+// priorityTier buckets a job's continuous 0-100 priority into one of the
+// four named tiers, so processing-time breakdowns stay a fixed, small set
+// of buckets instead of growing without bound.
+func priorityTier(priority Priority) string {
+	switch {
+	case priority >= PriorityUrgent:
+		return "urgent"
+	case priority >= PriorityHigh:
+		return "high"
+	case priority >= PriorityNormal:
+		return "normal"
+	default:
+		return "low"
+	}
+}
+
+func jobTypePriorityKey(jobType string, priority Priority) string {
+	return jobType + ":" + priorityTier(priority)
+}
+
+// p2Estimator implements the P² algorithm (Jain & Chlamtac, 1985) for
+// estimating a single quantile from a stream of values in O(1) memory,
+// without storing the samples themselves. This replaces the naive
+// (current+duration)/2 moving average previously used for AverageTime,
+// which skews heavily toward recent samples and can't answer "what's the
+// p95" at all.
+type p2Estimator struct {
+	quantile float64
+	count    int
+	initial  [5]float64
+	n        [5]int
+	np       [5]float64
+	dn       [5]float64
+	q        [5]float64
+}
+
+func newP2Estimator(quantile float64) *p2Estimator {
+	return &p2Estimator{quantile: quantile}
+}
+
+func (p *p2Estimator) Add(x float64) {
+	p.count++
+
+	if p.count <= 5 {
+		p.initial[p.count-1] = x
+		if p.count == 5 {
+			sort.Float64s(p.initial[:])
+			p.q = p.initial
+			for i := range p.n {
+				p.n[i] = i + 1
+			}
+			p.np = [5]float64{1, 1 + 2*p.quantile, 1 + 4*p.quantile, 3 + 2*p.quantile, 5}
+			p.dn = [5]float64{0, p.quantile / 2, p.quantile, (1 + p.quantile) / 2, 1}
+		}
+		return
+	}
+
+	k := 0
+	switch {
+	case x < p.q[0]:
+		p.q[0] = x
+	case x < p.q[1]:
+		k = 0
+	case x < p.q[2]:
+		k = 1
+	case x < p.q[3]:
+		k = 2
+	case x < p.q[4]:
+		k = 3
+	default:
+		p.q[4] = x
+		k = 3
+	}
+
+	for i := k + 1; i < 5; i++ {
+		p.n[i]++
+	}
+	for i := range p.np {
+		p.np[i] += p.dn[i]
+	}
+
+	for i := 1; i <= 3; i++ {
+		d := p.np[i] - float64(p.n[i])
+		if (d >= 1 && p.n[i+1]-p.n[i] > 1) || (d <= -1 && p.n[i-1]-p.n[i] < -1) {
+			sign := 1.0
+			if d < 0 {
+				sign = -1.0
+			}
+			qNew := p.parabolic(i, sign)
+			if p.q[i-1] < qNew && qNew < p.q[i+1] {
+				p.q[i] = qNew
+			} else {
+				p.q[i] = p.linear(i, sign)
+			}
+			p.n[i] += int(sign)
+		}
+	}
+}
+
+func (p *p2Estimator) parabolic(i int, d float64) float64 {
+	return p.q[i] + d/float64(p.n[i+1]-p.n[i-1])*
+		((float64(p.n[i]-p.n[i-1])+d)*(p.q[i+1]-p.q[i])/float64(p.n[i+1]-p.n[i])+
+			(float64(p.n[i+1]-p.n[i])-d)*(p.q[i]-p.q[i-1])/float64(p.n[i]-p.n[i-1]))
+}
+
+func (p *p2Estimator) linear(i int, d float64) float64 {
+	sign := int(d)
+	return p.q[i] + d*(p.q[i+sign]-p.q[i])/float64(p.n[i+sign]-p.n[i])
+}
+
+// Value returns the current quantile estimate, or 0 if no samples have
+// been added yet.
+func (p *p2Estimator) Value() float64 {
+	if p.count == 0 {
+		return 0
+	}
+	if p.count < 5 {
+		sorted := append([]float64(nil), p.initial[:p.count]...)
+		sort.Float64s(sorted)
+		idx := int(p.quantile * float64(len(sorted)-1))
+		return sorted[idx]
+	}
+	return p.q[2]
+}
+
+// QuietHoursPolicy defers send-type jobs that would otherwise run during a
+// recipient's local quiet hours, rescheduling them to the next allowed time
+// instead of sending. This exists to satisfy compliance requirements around
+// customer messaging windows (e.g. no messages between 9pm and 8am).
+type QuietHoursPolicy struct {
+	Enabled       bool   `json:"enabled"`
+	StartHour     int    `json:"start_hour"`      // 0-23, local to the recipient
+	EndHour       int    `json:"end_hour"`        // 0-23, local to the recipient
+	TimezoneField string `json:"timezone_field"`  // key in job.Data holding an IANA timezone, e.g. "timezone"
+	JobTypePrefix string `json:"job_type_prefix"` // only job types with this prefix are gated, e.g. "send_"
 }
 
 type RateLimiter struct {
-	tokens    int
-	maxTokens int
+	tokens     int
+	maxTokens  int
 	refillRate time.Duration
 	lastRefill time.Time
-	mutex     sync.Mutex
+	mutex      sync.Mutex
 }
 
 type QueueConfig struct {
-	MaxWorkers     map[Priority]int `json:"max_workers"`
-	RetryDelay     time.Duration    `json:"retry_delay"`
-	MaxRetries     int              `json:"max_retries"`
-	JobTimeout     time.Duration    `json:"job_timeout"`
-	CleanupInterval time.Duration   `json:"cleanup_interval"`
-	RateLimits     map[string]int   `json:"rate_limits"` // jobs per minute by type
+	WorkerCount     int            `json:"worker_count"`
+	RetryDelay      time.Duration  `json:"retry_delay"`
+	MaxRetries      int            `json:"max_retries"`
+	JobTimeout      time.Duration  `json:"job_timeout"`
+	CleanupInterval time.Duration  `json:"cleanup_interval"`
+	RateLimits      map[string]int `json:"rate_limits"` // jobs per minute by type
 }
 
 func NewQueueManager() *QueueManager {
 	ctx, cancel := context.WithCancel(context.Background())
-	
+
 	qm := &QueueManager{
-		queues:      make(map[Priority][]*Job),
+		jobs:        make(jobHeap, 0),
 		handlers:    make(map[string]JobHandler),
-		workers:     make(map[Priority]int),
+		running:     true,
 		ctx:         ctx,
 		cancel:      cancel,
 		rateLimiter: make(map[string]*RateLimiter),
+		recurring:   make(map[string]*RecurringJob),
+		processing:  make(map[string]*Job),
 		stats: &QueueStats{
-			PendingJobs: make(map[Priority]int),
-			JobsByType:  make(map[string]int64),
-			AverageTime: make(map[string]time.Duration),
-			LastUpdated: time.Now(),
+			PendingJobs:       make(map[Priority]int),
+			JobsByType:        make(map[string]int64),
+			AverageTime:       make(map[string]time.Duration),
+			ByTypeAndPriority: make(map[string]*JobTypePriorityStats),
+			LastUpdated:       time.Now(),
+			detail:            make(map[string]*jobTypePriorityTracker),
+			typeMedian:        make(map[string]*p2Estimator),
+		},
+		quietHours: &QuietHoursPolicy{
+			Enabled:       false,
+			StartHour:     21,
+			EndHour:       8,
+			TimezoneField: "timezone",
+			JobTypePrefix: "send_",
+		},
+		retryPolicy: &RetryPolicy{
+			BaseDelay: 1 * time.Minute,
+			Jitter:    true,
 		},
 	}
 
-	// Initialize queues for each priority
-	for priority := PriorityLow; priority <= PriorityUrgent; priority++ {
-		qm.queues[priority] = make([]*Job, 0)
-		qm.workers[priority] = 0
-	}
+	heap.Init(&qm.jobs)
 
 	// Set default configuration
 	qm.applyDefaultConfig()
 
+	if err := qm.openResultStore(); err != nil {
+		logrus.Errorf("[QUEUE] Failed to open job result store: %v", err)
+	}
+
 	// Start background processes
 	go qm.startWorkers()
 	go qm.startCleanup()
 	go qm.startStatsUpdater()
+	go qm.startRecurringScheduler()
+	go qm.startLeaseMonitor()
+	go qm.startStuckJobReaper()
 
 	logrus.Info("[QUEUE] Queue manager initialized")
 	return qm
 }
 
-func (qm *QueueManager) applyDefaultConfig() {
-	// Default worker configuration
-	defaultWorkers := map[Priority]int{
-		PriorityUrgent: 5,
-		PriorityHigh:   3,
-		PriorityNormal: 2,
-		PriorityLow:    1,
-	}
+// defaultWorkerCount is the total number of worker goroutines pulling from
+// the shared priority heap. It matches the combined size of the four
+// hardcoded per-tier pools this replaced (5 + 3 + 2 + 1); since a single
+// heap already serves the highest-priority ready job first, one shared pool
+// sized to the old total is sufficient rather than partitioning workers by
+// priority.
+const defaultWorkerCount = 11
 
-	for priority, count := range defaultWorkers {
-		qm.workers[priority] = count
-	}
+func (qm *QueueManager) applyDefaultConfig() {
+	qm.workerCount = defaultWorkerCount
 
 	// Default rate limiters
 	defaultRateLimits := map[string]int{
-		"send_message": 60,  // 60 messages per minute
-		"send_media":   30,  // 30 media files per minute
-		"send_bulk":    10,  // 10 bulk operations per minute
+		"send_message": 60, // 60 messages per minute
+		"send_media":   30, // 30 media files per minute
+		"send_bulk":    10, // 10 bulk operations per minute
 	}
 
 	for jobType, limit := range defaultRateLimits {
@@ -161,13 +526,17 @@ func (qm *QueueManager) applyDefaultConfig() {
 func (qm *QueueManager) RegisterHandler(jobType string, handler JobHandler) {
 	qm.mutex.Lock()
 	defer qm.mutex.Unlock()
-	
+
 	qm.handlers[jobType] = handler
 	logrus.Infof("[QUEUE] Registered handler for job type: %s", jobType)
 }
 
 // AddJob adds a new job to the queue
 func (qm *QueueManager) AddJob(jobType string, data map[string]interface{}, priority Priority) (*Job, error) {
+	if priority < PriorityMin || priority > PriorityMax {
+		return nil, apperr.Validation("QUEUE_INVALID_PRIORITY", fmt.Sprintf("priority must be between %d and %d, got %d", PriorityMin, PriorityMax, priority))
+	}
+
 	job := &Job{
 		ID:          qm.generateJobID(),
 		Type:        jobType,
@@ -184,11 +553,11 @@ func (qm *QueueManager) AddJob(jobType string, data map[string]interface{}, prio
 
 	// Check rate limiting
 	if !qm.checkRateLimit(jobType) {
-		return nil, fmt.Errorf("rate limit exceeded for job type: %s", jobType)
+		return nil, apperr.RateLimited("QUEUE_RATE_LIMITED", fmt.Sprintf("rate limit exceeded for job type: %s", jobType))
 	}
 
 	qm.jobMutex.Lock()
-	qm.queues[priority] = append(qm.queues[priority], job)
+	heap.Push(&qm.jobs, job)
 	qm.jobMutex.Unlock()
 
 	// Update stats
@@ -200,6 +569,7 @@ func (qm *QueueManager) AddJob(jobType string, data map[string]interface{}, prio
 	qm.stats.mutex.Unlock()
 
 	logrus.Debugf("[QUEUE] Added job %s (type: %s, priority: %d)", job.ID, jobType, priority)
+	qm.emitJobEvent(job, "enqueued")
 	return job, nil
 }
 
@@ -215,20 +585,119 @@ func (qm *QueueManager) ScheduleJob(jobType string, data map[string]interface{},
 	return job, nil
 }
 
+// RegisterRecurringJob schedules jobType to be added to the queue every
+// interval, starting immediately. Registering again with the same id
+// replaces the existing schedule (and resets NextRunAt to now).
+func (qm *QueueManager) RegisterRecurringJob(id, jobType string, data map[string]interface{}, priority Priority, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+
+	qm.recurringMutex.Lock()
+	defer qm.recurringMutex.Unlock()
+
+	qm.recurring[id] = &RecurringJob{
+		ID:        id,
+		JobType:   jobType,
+		Data:      data,
+		Priority:  priority,
+		Interval:  interval,
+		NextRunAt: time.Now(),
+	}
+
+	logrus.Infof("[QUEUE] Registered recurring job %s (type: %s, every %s)", id, jobType, interval)
+	return nil
+}
+
+// AddJobWithDependencies adds a job that only becomes eligible to run once
+// every job listed in dependsOn has reached StatusCompleted.
+func (qm *QueueManager) AddJobWithDependencies(jobType string, data map[string]interface{}, priority Priority, dependsOn []string) (*Job, error) {
+	job, err := qm.AddJob(jobType, data, priority)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Dependencies = dependsOn
+	logrus.Debugf("[QUEUE] Job %s depends on %v", job.ID, dependsOn)
+	return job, nil
+}
+
+// Pause stops workers from picking up new jobs. Jobs already being processed
+// run to completion; queued jobs stay queued until Resume is called.
+func (qm *QueueManager) Pause() {
+	qm.mutex.Lock()
+	qm.running = false
+	qm.mutex.Unlock()
+
+	logrus.Info("[QUEUE] Queue paused")
+}
+
+// Resume allows workers to resume picking up jobs after Pause.
+func (qm *QueueManager) Resume() {
+	qm.mutex.Lock()
+	qm.running = true
+	qm.mutex.Unlock()
+
+	logrus.Info("[QUEUE] Queue resumed")
+}
+
+// IsPaused reports whether the queue is currently paused.
+func (qm *QueueManager) IsPaused() bool {
+	qm.mutex.RLock()
+	defer qm.mutex.RUnlock()
+
+	return !qm.running
+}
+
 // GetJob retrieves a job by ID
 func (qm *QueueManager) GetJob(jobID string) (*Job, error) {
 	qm.jobMutex.RLock()
 	defer qm.jobMutex.RUnlock()
 
-	for _, queue := range qm.queues {
-		for _, job := range queue {
-			if job.ID == jobID {
-				return job, nil
-			}
+	if job := qm.findJobLocked(jobID); job != nil {
+		return job, nil
+	}
+
+	return nil, apperr.NotFound("QUEUE_JOB_NOT_FOUND", fmt.Sprintf("job not found: %s", jobID))
+}
+
+// findJobLocked searches the priority heap for a job by ID. Callers must
+// hold qm.jobMutex (read or write).
+func (qm *QueueManager) findJobLocked(jobID string) *Job {
+	for _, job := range qm.jobs {
+		if job.ID == jobID {
+			return job
+		}
+	}
+
+	return nil
+}
+
+// heapIndexOfLocked returns job's current index within the priority heap, or
+// -1 if it's not present. Callers must hold qm.jobMutex and must call this
+// immediately before mutating the job's ScheduledAt and passing the result
+// to heap.Fix - any intervening heap.Push/Pop/Fix on qm.jobs invalidates it.
+func (qm *QueueManager) heapIndexOfLocked(job *Job) int {
+	for i, candidate := range qm.jobs {
+		if candidate == job {
+			return i
+		}
+	}
+	return -1
+}
+
+// unmetDependenciesLocked returns the IDs from job.Dependencies that have not
+// reached StatusCompleted yet. Callers must hold qm.jobMutex.
+func (qm *QueueManager) unmetDependenciesLocked(job *Job) []string {
+	var unmet []string
+	for _, depID := range job.Dependencies {
+		dep := qm.findJobLocked(depID)
+		if dep == nil || dep.Status != StatusCompleted {
+			unmet = append(unmet, depID)
 		}
 	}
 
-	return nil, fmt.Errorf("job not found: %s", jobID)
+	return unmet
 }
 
 // CancelJob cancels a pending job
@@ -236,25 +705,89 @@ func (qm *QueueManager) CancelJob(jobID string) error {
 	qm.jobMutex.Lock()
 	defer qm.jobMutex.Unlock()
 
-	for priority, queue := range qm.queues {
-		for i, job := range queue {
-			if job.ID == jobID && job.Status == StatusPending {
-				job.Status = StatusCancelled
-				// Remove from queue
-				qm.queues[priority] = append(queue[:i], queue[i+1:]...)
-				
-				// Update stats
-				qm.stats.mutex.Lock()
-				qm.stats.PendingJobs[priority]--
-				qm.stats.mutex.Unlock()
-				
-				logrus.Infof("[QUEUE] Cancelled job %s", jobID)
-				return nil
-			}
+	for i, job := range qm.jobs {
+		if job.ID == jobID && job.Status == StatusPending {
+			job.Status = StatusCancelled
+			heap.Remove(&qm.jobs, i)
+
+			// Update stats
+			qm.stats.mutex.Lock()
+			qm.stats.PendingJobs[job.Priority]--
+			qm.stats.mutex.Unlock()
+
+			logrus.Infof("[QUEUE] Cancelled job %s", jobID)
+			return nil
 		}
 	}
 
-	return fmt.Errorf("job not found or cannot be cancelled: %s", jobID)
+	return apperr.NotFound("QUEUE_JOB_NOT_CANCELLABLE", fmt.Sprintf("job not found or cannot be cancelled: %s", jobID))
+}
+
+// SetQuietHoursPolicy replaces the active quiet-hours policy used to gate
+// send-type jobs.
+func (qm *QueueManager) SetQuietHoursPolicy(policy *QuietHoursPolicy) {
+	qm.quietHoursMutex.Lock()
+	defer qm.quietHoursMutex.Unlock()
+	qm.quietHours = policy
+	logrus.Infof("[QUEUE] Quiet hours policy updated: enabled=%v window=%02d:00-%02d:00", policy.Enabled, policy.StartHour, policy.EndHour)
+}
+
+// GetQuietHoursPolicy returns the active quiet-hours policy.
+func (qm *QueueManager) GetQuietHoursPolicy() *QuietHoursPolicy {
+	qm.quietHoursMutex.RLock()
+	defer qm.quietHoursMutex.RUnlock()
+	policy := *qm.quietHours
+	return &policy
+}
+
+// deferredJobRunTime returns the job's ScheduledAt unchanged if the
+// quiet-hours policy doesn't apply to it (not a gated type, no recipient
+// timezone, or the policy is disabled); otherwise it returns the next time
+// the job is allowed to run, deferring it out of the recipient's quiet
+// window.
+func (qm *QueueManager) deferredJobRunTime(job *Job) (time.Time, bool) {
+	qm.quietHoursMutex.RLock()
+	policy := qm.quietHours
+	qm.quietHoursMutex.RUnlock()
+
+	if policy == nil || !policy.Enabled || policy.StartHour == policy.EndHour {
+		return job.ScheduledAt, false
+	}
+	if policy.JobTypePrefix != "" && !strings.HasPrefix(job.Type, policy.JobTypePrefix) {
+		return job.ScheduledAt, false
+	}
+
+	tzName, ok := job.Data[policy.TimezoneField].(string)
+	if !ok || tzName == "" {
+		return job.ScheduledAt, false
+	}
+
+	loc, err := time.LoadLocation(tzName)
+	if err != nil {
+		return job.ScheduledAt, false
+	}
+
+	now := time.Now()
+	local := now.In(loc)
+	if !inQuietHours(local.Hour(), policy.StartHour, policy.EndHour) {
+		return job.ScheduledAt, false
+	}
+
+	nextAllowed := time.Date(local.Year(), local.Month(), local.Day(), policy.EndHour, 0, 0, 0, loc)
+	if !nextAllowed.After(local) {
+		nextAllowed = nextAllowed.AddDate(0, 0, 1)
+	}
+
+	return nextAllowed, true
+}
+
+// inQuietHours reports whether hour falls within [start, end), wrapping past
+// midnight when start > end (e.g. 21 -> 8).
+func inQuietHours(hour, start, end int) bool {
+	if start < end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
 }
 
 // GetQueueStats returns current queue statistics
@@ -264,14 +797,16 @@ func (qm *QueueManager) GetQueueStats() *QueueStats {
 
 	// Create a copy to avoid race conditions
 	stats := &QueueStats{
-		TotalJobs:      qm.stats.TotalJobs,
-		CompletedJobs:  qm.stats.CompletedJobs,
-		FailedJobs:     qm.stats.FailedJobs,
-		ProcessingJobs: qm.stats.ProcessingJobs,
-		PendingJobs:    make(map[Priority]int),
-		JobsByType:     make(map[string]int64),
-		AverageTime:    make(map[string]time.Duration),
-		LastUpdated:    qm.stats.LastUpdated,
+		TotalJobs:         qm.stats.TotalJobs,
+		CompletedJobs:     qm.stats.CompletedJobs,
+		FailedJobs:        qm.stats.FailedJobs,
+		DeferredJobs:      qm.stats.DeferredJobs,
+		ProcessingJobs:    qm.stats.ProcessingJobs,
+		PendingJobs:       make(map[Priority]int),
+		JobsByType:        make(map[string]int64),
+		AverageTime:       make(map[string]time.Duration),
+		ByTypeAndPriority: make(map[string]*JobTypePriorityStats),
+		LastUpdated:       qm.stats.LastUpdated,
 	}
 
 	for k, v := range qm.stats.PendingJobs {
@@ -283,60 +818,211 @@ func (qm *QueueManager) GetQueueStats() *QueueStats {
 	for k, v := range qm.stats.AverageTime {
 		stats.AverageTime[k] = v
 	}
+	for k, tracker := range qm.stats.detail {
+		stats.ByTypeAndPriority[k] = tracker.snapshot()
+	}
 
 	return stats
 }
 
+// QueueState is a serializable snapshot of all jobs and recurring
+// definitions currently held by the queue manager. Jobs aren't grouped by
+// priority since priority is now a continuous 0-100 value; ImportState
+// re-establishes heap order itself. Only non-terminal jobs (not completed,
+// failed, or cancelled) are included, since a migration or backup has no use
+// for jobs that will never run again.
+type QueueState struct {
+	Jobs       []*Job          `json:"jobs"`
+	Recurring  []*RecurringJob `json:"recurring"`
+	ExportedAt time.Time       `json:"exported_at"`
+}
+
+// ExportState serializes every non-terminal job and recurring job
+// definition currently held by the queue, for backup or migration to
+// another queue manager instance.
+func (qm *QueueManager) ExportState() ([]byte, error) {
+	qm.jobMutex.RLock()
+	jobs := make([]*Job, 0, len(qm.jobs))
+	for _, job := range qm.jobs {
+		switch job.Status {
+		case StatusCompleted, StatusFailed, StatusCancelled:
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	qm.jobMutex.RUnlock()
+
+	qm.recurringMutex.RLock()
+	recurring := make([]*RecurringJob, 0, len(qm.recurring))
+	for _, job := range qm.recurring {
+		recurring = append(recurring, job)
+	}
+	qm.recurringMutex.RUnlock()
+
+	state := &QueueState{
+		Jobs:       jobs,
+		Recurring:  recurring,
+		ExportedAt: time.Now(),
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return nil, apperr.Internal("QUEUE_EXPORT_FAILED", "failed to serialize queue state", err)
+	}
+
+	return data, nil
+}
+
+// ImportState restores jobs and recurring definitions from a snapshot
+// produced by ExportState. When merge is false, the current queue and
+// recurring schedule are replaced wholesale. When merge is true, the
+// imported jobs and recurring definitions are added alongside whatever is
+// already queued or registered, and any job or recurring ID that collides
+// with an existing one is regenerated so the merge never silently clobbers
+// in-flight work. Jobs that were mid-flight ("processing") when the
+// snapshot was taken are reset to pending so a worker can pick them back up.
+func (qm *QueueManager) ImportState(data []byte, merge bool) error {
+	if len(data) == 0 {
+		return apperr.Validation("QUEUE_STATE_REQUIRED", "queue state is required")
+	}
+
+	var state QueueState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return apperr.Validation("QUEUE_STATE_INVALID", fmt.Sprintf("invalid queue state: %v", err))
+	}
+
+	for _, job := range state.Jobs {
+		if job.Status == StatusProcessing {
+			job.Status = StatusPending
+			job.StartedAt = nil
+		}
+	}
+
+	qm.jobMutex.Lock()
+	if merge {
+		existingIDs := make(map[string]bool, len(qm.jobs))
+		for _, job := range qm.jobs {
+			existingIDs[job.ID] = true
+		}
+		for _, job := range state.Jobs {
+			if existingIDs[job.ID] {
+				job.ID = qm.generateJobID()
+			}
+			existingIDs[job.ID] = true
+			heap.Push(&qm.jobs, job)
+		}
+	} else {
+		newJobs := make(jobHeap, 0, len(state.Jobs))
+		newJobs = append(newJobs, state.Jobs...)
+		qm.jobs = newJobs
+		heap.Init(&qm.jobs)
+	}
+	imported := len(state.Jobs)
+	qm.jobMutex.Unlock()
+
+	qm.recurringMutex.Lock()
+	if !merge {
+		qm.recurring = make(map[string]*RecurringJob)
+	}
+	for _, job := range state.Recurring {
+		if _, exists := qm.recurring[job.ID]; exists && merge {
+			job.ID = fmt.Sprintf("%s_%d", job.ID, time.Now().UnixNano())
+		}
+		qm.recurring[job.ID] = job
+	}
+	qm.recurringMutex.Unlock()
+
+	qm.updateCurrentStats()
+	logrus.Infof("[QUEUE] Imported %d jobs and %d recurring definitions from exported state (captured at %s, merge=%v)", imported, len(state.Recurring), state.ExportedAt.Format(time.RFC3339), merge)
+	return nil
+}
+
 // ListJobs returns jobs with optional filtering
-func (qm *QueueManager) ListJobs(status JobStatus, jobType string, limit int) []*Job {
+func (qm *QueueManager) ListJobs(status JobStatus, jobType, sortBy, sortDir string, limit int) []*Job {
 	qm.jobMutex.RLock()
 	defer qm.jobMutex.RUnlock()
 
 	var jobs []*Job
-	count := 0
-
-	// Search in all priority queues
-	for priority := PriorityUrgent; priority >= PriorityLow; priority-- {
-		for _, job := range qm.queues[priority] {
-			if limit > 0 && count >= limit {
-				break
-			}
 
-			if (status == "" || job.Status == status) &&
-				(jobType == "" || job.Type == jobType) {
-				jobs = append(jobs, job)
-				count++
-			}
+	// Collect the full candidate set so limit is applied after sorting
+	// rather than cutting off traversal early.
+	for _, job := range qm.jobs {
+		if (status == "" || job.Status == status) &&
+			(jobType == "" || job.Type == jobType) {
+			jobs = append(jobs, job)
 		}
-		if limit > 0 && count >= limit {
-			break
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		cmp := compareJobs(jobs[i], jobs[j], sortBy)
+		if sortDir == "desc" {
+			return cmp > 0
 		}
+		return cmp < 0
+	})
+
+	if limit > 0 && limit < len(jobs) {
+		jobs = jobs[:limit]
 	}
 
 	return jobs
 }
 
+// compareJobs orders two jobs by sortBy ("scheduled_at", "created_at", or
+// the default "priority"), falling back to ID as a tie-breaker so the order
+// is stable regardless of insertion order within a priority bucket.
+func compareJobs(a, b *Job, sortBy string) int {
+	switch sortBy {
+	case "scheduled_at":
+		if !a.ScheduledAt.Equal(b.ScheduledAt) {
+			if a.ScheduledAt.Before(b.ScheduledAt) {
+				return -1
+			}
+			return 1
+		}
+	case "created_at":
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			if a.CreatedAt.Before(b.CreatedAt) {
+				return -1
+			}
+			return 1
+		}
+	default:
+		if a.Priority != b.Priority {
+			if a.Priority < b.Priority {
+				return -1
+			}
+			return 1
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			if a.CreatedAt.Before(b.CreatedAt) {
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(a.ID, b.ID)
+}
+
 // Private methods
 
 func (qm *QueueManager) startWorkers() {
-	for priority, workerCount := range qm.workers {
-		for i := 0; i < workerCount; i++ {
-			go qm.worker(priority, i)
-		}
+	for i := 0; i < qm.workerCount; i++ {
+		go qm.worker(i)
 	}
-	logrus.Info("[QUEUE] Started all workers")
+	logrus.Infof("[QUEUE] Started %d workers", qm.workerCount)
 }
 
-func (qm *QueueManager) worker(priority Priority, workerID int) {
-	logrus.Infof("[QUEUE] Worker %d started for priority %d", workerID, priority)
-	
+func (qm *QueueManager) worker(workerID int) {
+	logrus.Infof("[QUEUE] Worker %d started", workerID)
+
 	for {
 		select {
 		case <-qm.ctx.Done():
-			logrus.Infof("[QUEUE] Worker %d (priority %d) stopping", workerID, priority)
+			logrus.Infof("[QUEUE] Worker %d stopping", workerID)
 			return
 		default:
-			job := qm.getNextJob(priority)
+			job := qm.getNextJob()
 			if job == nil {
 				time.Sleep(100 * time.Millisecond)
 				continue
@@ -347,36 +1033,69 @@ func (qm *QueueManager) worker(priority Priority, workerID int) {
 	}
 }
 
-func (qm *QueueManager) getNextJob(priority Priority) *Job {
+// getNextJob pops the highest-priority eligible-to-run job off the heap.
+// Jobs that aren't yet ready (future ScheduledAt, unmet dependencies, or
+// deferred by quiet hours) are popped and pushed back so the heap keeps its
+// invariant, since eligibility depends on more than just priority order.
+func (qm *QueueManager) getNextJob() *Job {
+	if qm.IsPaused() {
+		return nil
+	}
+
 	qm.jobMutex.Lock()
 	defer qm.jobMutex.Unlock()
 
-	queue := qm.queues[priority]
-	if len(queue) == 0 {
-		return nil
-	}
+	var skipped []*Job
+	var found *Job
+
+	for qm.jobs.Len() > 0 {
+		job := heap.Pop(&qm.jobs).(*Job)
+
+		if job.Status != StatusPending || !time.Now().After(job.ScheduledAt) {
+			skipped = append(skipped, job)
+			continue
+		}
 
-	// Find the first job that's ready to run
-	for i, job := range queue {
-		if job.Status == StatusPending && time.Now().After(job.ScheduledAt) {
-			// Remove from queue
-			qm.queues[priority] = append(queue[:i], queue[i+1:]...)
-			
-			// Update status and stats
-			job.Status = StatusProcessing
-			now := time.Now()
-			job.StartedAt = &now
-			
+		if len(qm.unmetDependenciesLocked(job)) > 0 {
+			skipped = append(skipped, job)
+			continue
+		}
+
+		if runAt, deferred := qm.deferredJobRunTime(job); deferred {
+			job.ScheduledAt = runAt
 			qm.stats.mutex.Lock()
-			qm.stats.PendingJobs[priority]--
-			qm.stats.ProcessingJobs++
+			qm.stats.DeferredJobs++
 			qm.stats.mutex.Unlock()
-			
-			return job
+			logrus.Debugf("[QUEUE] Deferred job %s (type: %s) past quiet hours until %s", job.ID, job.Type, runAt.Format(time.RFC3339))
+			skipped = append(skipped, job)
+			continue
 		}
+
+		found = job
+		break
 	}
 
-	return nil
+	for _, job := range skipped {
+		heap.Push(&qm.jobs, job)
+	}
+
+	if found == nil {
+		return nil
+	}
+
+	// Update status and stats
+	found.Status = StatusProcessing
+	now := time.Now()
+	found.StartedAt = &now
+
+	qm.stats.mutex.Lock()
+	qm.stats.PendingJobs[found.Priority]--
+	qm.stats.ProcessingJobs++
+	qm.stats.mutex.Unlock()
+
+	qm.trackProcessing(found)
+	qm.emitJobEvent(found, "started")
+	return found
 }
 
 func (qm *QueueManager) processJob(job *Job) {
@@ -384,7 +1103,10 @@ func (qm *QueueManager) processJob(job *Job) {
 		if r := recover(); r != nil {
 			job.Error = fmt.Sprintf("panic: %v", r)
 			job.Status = StatusFailed
+			qm.untrackProcessing(job.ID)
+			qm.updateJobStats(job)
 			logrus.Errorf("[QUEUE] Job %s panicked: %v", job.ID, r)
+			qm.emitJobEvent(job, "failed")
 		}
 	}()
 
@@ -398,6 +1120,7 @@ func (qm *QueueManager) processJob(job *Job) {
 	if !exists {
 		job.Error = fmt.Sprintf("no handler registered for job type: %s", job.Type)
 		job.Status = StatusFailed
+		qm.untrackProcessing(job.ID)
 		qm.updateJobStats(job)
 		return
 	}
@@ -409,7 +1132,7 @@ func (qm *QueueManager) processJob(job *Job) {
 	// Execute job
 	job.Attempts++
 	startTime := time.Now()
-	
+
 	err := handler(ctx, job)
 	duration := time.Since(startTime)
 
@@ -419,30 +1142,35 @@ func (qm *QueueManager) processJob(job *Job) {
 
 	if err != nil {
 		job.Error = err.Error()
-		
+
 		// Retry logic
 		if job.Attempts < job.MaxAttempts {
 			job.Status = StatusRetrying
-			job.ScheduledAt = time.Now().Add(time.Duration(job.Attempts) * time.Minute)
-			
+			job.ScheduledAt = time.Now().Add(qm.calculateRetryDelay(job.Attempts))
+
 			// Re-add to queue
 			qm.jobMutex.Lock()
-			qm.queues[job.Priority] = append(qm.queues[job.Priority], job)
+			heap.Push(&qm.jobs, job)
 			qm.jobMutex.Unlock()
-			
-			logrus.Warnf("[QUEUE] Job %s failed, retrying (attempt %d/%d): %v", 
+
+			logrus.Warnf("[QUEUE] Job %s failed, retrying (attempt %d/%d): %v",
 				job.ID, job.Attempts, job.MaxAttempts, err)
+			qm.emitJobEvent(job, "retrying")
 		} else {
 			job.Status = StatusFailed
 			logrus.Errorf("[QUEUE] Job %s failed permanently: %v", job.ID, err)
+			qm.emitJobEvent(job, "failed")
 		}
 	} else {
 		job.Status = StatusCompleted
 		logrus.Debugf("[QUEUE] Job %s completed successfully in %v", job.ID, duration)
+		qm.emitJobEvent(job, "completed")
 	}
 
+	qm.untrackProcessing(job.ID)
 	qm.updateJobStats(job)
 	qm.updateAverageTime(job.Type, duration)
+	qm.recordTypePriorityOutcome(job, job.Status == StatusCompleted, duration)
 }
 
 func (qm *QueueManager) updateJobStats(job *Job) {
@@ -450,7 +1178,7 @@ func (qm *QueueManager) updateJobStats(job *Job) {
 	defer qm.stats.mutex.Unlock()
 
 	qm.stats.ProcessingJobs--
-	
+
 	if job.Status == StatusCompleted {
 		qm.stats.CompletedJobs++
 	} else if job.Status == StatusFailed {
@@ -459,23 +1187,45 @@ func (qm *QueueManager) updateJobStats(job *Job) {
 		qm.stats.PendingJobs[job.Priority]++
 		qm.stats.ProcessingJobs-- // Will be incremented again when retried
 	}
-	
+
 	qm.stats.LastUpdated = time.Now()
 }
 
+// updateAverageTime tracks AverageTime[jobType] as a running median rather
+// than a (current+duration)/2 moving average: that naive average skews
+// heavily toward the most recent sample and converges slowly, which made it
+// a poor stand-in for "typical" processing time.
 func (qm *QueueManager) updateAverageTime(jobType string, duration time.Duration) {
 	qm.stats.mutex.Lock()
 	defer qm.stats.mutex.Unlock()
 
-	if current, exists := qm.stats.AverageTime[jobType]; exists {
-		// Simple moving average
-		qm.stats.AverageTime[jobType] = (current + duration) / 2
-	} else {
-		qm.stats.AverageTime[jobType] = duration
+	estimator, exists := qm.stats.typeMedian[jobType]
+	if !exists {
+		estimator = newP2Estimator(0.50)
+		qm.stats.typeMedian[jobType] = estimator
 	}
+	estimator.Add(float64(duration.Milliseconds()))
+	qm.stats.AverageTime[jobType] = time.Duration(estimator.Value()) * time.Millisecond
 }
 
-func (qm *QueueManager) checkRateLimit(jobType string) bool {
+// recordTypePriorityOutcome tracks processing-time percentiles and success
+// rates broken down by (job type, priority tier), so callers can tell
+// whether high-priority jobs actually get the latency benefit they should
+// over normal-priority ones.
+func (qm *QueueManager) recordTypePriorityOutcome(job *Job, success bool, duration time.Duration) {
+	qm.stats.mutex.Lock()
+	defer qm.stats.mutex.Unlock()
+
+	key := jobTypePriorityKey(job.Type, job.Priority)
+	tracker, exists := qm.stats.detail[key]
+	if !exists {
+		tracker = newJobTypePriorityTracker()
+		qm.stats.detail[key] = tracker
+	}
+	tracker.record(success, duration)
+}
+
+func (qm *QueueManager) checkRateLimit(jobType string) bool {
 	limiter, exists := qm.rateLimiter[jobType]
 	if !exists {
 		return true // No rate limit configured
@@ -500,6 +1250,52 @@ func (qm *QueueManager) checkRateLimit(jobType string) bool {
 	return false
 }
 
+// rateLimitAvailable reports whether jobType currently has a token available,
+// without consuming one. Used by DiagnoseJob so inspecting a job doesn't
+// itself affect its rate limit.
+func (qm *QueueManager) rateLimitAvailable(jobType string) bool {
+	limiter, exists := qm.rateLimiter[jobType]
+	if !exists {
+		return true
+	}
+
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	if time.Since(limiter.lastRefill) >= limiter.refillRate {
+		return true
+	}
+
+	return limiter.tokens > 0
+}
+
+// GetRateLimitState reports the current token-bucket state for jobType
+// without consuming a token, so an external caller can pace itself instead
+// of submitting a job only to have it rejected by checkRateLimit. remaining
+// is the number of tokens available right now; resetAt is when the bucket
+// next refills to its max. A jobType with no rate limit configured reports
+// remaining as -1 (unlimited) and a zero resetAt.
+//
+// This reflects only qm's own in-process token bucket. rateLimiter is not
+// currently backed by Redis, so in a multi-instance deployment each instance
+// enforces (and reports) its own independent limit rather than a cluster-wide
+// one.
+func (qm *QueueManager) GetRateLimitState(jobType string) (remaining int, resetAt time.Time) {
+	limiter, exists := qm.rateLimiter[jobType]
+	if !exists {
+		return -1, time.Time{}
+	}
+
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	if time.Since(limiter.lastRefill) >= limiter.refillRate {
+		return limiter.maxTokens, time.Now()
+	}
+
+	return limiter.tokens, limiter.lastRefill.Add(limiter.refillRate)
+}
+
 func (qm *QueueManager) startCleanup() {
 	ticker := time.NewTicker(1 * time.Hour)
 	defer ticker.Stop()
@@ -514,31 +1310,605 @@ func (qm *QueueManager) startCleanup() {
 	}
 }
 
+// startRecurringScheduler periodically checks registered recurring jobs and
+// submits any that are due as a normal job via AddJob.
+func (qm *QueueManager) startRecurringScheduler() {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-qm.ctx.Done():
+			return
+		case <-ticker.C:
+			qm.runDueRecurringJobs()
+		}
+	}
+}
+
+func (qm *QueueManager) runDueRecurringJobs() {
+	qm.recurringMutex.Lock()
+	defer qm.recurringMutex.Unlock()
+
+	now := time.Now()
+	for _, rj := range qm.recurring {
+		if now.Before(rj.NextRunAt) {
+			continue
+		}
+
+		job, err := qm.AddJob(rj.JobType, rj.Data, rj.Priority)
+		if err != nil {
+			logrus.Errorf("[QUEUE] Failed to submit recurring job %s: %v", rj.ID, err)
+			continue
+		}
+
+		rj.LastJobID = job.ID
+		rj.NextRunAt = now.Add(rj.Interval)
+		logrus.Debugf("[QUEUE] Submitted recurring job %s as %s, next run at %s", rj.ID, job.ID, rj.NextRunAt.Format(time.RFC3339))
+	}
+}
+
 func (qm *QueueManager) cleanupCompletedJobs() {
 	cutoff := time.Now().Add(-24 * time.Hour) // Keep jobs for 24 hours
-	
+
 	qm.jobMutex.Lock()
 	defer qm.jobMutex.Unlock()
 
 	cleaned := 0
-	for priority, queue := range qm.queues {
-		var newQueue []*Job
-		for _, job := range queue {
-			if (job.Status == StatusCompleted || job.Status == StatusFailed) && 
-				job.CompletedAt != nil && job.CompletedAt.Before(cutoff) {
-				cleaned++
-				continue
-			}
-			newQueue = append(newQueue, job)
+	var kept jobHeap
+	for _, job := range qm.jobs {
+		if (job.Status == StatusCompleted || job.Status == StatusFailed) &&
+			job.CompletedAt != nil && job.CompletedAt.Before(cutoff) {
+			qm.persistJobResult(job)
+			cleaned++
+			continue
 		}
-		qm.queues[priority] = newQueue
+		kept = append(kept, job)
 	}
+	qm.jobs = kept
+	heap.Init(&qm.jobs)
 
 	if cleaned > 0 {
 		logrus.Infof("[QUEUE] Cleaned up %d old jobs", cleaned)
 	}
 }
 
+// openResultStore opens (creating if necessary) the SQLite database used to
+// persist terminal jobs' results past in-memory cleanup, so they remain
+// available for reconciliation days later via GetJobResult.
+func (qm *QueueManager) openResultStore() error {
+	if err := os.MkdirAll(config.PathStorages, 0755); err != nil {
+		return fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	dbPath := filepath.Join(config.PathStorages, "queue_results.db")
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_foreign_keys=on", dbPath))
+	if err != nil {
+		return fmt.Errorf("failed to open job result store: %w", err)
+	}
+
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS job_results (
+		id TEXT PRIMARY KEY,
+		type TEXT NOT NULL,
+		status TEXT NOT NULL,
+		result TEXT,
+		error TEXT,
+		completed_at DATETIME,
+		data TEXT,
+		priority INTEGER
+	)`); err != nil {
+		db.Close()
+		return fmt.Errorf("failed to create job_results table: %w", err)
+	}
+
+	// job_results predates the data/priority columns, so a database created by
+	// an older build won't have them yet - add them if missing and ignore the
+	// "duplicate column" error on databases that already have them.
+	for _, stmt := range []string{
+		`ALTER TABLE job_results ADD COLUMN data TEXT`,
+		`ALTER TABLE job_results ADD COLUMN priority INTEGER`,
+	} {
+		if _, err := db.Exec(stmt); err != nil && !strings.Contains(err.Error(), "duplicate column") {
+			db.Close()
+			return fmt.Errorf("failed to migrate job_results table: %w", err)
+		}
+	}
+
+	qm.resultStore = db
+	return nil
+}
+
+// persistJobResult writes a terminal job's result to the job_results table
+// before it is dropped from memory by cleanupCompletedJobs.
+func (qm *QueueManager) persistJobResult(job *Job) {
+	if qm.resultStore == nil {
+		return
+	}
+
+	var resultJSON []byte
+	if job.Result != nil {
+		var err error
+		resultJSON, err = json.Marshal(job.Result)
+		if err != nil {
+			logrus.Errorf("[QUEUE] Failed to marshal result for job %s: %v", job.ID, err)
+			return
+		}
+	}
+
+	var completedAt interface{}
+	if job.CompletedAt != nil {
+		completedAt = *job.CompletedAt
+	}
+
+	var dataJSON []byte
+	if job.Data != nil {
+		var err error
+		dataJSON, err = json.Marshal(job.Data)
+		if err != nil {
+			logrus.Errorf("[QUEUE] Failed to marshal data for job %s: %v", job.ID, err)
+			return
+		}
+	}
+
+	_, err := qm.resultStore.Exec(
+		`INSERT OR REPLACE INTO job_results (id, type, status, result, error, completed_at, data, priority) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		job.ID, job.Type, string(job.Status), string(resultJSON), job.Error, completedAt, string(dataJSON), int(job.Priority),
+	)
+	if err != nil {
+		logrus.Errorf("[QUEUE] Failed to persist result for job %s: %v", job.ID, err)
+	}
+}
+
+// GetJobResult returns the result of a completed job, checking the
+// in-memory queue first and falling back to the persisted job_results
+// table for jobs that have already been cleaned up.
+func (qm *QueueManager) GetJobResult(jobID string) (interface{}, error) {
+	if job, err := qm.GetJob(jobID); err == nil {
+		return job.Result, nil
+	}
+
+	if qm.resultStore == nil {
+		return nil, apperr.NotFound("QUEUE_JOB_NOT_FOUND", fmt.Sprintf("job not found: %s", jobID))
+	}
+
+	var resultJSON, status string
+	row := qm.resultStore.QueryRow(`SELECT status, result FROM job_results WHERE id = ?`, jobID)
+	if err := row.Scan(&status, &resultJSON); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperr.NotFound("QUEUE_JOB_NOT_FOUND", fmt.Sprintf("job not found: %s", jobID))
+		}
+		return nil, apperr.Internal("QUEUE_RESULT_LOOKUP_FAILED", "failed to look up job result", err)
+	}
+
+	if resultJSON == "" {
+		return nil, nil
+	}
+
+	var result interface{}
+	if err := json.Unmarshal([]byte(resultJSON), &result); err != nil {
+		return nil, apperr.Internal("QUEUE_RESULT_DECODE_FAILED", "failed to decode stored job result", err)
+	}
+
+	return result, nil
+}
+
+// RequeueFailed re-submits every failed job of jobType whose CompletedAt is
+// at or after since, checking both the in-memory queue and the persisted
+// job_results table so jobs already cleaned up by cleanupCompletedJobs are
+// still eligible. Each match is cloned into a brand new job via AddJob (fresh
+// ID, Attempts reset to 0) rather than mutated in place, matching how a
+// retried job normally re-enters the queue. It returns the number of jobs
+// requeued.
+func (qm *QueueManager) RequeueFailed(jobType string, since time.Time) (int, error) {
+	type failedJob struct {
+		data     map[string]interface{}
+		priority Priority
+	}
+	var candidates []failedJob
+
+	qm.jobMutex.RLock()
+	for _, job := range qm.jobs {
+		if job.Status == StatusFailed && job.Type == jobType && job.CompletedAt != nil && !job.CompletedAt.Before(since) {
+			candidates = append(candidates, failedJob{data: job.Data, priority: job.Priority})
+		}
+	}
+	qm.jobMutex.RUnlock()
+
+	if qm.resultStore != nil {
+		rows, err := qm.resultStore.Query(
+			`SELECT data, priority FROM job_results WHERE type = ? AND status = ? AND completed_at >= ?`,
+			jobType, string(StatusFailed), since,
+		)
+		if err != nil {
+			return 0, apperr.Internal("QUEUE_REQUEUE_LOOKUP_FAILED", "failed to look up failed jobs", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var dataJSON string
+			var priority int
+			if err := rows.Scan(&dataJSON, &priority); err != nil {
+				return 0, apperr.Internal("QUEUE_REQUEUE_LOOKUP_FAILED", "failed to read failed job row", err)
+			}
+
+			var data map[string]interface{}
+			if dataJSON != "" {
+				if err := json.Unmarshal([]byte(dataJSON), &data); err != nil {
+					logrus.Errorf("[QUEUE] Failed to decode persisted data while requeuing job type %s: %v", jobType, err)
+					continue
+				}
+			}
+			candidates = append(candidates, failedJob{data: data, priority: Priority(priority)})
+		}
+	}
+
+	requeued := 0
+	for _, candidate := range candidates {
+		if _, err := qm.AddJob(jobType, candidate.data, candidate.priority); err != nil {
+			logrus.Errorf("[QUEUE] Failed to requeue a failed %s job: %v", jobType, err)
+			continue
+		}
+		requeued++
+	}
+
+	logrus.Infof("[QUEUE] Requeued %d failed %s job(s) since %s", requeued, jobType, since.Format(time.RFC3339))
+	return requeued, nil
+}
+
+// LeaseJob atomically claims one ready pending job of jobType for an external
+// worker: it marks the job processing and returns it with LeaseExpiresAt set
+// leaseTTL from now, without requiring a handler to be registered for
+// jobType (unlike the internal worker pool, an external worker does its own
+// dispatch). The lease is a promise, not a lock across processes - if the
+// worker never calls AckJob or NackJob before it expires, the lease monitor
+// requeues the job automatically. Returns apperr.NotFound if no eligible job
+// is currently available.
+func (qm *QueueManager) LeaseJob(jobType string, leaseTTL time.Duration) (*Job, error) {
+	if leaseTTL <= 0 {
+		return nil, apperr.Validation("QUEUE_INVALID_LEASE_TTL", "lease TTL must be positive")
+	}
+	if qm.IsPaused() {
+		return nil, apperr.NotFound("QUEUE_NO_JOB_AVAILABLE", fmt.Sprintf("no leasable job available for type: %s", jobType))
+	}
+
+	qm.jobMutex.Lock()
+	defer qm.jobMutex.Unlock()
+
+	var skipped []*Job
+	var found *Job
+
+	for qm.jobs.Len() > 0 {
+		job := heap.Pop(&qm.jobs).(*Job)
+
+		if job.Type != jobType || job.Status != StatusPending || !time.Now().After(job.ScheduledAt) || len(qm.unmetDependenciesLocked(job)) > 0 {
+			skipped = append(skipped, job)
+			continue
+		}
+
+		found = job
+		break
+	}
+
+	for _, job := range skipped {
+		heap.Push(&qm.jobs, job)
+	}
+
+	if found == nil {
+		return nil, apperr.NotFound("QUEUE_NO_JOB_AVAILABLE", fmt.Sprintf("no leasable job available for type: %s", jobType))
+	}
+
+	found.Status = StatusProcessing
+	now := time.Now()
+	found.StartedAt = &now
+	leaseExpiry := now.Add(leaseTTL)
+	found.LeaseExpiresAt = &leaseExpiry
+	heap.Push(&qm.jobs, found)
+
+	qm.stats.mutex.Lock()
+	qm.stats.PendingJobs[found.Priority]--
+	qm.stats.ProcessingJobs++
+	qm.stats.mutex.Unlock()
+
+	logrus.Debugf("[QUEUE] Leased job %s (type: %s) until %s", found.ID, found.Type, leaseExpiry.Format(time.RFC3339))
+	qm.emitJobEvent(found, "leased")
+	return found, nil
+}
+
+// AckJob reports that a leased job finished successfully. It's the external
+// worker's counterpart to a handler returning nil from processJob.
+func (qm *QueueManager) AckJob(jobID string, result interface{}) error {
+	qm.jobMutex.Lock()
+	job := qm.findJobLocked(jobID)
+	if job == nil {
+		qm.jobMutex.Unlock()
+		return apperr.NotFound("QUEUE_JOB_NOT_FOUND", fmt.Sprintf("job not found: %s", jobID))
+	}
+	if job.Status != StatusProcessing {
+		qm.jobMutex.Unlock()
+		return apperr.Conflict("QUEUE_JOB_NOT_LEASED", fmt.Sprintf("job %s is not currently leased: %s", jobID, job.Status))
+	}
+
+	job.Result = result
+	job.Status = StatusCompleted
+	job.LeaseExpiresAt = nil
+	now := time.Now()
+	job.CompletedAt = &now
+	qm.jobMutex.Unlock()
+
+	qm.updateJobStats(job)
+	logrus.Debugf("[QUEUE] Job %s acked by external worker", jobID)
+	qm.emitJobEvent(job, "completed")
+	return nil
+}
+
+// NackJob reports that a leased job failed. If retry is true and the job
+// hasn't exhausted MaxAttempts, it's put back on the queue to be leased or
+// worked again after calculateRetryDelay; otherwise it's marked permanently
+// failed. It's the external worker's counterpart to a handler returning an
+// error from processJob.
+func (qm *QueueManager) NackJob(jobID string, jobErr string, retry bool) error {
+	qm.jobMutex.Lock()
+	job := qm.findJobLocked(jobID)
+	if job == nil {
+		qm.jobMutex.Unlock()
+		return apperr.NotFound("QUEUE_JOB_NOT_FOUND", fmt.Sprintf("job not found: %s", jobID))
+	}
+	if job.Status != StatusProcessing {
+		qm.jobMutex.Unlock()
+		return apperr.Conflict("QUEUE_JOB_NOT_LEASED", fmt.Sprintf("job %s is not currently leased: %s", jobID, job.Status))
+	}
+
+	job.Error = jobErr
+	job.LeaseExpiresAt = nil
+	job.Attempts++
+	now := time.Now()
+	job.CompletedAt = &now
+
+	if retry && job.Attempts < job.MaxAttempts {
+		job.Status = StatusRetrying
+		job.ScheduledAt = time.Now().Add(qm.calculateRetryDelay(job.Attempts))
+		job.StartedAt = nil
+		job.CompletedAt = nil
+		// ScheduledAt is the heap's sort key and this job stays resident in
+		// the heap (LeaseJob pushed it back when it was leased) - restore
+		// the heap invariant rather than leaving it corrupted.
+		if idx := qm.heapIndexOfLocked(job); idx >= 0 {
+			heap.Fix(&qm.jobs, idx)
+		}
+		qm.jobMutex.Unlock()
+
+		logrus.Warnf("[QUEUE] Job %s nacked by external worker, retrying (attempt %d/%d): %s", job.ID, job.Attempts, job.MaxAttempts, jobErr)
+		qm.emitJobEvent(job, "retrying")
+	} else {
+		job.Status = StatusFailed
+		qm.jobMutex.Unlock()
+
+		logrus.Errorf("[QUEUE] Job %s nacked permanently by external worker: %s", job.ID, jobErr)
+		qm.emitJobEvent(job, "failed")
+	}
+
+	qm.updateJobStats(job)
+	return nil
+}
+
+// startLeaseMonitor periodically requeues jobs whose lease expired without
+// the external worker calling AckJob or NackJob, so a crashed or hung worker
+// doesn't strand a job in StatusProcessing forever.
+func (qm *QueueManager) startLeaseMonitor() {
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-qm.ctx.Done():
+			return
+		case <-ticker.C:
+			qm.requeueExpiredLeases()
+		}
+	}
+}
+
+func (qm *QueueManager) requeueExpiredLeases() {
+	qm.jobMutex.Lock()
+	defer qm.jobMutex.Unlock()
+
+	now := time.Now()
+	var expired []*Job
+	for _, job := range qm.jobs {
+		if job.Status != StatusProcessing || job.LeaseExpiresAt == nil || now.Before(*job.LeaseExpiresAt) {
+			continue
+		}
+		expired = append(expired, job)
+	}
+
+	for _, job := range expired {
+		job.Status = StatusPending
+		job.LeaseExpiresAt = nil
+		job.StartedAt = nil
+		job.ScheduledAt = now
+		// ScheduledAt is the heap's sort key; restore the invariant instead
+		// of leaving this job's position stale. Re-locate the index for
+		// each job right before fixing it, since fixing an earlier one in
+		// this loop can move later ones around.
+		if idx := qm.heapIndexOfLocked(job); idx >= 0 {
+			heap.Fix(&qm.jobs, idx)
+		}
+
+		qm.stats.mutex.Lock()
+		qm.stats.ProcessingJobs--
+		qm.stats.PendingJobs[job.Priority]++
+		qm.stats.mutex.Unlock()
+
+		logrus.Warnf("[QUEUE] Lease expired for job %s (type: %s), requeued", job.ID, job.Type)
+		qm.emitJobEvent(job, "lease_expired")
+	}
+}
+
+// trackProcessing registers a job as being worked by an in-process worker
+// goroutine so the stuck-job reaper can find it if that goroutine never
+// reports back. untrackProcessing removes it once the worker finishes,
+// however it finishes.
+func (qm *QueueManager) trackProcessing(job *Job) {
+	qm.processingMutex.Lock()
+	qm.processing[job.ID] = job
+	qm.processingMutex.Unlock()
+}
+
+func (qm *QueueManager) untrackProcessing(jobID string) {
+	qm.processingMutex.Lock()
+	delete(qm.processing, jobID)
+	qm.processingMutex.Unlock()
+}
+
+// reapProcessingMargin is added on top of a job's own Timeout before the
+// stuck-job reaper considers it abandoned, so a handler that's still
+// legitimately running right up to its deadline isn't reaped out from under
+// it - the margin only needs to cover the gap between the handler's context
+// timing out and processJob actually observing that and untracking the job.
+const reapProcessingMargin = 30 * time.Second
+
+// startStuckJobReaper periodically recovers jobs left in StatusProcessing
+// well past their Timeout - e.g. because a worker goroutine panicked in a
+// way that bypassed processJob's recover, or the process was killed
+// mid-job - so they don't hang forever instead of completing or retrying.
+// Leased jobs are handled separately by requeueExpiredLeases.
+func (qm *QueueManager) startStuckJobReaper() {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-qm.ctx.Done():
+			return
+		case <-ticker.C:
+			qm.reapStuckJobs()
+		}
+	}
+}
+
+func (qm *QueueManager) reapStuckJobs() {
+	now := time.Now()
+
+	qm.processingMutex.Lock()
+	var stuck []*Job
+	for id, job := range qm.processing {
+		if job.LeaseExpiresAt != nil || job.StartedAt == nil {
+			continue
+		}
+		if now.Sub(*job.StartedAt) <= job.Timeout+reapProcessingMargin {
+			continue
+		}
+		stuck = append(stuck, job)
+		delete(qm.processing, id)
+	}
+	qm.processingMutex.Unlock()
+
+	for _, job := range stuck {
+		job.Error = fmt.Sprintf("reaped: stuck in processing past timeout (%s)", job.Timeout)
+		job.Attempts++
+		completedAt := time.Now()
+		job.CompletedAt = &completedAt
+
+		if job.Attempts < job.MaxAttempts {
+			job.Status = StatusRetrying
+			job.ScheduledAt = time.Now().Add(qm.calculateRetryDelay(job.Attempts))
+			job.StartedAt = nil
+			job.CompletedAt = nil
+
+			qm.jobMutex.Lock()
+			heap.Push(&qm.jobs, job)
+			qm.jobMutex.Unlock()
+
+			qm.updateJobStats(job)
+			logrus.Warnf("[QUEUE] Recovered stuck job %s (type: %s), retrying (attempt %d/%d)", job.ID, job.Type, job.Attempts, job.MaxAttempts)
+			qm.emitJobEvent(job, "retrying")
+		} else {
+			job.Status = StatusFailed
+
+			qm.updateJobStats(job)
+			logrus.Errorf("[QUEUE] Recovered stuck job %s (type: %s), marked failed permanently after %d attempts", job.ID, job.Type, job.Attempts)
+			qm.emitJobEvent(job, "failed")
+		}
+	}
+}
+
+// JobDiagnosis explains why a job isn't currently running. Only one of
+// UnmetDependencies, RateLimited or QueuePaused is ever populated - Reason
+// always describes the blocker in plain text regardless of which field it is.
+type JobDiagnosis struct {
+	JobID             string    `json:"job_id"`
+	Status            JobStatus `json:"status"`
+	Ready             bool      `json:"ready"`
+	Reason            string    `json:"reason"`
+	HandlerRegistered bool      `json:"handler_registered"`
+	ScheduledAt       time.Time `json:"scheduled_at"`
+	UnmetDependencies []string  `json:"unmet_dependencies,omitempty"`
+	RateLimited       bool      `json:"rate_limited,omitempty"`
+	QueuePaused       bool      `json:"queue_paused,omitempty"`
+}
+
+// DiagnoseJob explains the current blocker for a job that hasn't run yet:
+// scheduled-in-future, rate-limited, waiting on unmet dependencies, a paused
+// queue, or a missing handler for its job type. Jobs that are already
+// processing, completed, or otherwise resolved report that status directly.
+func (qm *QueueManager) DiagnoseJob(jobID string) (*JobDiagnosis, error) {
+	job, err := qm.GetJob(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	qm.mutex.RLock()
+	_, handlerRegistered := qm.handlers[job.Type]
+	qm.mutex.RUnlock()
+
+	diagnosis := &JobDiagnosis{
+		JobID:             job.ID,
+		Status:            job.Status,
+		ScheduledAt:       job.ScheduledAt,
+		HandlerRegistered: handlerRegistered,
+	}
+
+	if job.Status != StatusPending {
+		diagnosis.Reason = fmt.Sprintf("job is %s, not waiting to run", job.Status)
+		return diagnosis, nil
+	}
+
+	if !handlerRegistered {
+		diagnosis.Reason = fmt.Sprintf("no handler registered for job type: %s", job.Type)
+		return diagnosis, nil
+	}
+
+	if qm.IsPaused() {
+		diagnosis.QueuePaused = true
+		diagnosis.Reason = "queue is paused"
+		return diagnosis, nil
+	}
+
+	qm.jobMutex.RLock()
+	unmet := qm.unmetDependenciesLocked(job)
+	qm.jobMutex.RUnlock()
+	if len(unmet) > 0 {
+		diagnosis.UnmetDependencies = unmet
+		diagnosis.Reason = fmt.Sprintf("waiting on %d unmet dependencies", len(unmet))
+		return diagnosis, nil
+	}
+
+	if time.Now().Before(job.ScheduledAt) {
+		diagnosis.Reason = fmt.Sprintf("scheduled to run at %s", job.ScheduledAt.Format(time.RFC3339))
+		return diagnosis, nil
+	}
+
+	if !qm.rateLimitAvailable(job.Type) {
+		diagnosis.RateLimited = true
+		diagnosis.Reason = fmt.Sprintf("rate limit exceeded for job type: %s", job.Type)
+		return diagnosis, nil
+	}
+
+	diagnosis.Ready = true
+	diagnosis.Reason = "job is ready to run and waiting for a free worker"
+	return diagnosis, nil
+}
+
 func (qm *QueueManager) startStatsUpdater() {
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
@@ -566,11 +1936,9 @@ func (qm *QueueManager) updateCurrentStats() {
 	}
 
 	// Count current pending jobs
-	for priority, queue := range qm.queues {
-		for _, job := range queue {
-			if job.Status == StatusPending {
-				qm.stats.PendingJobs[priority]++
-			}
+	for _, job := range qm.jobs {
+		if job.Status == StatusPending {
+			qm.stats.PendingJobs[job.Priority]++
 		}
 	}
 
@@ -581,11 +1949,51 @@ func (qm *QueueManager) generateJobID() string {
 	return fmt.Sprintf("job_%d_%d", time.Now().UnixNano(), time.Now().UnixNano()%1000)
 }
 
+// calculateRetryDelay computes how long to wait before a job's next attempt,
+// linearly scaling with attempts, then - if the retry policy has Jitter
+// enabled - randomizing uniformly within [0, computed] (full jitter) so a
+// batch of jobs that failed together don't all retry at the exact same
+// instant.
+func (qm *QueueManager) calculateRetryDelay(attempts int) time.Duration {
+	qm.retryMutex.RLock()
+	policy := qm.retryPolicy
+	qm.retryMutex.RUnlock()
+
+	delay := time.Duration(attempts) * policy.BaseDelay
+	if policy.Jitter && delay > 0 {
+		delay = time.Duration(rand.Int63n(int64(delay) + 1))
+	}
+	return delay
+}
+
+// GetRetryPolicy returns the currently configured job retry policy.
+func (qm *QueueManager) GetRetryPolicy() RetryPolicy {
+	qm.retryMutex.RLock()
+	defer qm.retryMutex.RUnlock()
+	return *qm.retryPolicy
+}
+
+// SetRetryPolicy reconfigures the delay used for automatic job retries.
+func (qm *QueueManager) SetRetryPolicy(policy RetryPolicy) error {
+	if policy.BaseDelay <= 0 {
+		return apperr.Validation("QUEUE_INVALID_RETRY_POLICY", "base_delay must be positive")
+	}
+
+	qm.retryMutex.Lock()
+	qm.retryPolicy = &policy
+	qm.retryMutex.Unlock()
+	return nil
+}
+
 // Stop gracefully stops the queue manager
 func (qm *QueueManager) Stop() {
 	logrus.Info("[QUEUE] Stopping queue manager...")
 	qm.cancel()
-	
+
+	if qm.resultStore != nil {
+		defer qm.resultStore.Close()
+	}
+
 	// Wait for jobs to complete (with timeout)
 	timeout := time.After(30 * time.Second)
 	ticker := time.NewTicker(1 * time.Second)
@@ -605,4 +2013,4 @@ func (qm *QueueManager) Stop() {
 			logrus.Infof("[QUEUE] Waiting for %d jobs to complete...", stats.ProcessingJobs)
 		}
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,85 @@
+package queue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculateRetryDelayJitterSpread(t *testing.T) {
+	qm := NewQueueManager()
+	defer qm.Stop()
+
+	qm.retryPolicy = &RetryPolicy{
+		BaseDelay: 1 * time.Minute,
+		Jitter:    true,
+	}
+
+	computed := 3 * qm.retryPolicy.BaseDelay
+
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 50; i++ {
+		delay := qm.calculateRetryDelay(3)
+		if delay < 0 || delay > computed {
+			t.Fatalf("delay %v out of expected range [0, %v]", delay, computed)
+		}
+		seen[delay] = true
+	}
+
+	if len(seen) < 2 {
+		t.Fatalf("expected jittered delays to spread across multiple values, got %d distinct value(s)", len(seen))
+	}
+}
+
+func TestP2EstimatorTracksMedian(t *testing.T) {
+	p := newP2Estimator(0.50)
+	for i := 1; i <= 99; i++ {
+		p.Add(float64(i))
+	}
+
+	if got := p.Value(); got < 45 || got > 55 {
+		t.Fatalf("expected median estimate near 50 for values 1..99, got %v", got)
+	}
+}
+
+func TestP2EstimatorHighQuantileExceedsMedian(t *testing.T) {
+	median := newP2Estimator(0.50)
+	p95 := newP2Estimator(0.95)
+	for i := 1; i <= 200; i++ {
+		median.Add(float64(i))
+		p95.Add(float64(i))
+	}
+
+	if p95.Value() <= median.Value() {
+		t.Fatalf("expected p95 (%v) to exceed median (%v)", p95.Value(), median.Value())
+	}
+}
+
+func TestPriorityTierBucketsKnownConstants(t *testing.T) {
+	cases := map[Priority]string{
+		PriorityLow:    "low",
+		PriorityNormal: "normal",
+		PriorityHigh:   "high",
+		PriorityUrgent: "urgent",
+	}
+
+	for priority, want := range cases {
+		if got := priorityTier(priority); got != want {
+			t.Fatalf("priorityTier(%d) = %q, want %q", priority, got, want)
+		}
+	}
+}
+
+func TestCalculateRetryDelayNoJitterIsDeterministic(t *testing.T) {
+	qm := NewQueueManager()
+	defer qm.Stop()
+
+	qm.retryPolicy = &RetryPolicy{
+		BaseDelay: 1 * time.Minute,
+		Jitter:    false,
+	}
+
+	want := 3 * qm.retryPolicy.BaseDelay
+	if delay := qm.calculateRetryDelay(3); delay != want {
+		t.Fatalf("expected deterministic delay %v without jitter, got %v", want, delay)
+	}
+}
@@ -0,0 +1,405 @@
+package reportschedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/analytics"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/apperr"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/queue"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/templates"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/webhook"
+	"github.com/sirupsen/logrus"
+)
+
+// ReportSchedule configures one recurring analytics report delivery: what
+// report to generate (ReportType), when to generate it, and where to send
+// it (Destination).
+type ReportSchedule struct {
+	ID         string `json:"id"`
+	ReportType string `json:"report_type"` // "weekly" or "monthly"
+
+	// Weekday and Hour are used when ReportType is "weekly": the report runs
+	// once on that day of the week (0 = Sunday) at that hour, server-local
+	// time. DayOfMonth and Hour are used instead when ReportType is
+	// "monthly"; a DayOfMonth beyond the current month's length runs on the
+	// month's last day.
+	Weekday    time.Weekday `json:"weekday,omitempty"`
+	DayOfMonth int          `json:"day_of_month,omitempty"`
+	Hour       int          `json:"hour"`
+
+	// Destination is "webhook" or "whatsapp".
+	Destination string `json:"destination"`
+	// WebhookEventType is the event type SendEvent dispatches the report
+	// under when Destination is "webhook" - the operator subscribes a
+	// webhook endpoint to it the same way as any other event type.
+	WebhookEventType string `json:"webhook_event_type,omitempty"`
+	// TemplateID and Phone are used when Destination is "whatsapp": the
+	// report is rendered through TemplateID and the result queued as a
+	// send_message job to Phone.
+	TemplateID string `json:"template_id,omitempty"`
+	Phone      string `json:"phone,omitempty"`
+
+	Enabled   bool       `json:"enabled"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+}
+
+// Manager persists ReportSchedules and, on a background tick, generates and
+// delivers any that are due. It depends on analytics for report generation
+// and either webhook or templates+queue for delivery, mirroring how
+// autoreply.Manager depends on templates+queue to turn a match into a
+// delivered message.
+type Manager struct {
+	mutex        sync.RWMutex
+	schedules    map[string]*ReportSchedule
+	dataPath     string
+	analyticsMgr *analytics.Analytics
+	webhookMgr   *webhook.WebhookManager
+	templateMgr  *templates.TemplateManager
+	queueMgr     *queue.QueueManager
+
+	stopChan chan struct{}
+}
+
+// NewManager constructs a report schedule manager backed by analyticsMgr for
+// report generation and webhookMgr/templateMgr/queueMgr for delivery.
+func NewManager(analyticsMgr *analytics.Analytics, webhookMgr *webhook.WebhookManager, templateMgr *templates.TemplateManager, queueMgr *queue.QueueManager) *Manager {
+	dataPath := filepath.Join(config.PathStorages, "report_schedules")
+	os.MkdirAll(dataPath, 0755)
+
+	m := &Manager{
+		schedules:    make(map[string]*ReportSchedule),
+		dataPath:     dataPath,
+		analyticsMgr: analyticsMgr,
+		webhookMgr:   webhookMgr,
+		templateMgr:  templateMgr,
+		queueMgr:     queueMgr,
+		stopChan:     make(chan struct{}),
+	}
+
+	m.loadSchedules()
+	go m.run()
+
+	return m
+}
+
+// AddSchedule validates and stores a new report schedule, assigning it an ID
+// if the caller didn't supply one.
+func (m *Manager) AddSchedule(s *ReportSchedule) error {
+	if s.ReportType != "weekly" && s.ReportType != "monthly" {
+		return apperr.Validation("REPORT_SCHEDULE_INVALID_TYPE", `report_type must be "weekly" or "monthly"`)
+	}
+	if s.Destination != "webhook" && s.Destination != "whatsapp" {
+		return apperr.Validation("REPORT_SCHEDULE_INVALID_DESTINATION", `destination must be "webhook" or "whatsapp"`)
+	}
+	if s.Destination == "whatsapp" && (s.TemplateID == "" || s.Phone == "") {
+		return apperr.Validation("REPORT_SCHEDULE_MISSING_DESTINATION", "template_id and phone are required for whatsapp destination")
+	}
+	if s.Hour < 0 || s.Hour > 23 {
+		return apperr.Validation("REPORT_SCHEDULE_INVALID_HOUR", "hour must be between 0 and 23")
+	}
+
+	if s.ID == "" {
+		s.ID = m.generateScheduleID()
+	}
+	s.Enabled = true
+	s.CreatedAt = time.Now()
+	s.UpdatedAt = time.Now()
+
+	m.mutex.Lock()
+	m.schedules[s.ID] = s
+	m.mutex.Unlock()
+
+	if err := m.saveSchedule(s); err != nil {
+		return fmt.Errorf("failed to persist report schedule: %w", err)
+	}
+
+	logrus.Infof("[REPORT_SCHEDULE] Added %s schedule %s (destination: %s)", s.ReportType, s.ID, s.Destination)
+	return nil
+}
+
+// UpdateSchedule applies a partial update: only keys present in updates are
+// changed, mirroring webhook.WebhookManager.UpdateEndpoint's explicit
+// allowlist of settable fields.
+func (m *Manager) UpdateSchedule(id string, updates map[string]interface{}) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	s, exists := m.schedules[id]
+	if !exists {
+		return apperr.NotFound("REPORT_SCHEDULE_NOT_FOUND", fmt.Sprintf("report schedule not found: %s", id))
+	}
+
+	if reportType, ok := updates["report_type"].(string); ok {
+		s.ReportType = reportType
+	}
+	if weekday, ok := updates["weekday"].(float64); ok {
+		s.Weekday = time.Weekday(int(weekday))
+	}
+	if dayOfMonth, ok := updates["day_of_month"].(float64); ok {
+		s.DayOfMonth = int(dayOfMonth)
+	}
+	if hour, ok := updates["hour"].(float64); ok {
+		s.Hour = int(hour)
+	}
+	if destination, ok := updates["destination"].(string); ok {
+		s.Destination = destination
+	}
+	if eventType, ok := updates["webhook_event_type"].(string); ok {
+		s.WebhookEventType = eventType
+	}
+	if templateID, ok := updates["template_id"].(string); ok {
+		s.TemplateID = templateID
+	}
+	if phone, ok := updates["phone"].(string); ok {
+		s.Phone = phone
+	}
+	if enabled, ok := updates["enabled"].(bool); ok {
+		s.Enabled = enabled
+	}
+
+	s.UpdatedAt = time.Now()
+	if err := m.saveSchedule(s); err != nil {
+		return fmt.Errorf("failed to persist report schedule: %w", err)
+	}
+	return nil
+}
+
+// RemoveSchedule deletes a schedule and its persisted file.
+func (m *Manager) RemoveSchedule(id string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, exists := m.schedules[id]; !exists {
+		return apperr.NotFound("REPORT_SCHEDULE_NOT_FOUND", fmt.Sprintf("report schedule not found: %s", id))
+	}
+
+	delete(m.schedules, id)
+	if err := os.Remove(filepath.Join(m.dataPath, id+".json")); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove report schedule file: %w", err)
+	}
+
+	logrus.Infof("[REPORT_SCHEDULE] Removed schedule %s", id)
+	return nil
+}
+
+// GetSchedule retrieves a schedule by ID.
+func (m *Manager) GetSchedule(id string) (*ReportSchedule, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	s, exists := m.schedules[id]
+	if !exists {
+		return nil, apperr.NotFound("REPORT_SCHEDULE_NOT_FOUND", fmt.Sprintf("report schedule not found: %s", id))
+	}
+	return s, nil
+}
+
+// ListSchedules returns every configured report schedule.
+func (m *Manager) ListSchedules() []*ReportSchedule {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	list := make([]*ReportSchedule, 0, len(m.schedules))
+	for _, s := range m.schedules {
+		list = append(list, s)
+	}
+	return list
+}
+
+// Stop ends the background scheduling loop.
+func (m *Manager) Stop() {
+	close(m.stopChan)
+}
+
+// run periodically checks configured schedules and delivers any that are due.
+func (m *Manager) run() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopChan:
+			return
+		case <-ticker.C:
+			m.runDue()
+		}
+	}
+}
+
+func (m *Manager) runDue() {
+	now := time.Now()
+
+	m.mutex.RLock()
+	var due []*ReportSchedule
+	for _, s := range m.schedules {
+		if s.Enabled && isDue(s, now) {
+			due = append(due, s)
+		}
+	}
+	m.mutex.RUnlock()
+
+	for _, s := range due {
+		m.deliver(s, now)
+	}
+}
+
+// isDue reports whether s should fire at now: its hour (and weekday or
+// day-of-month) matches, and it hasn't already run today.
+func isDue(s *ReportSchedule, now time.Time) bool {
+	if s.LastRunAt != nil && sameDay(*s.LastRunAt, now) {
+		return false
+	}
+	if now.Hour() != s.Hour {
+		return false
+	}
+
+	if s.ReportType == "monthly" {
+		return now.Day() == effectiveDayOfMonth(s.DayOfMonth, now)
+	}
+	return now.Weekday() == s.Weekday
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// effectiveDayOfMonth clamps dayOfMonth to the last day of now's month, so a
+// schedule set for the 31st still fires in shorter months.
+func effectiveDayOfMonth(dayOfMonth int, now time.Time) int {
+	lastDay := time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, now.Location()).Day()
+	if dayOfMonth <= 0 {
+		return 1
+	}
+	if dayOfMonth > lastDay {
+		return lastDay
+	}
+	return dayOfMonth
+}
+
+// deliver generates s's report and sends it to its configured destination,
+// recording the outcome on s regardless of success or failure.
+func (m *Manager) deliver(s *ReportSchedule, now time.Time) {
+	var report *analytics.Report
+	if s.ReportType == "monthly" {
+		report = m.analyticsMgr.GetMonthlyReport()
+	} else {
+		report = m.analyticsMgr.GetWeeklyReport()
+	}
+
+	var err error
+	if s.Destination == "whatsapp" {
+		err = m.deliverWhatsApp(s, report)
+	} else {
+		err = m.deliverWebhook(s, report)
+	}
+
+	m.mutex.Lock()
+	s.LastRunAt = &now
+	if err != nil {
+		s.LastError = err.Error()
+	} else {
+		s.LastError = ""
+	}
+	m.mutex.Unlock()
+
+	if err != nil {
+		logrus.Errorf("[REPORT_SCHEDULE] Delivery failed for schedule %s: %v", s.ID, err)
+	} else {
+		logrus.Infof("[REPORT_SCHEDULE] Delivered %s report for schedule %s via %s", s.ReportType, s.ID, s.Destination)
+	}
+
+	if saveErr := m.saveSchedule(s); saveErr != nil {
+		logrus.Errorf("[REPORT_SCHEDULE] Failed to persist schedule %s after delivery: %v", s.ID, saveErr)
+	}
+}
+
+func (m *Manager) deliverWebhook(s *ReportSchedule, report *analytics.Report) error {
+	if m.webhookMgr == nil {
+		return fmt.Errorf("webhook delivery is not available")
+	}
+
+	eventType := s.WebhookEventType
+	if eventType == "" {
+		eventType = "analytics.report"
+	}
+
+	return m.webhookMgr.SendEvent(&webhook.WebhookEvent{
+		Type:   eventType,
+		Source: "report_schedule",
+		Data: map[string]interface{}{
+			"schedule_id": s.ID,
+			"report_type": s.ReportType,
+			"report":      report,
+		},
+	})
+}
+
+func (m *Manager) deliverWhatsApp(s *ReportSchedule, report *analytics.Report) error {
+	summary, err := json.Marshal(report.Summary)
+	if err != nil {
+		return fmt.Errorf("failed to marshal report summary: %w", err)
+	}
+
+	rendered, err := m.templateMgr.RenderTemplate(s.TemplateID, map[string]string{
+		"report_type": s.ReportType,
+		"report":      string(summary),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render report template: %w", err)
+	}
+
+	_, err = m.queueMgr.AddJob("send_message", map[string]interface{}{
+		"phone":   s.Phone,
+		"message": rendered,
+	}, queue.PriorityNormal)
+	return err
+}
+
+func (m *Manager) saveSchedule(s *ReportSchedule) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(m.dataPath, s.ID+".json"), data, 0644)
+}
+
+func (m *Manager) loadSchedules() {
+	matches, err := filepath.Glob(filepath.Join(m.dataPath, "*.json"))
+	if err != nil {
+		logrus.Errorf("[REPORT_SCHEDULE] Failed to list schedule files: %v", err)
+		return
+	}
+
+	for _, path := range matches {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logrus.Errorf("[REPORT_SCHEDULE] Failed to read schedule file %s: %v", path, err)
+			continue
+		}
+
+		var s ReportSchedule
+		if err := json.Unmarshal(data, &s); err != nil {
+			logrus.Errorf("[REPORT_SCHEDULE] Failed to parse schedule file %s: %v", path, err)
+			continue
+		}
+
+		m.schedules[s.ID] = &s
+	}
+
+	logrus.Infof("[REPORT_SCHEDULE] Loaded %d report schedules", len(m.schedules))
+}
+
+func (m *Manager) generateScheduleID() string {
+	return fmt.Sprintf("report_schedule_%d", time.Now().UnixNano())
+}
@@ -3,38 +3,67 @@ package templates
 import (
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"html"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	textTemplate "text/template"
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/analytics"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/apperr"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/pmezard/go-difflib/difflib"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
 )
 
 type Template struct {
-	ID            string                 `json:"id"`
-	Name          string                 `json:"name"`
-	Description   string                 `json:"description"`
-	Content       string                 `json:"content"`
-	Variables     []Variable             `json:"variables"`
-	Category      string                 `json:"category"`
-	Tags          []string               `json:"tags"`
-	Language      string                 `json:"language"`
-	Version       string                 `json:"version"`
-	IsActive      bool                   `json:"is_active"`
-	CreatedAt     time.Time              `json:"created_at"`
-	UpdatedAt     time.Time              `json:"updated_at"`
-	UsageCount    int                    `json:"usage_count"`
-	LastUsedAt    *time.Time             `json:"last_used_at,omitempty"`
-	Metadata      map[string]interface{} `json:"metadata"`
-	Conditions    []Condition            `json:"conditions,omitempty"`
-	Transformers  []Transformer          `json:"transformers,omitempty"`
-	Validations   []Validation           `json:"validations,omitempty"`
-	Scheduling    *ScheduleConfig        `json:"scheduling,omitempty"`
+	ID           string                 `json:"id"`
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description"`
+	Content      string                 `json:"content"`
+	Variables    []Variable             `json:"variables"`
+	Category     string                 `json:"category"`
+	Tags         []string               `json:"tags"`
+	Language     string                 `json:"language"`
+	Version      string                 `json:"version"`
+	IsActive     bool                   `json:"is_active"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+	UsageCount   int                    `json:"usage_count"`
+	LastUsedAt   *time.Time             `json:"last_used_at,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata"`
+	Conditions   []Condition            `json:"conditions,omitempty"`
+	Transformers []Transformer          `json:"transformers,omitempty"`
+	Validations  []Validation           `json:"validations,omitempty"`
+	Scheduling   *ScheduleConfig        `json:"scheduling,omitempty"`
+	// Workspace namespaces this template so separate teams sharing one
+	// deployment don't see each other's templates in listings/search.
+	// Empty is normalized to defaultWorkspace on create and on load.
+	Workspace string `json:"workspace,omitempty"`
+}
+
+// defaultWorkspace is the shared namespace templates fall into when no
+// workspace is specified, preserving pre-workspace behavior.
+const defaultWorkspace = "default"
+
+// normalizeWorkspace maps an empty workspace to defaultWorkspace so callers
+// never have to special-case "no workspace given".
+func normalizeWorkspace(workspace string) string {
+	if workspace == "" {
+		return defaultWorkspace
+	}
+	return workspace
 }
 
 type Variable struct {
@@ -43,9 +72,26 @@ type Variable struct {
 	Required     bool        `json:"required"`
 	DefaultValue interface{} `json:"default_value,omitempty"`
 	Description  string      `json:"description,omitempty"`
-	Options      []string    `json:"options,omitempty"` // for select type
+	Options      []string    `json:"options,omitempty"`    // for select type
 	Validation   string      `json:"validation,omitempty"` // regex pattern
-	Format       string      `json:"format,omitempty"` // date format, number format, etc.
+	Format       string      `json:"format,omitempty"`     // date format, number format, etc.
+
+	// ConditionalDefaults lets this variable's default depend on another
+	// variable already present in the render context, e.g. "greeting"
+	// defaulting to "Good morning" or "Good evening" based on "time_of_day".
+	// setDefaultValues evaluates them in order and uses the Value of the
+	// first entry whose When conditions all match; if none match, or this is
+	// empty, DefaultValue is used instead.
+	ConditionalDefaults []ConditionalDefault `json:"conditional_defaults,omitempty"`
+}
+
+// ConditionalDefault is one candidate default for a Variable, used when its
+// When conditions all match the render context. It reuses Condition's
+// field/operator/value shape (see isValidOperator for supported operators)
+// rather than introducing a separate expression syntax.
+type ConditionalDefault struct {
+	When  []Condition `json:"when"`
+	Value interface{} `json:"value"`
 }
 
 type Condition struct {
@@ -56,16 +102,16 @@ type Condition struct {
 }
 
 type Transformer struct {
-	Variable string `json:"variable"`
-	Type     string `json:"type"` // uppercase, lowercase, capitalize, format_date, format_number
+	Variable string                 `json:"variable"`
+	Type     string                 `json:"type"` // uppercase, lowercase, capitalize, format_date, format_number
 	Options  map[string]interface{} `json:"options,omitempty"`
 }
 
 type Validation struct {
-	Variable string `json:"variable"`
-	Rule     string `json:"rule"` // required, min_length, max_length, regex, email, phone
+	Variable string      `json:"variable"`
+	Rule     string      `json:"rule"` // required, min_length, max_length, regex, email, phone
 	Value    interface{} `json:"value,omitempty"`
-	Message  string `json:"message"`
+	Message  string      `json:"message"`
 }
 
 type ScheduleConfig struct {
@@ -85,14 +131,34 @@ type TemplateVersion struct {
 	Changes   string    `json:"changes"`
 }
 
+// OutputFormat values for RenderContext.OutputFormat, controlling how the
+// canonical WhatsApp markdown in a rendered template (*bold*, _italic_,
+// ~strike~, ```monospace```) is translated for the destination channel.
+const (
+	FormatWhatsApp = "whatsapp" // canonical syntax, passed through unchanged (default)
+	FormatPlain    = "plain"    // markers stripped, inner text kept
+	FormatHTML     = "html"     // markers converted to the equivalent HTML tag
+)
+
 type RenderContext struct {
-	Variables   map[string]interface{} `json:"variables"`
-	UserAgent   string                 `json:"user_agent,omitempty"`
-	IP          string                 `json:"ip,omitempty"`
-	Timestamp   time.Time              `json:"timestamp"`
-	Language    string                 `json:"language,omitempty"`
-	Timezone    string                 `json:"timezone,omitempty"`
-	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Variables    map[string]interface{} `json:"variables"`
+	UserAgent    string                 `json:"user_agent,omitempty"`
+	IP           string                 `json:"ip,omitempty"`
+	Timestamp    time.Time              `json:"timestamp"`
+	Language     string                 `json:"language,omitempty"`
+	Timezone     string                 `json:"timezone,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	OutputFormat string                 `json:"output_format,omitempty"` // FormatWhatsApp (default), FormatPlain, or FormatHTML
+
+	// Sandboxed restricts the template to sandboxAllowedFuncs, the vetted
+	// subset of createFuncMap with no filesystem, network, or process
+	// access, so a template author can't use a func with side effects to
+	// exfiltrate data. It's deliberately not JSON-tagged: it must never be
+	// settable from a render-advanced request body, only by trusted Go code
+	// constructing a RenderContext directly. The render-advanced REST
+	// handler forces it on for every external caller; privileged internal
+	// callers may construct a context with Sandboxed left false to opt out.
+	Sandboxed bool `json:"-"`
 }
 
 type TemplateManager struct {
@@ -100,7 +166,49 @@ type TemplateManager struct {
 	versionsPath  string
 	templates     map[string]*Template
 	versions      map[string][]TemplateVersion
+	reloadMutex   sync.RWMutex
 	funcMap       textTemplate.FuncMap
+	renderMetrics map[string]*RenderMetrics
+	globalMetrics *RenderMetrics
+	metricsMutex  sync.Mutex
+	analytics     *analytics.Analytics
+
+	// parsedTemplates caches compiled *template.Template values so a hot
+	// template isn't re-lexed and re-parsed on every render. Entries are
+	// keyed by template ID and sandbox mode (the func map differs between
+	// the two) and self-invalidate: each entry also carries a hash of the
+	// Content it was parsed from, so a stale entry left behind by
+	// UpdateTemplate or RestoreTemplateVersion is simply reparsed and
+	// replaced the next time it's read, instead of requiring every content
+	// mutation site to remember to purge the cache.
+	parsedTemplates map[parsedTemplateCacheKey]*cachedTemplate
+	parsedMutex     sync.RWMutex
+}
+
+// parsedTemplateCacheKey identifies one compiled variant of a template.
+// Sandboxed and unsandboxed renders of the same template use different func
+// maps, so they're cached separately.
+type parsedTemplateCacheKey struct {
+	id        string
+	sandboxed bool
+}
+
+// cachedTemplate is a compiled template plus the content hash it was
+// compiled from, used to detect that the source Template has since changed.
+type cachedTemplate struct {
+	contentHash uint64
+	tmpl        *textTemplate.Template
+}
+
+// RenderMetrics tracks rendering latency and error rate, either for a
+// single template or aggregated across all of them.
+type RenderMetrics struct {
+	TotalRenders   int64         `json:"total_renders"`
+	TotalErrors    int64         `json:"total_errors"`
+	TotalDuration  time.Duration `json:"total_duration"`
+	AverageLatency time.Duration `json:"average_latency"`
+	ErrorRate      float64       `json:"error_rate"`
+	LastRenderedAt *time.Time    `json:"last_rendered_at,omitempty"`
 }
 
 func NewTemplateManager() *TemplateManager {
@@ -110,27 +218,40 @@ func NewTemplateManager() *TemplateManager {
 	os.MkdirAll(versionsPath, 0755)
 
 	tm := &TemplateManager{
-	templatesPath: templatesPath,
-	versionsPath:  versionsPath,
-	templates:     make(map[string]*Template),
-	versions:      make(map[string][]TemplateVersion),
-}
-tm.funcMap = tm.createFuncMap()
+		templatesPath:   templatesPath,
+		versionsPath:    versionsPath,
+		templates:       make(map[string]*Template),
+		versions:        make(map[string][]TemplateVersion),
+		renderMetrics:   make(map[string]*RenderMetrics),
+		globalMetrics:   &RenderMetrics{},
+		analytics:       analytics.NewAnalytics(),
+		parsedTemplates: make(map[parsedTemplateCacheKey]*cachedTemplate),
+	}
+	tm.funcMap = tm.createFuncMap()
 
 	// Load existing templates and versions
 	tm.loadTemplates()
 	tm.loadVersions()
-	
+
 	// Create default templates if none exist
 	if len(tm.templates) == 0 {
 		tm.createDefaultTemplates()
 	}
 
+	// Auto-activate/deactivate templates based on their schedule
+	go tm.startScheduleSync()
+
 	return tm
 }
 
-// CreateTemplate creates a new message template
+// CreateTemplate creates a new message template in the default workspace.
 func (tm *TemplateManager) CreateTemplate(name, description, content, category string) (*Template, error) {
+	return tm.CreateTemplateInWorkspace(defaultWorkspace, name, description, content, category)
+}
+
+// CreateTemplateInWorkspace is CreateTemplate scoped to a specific
+// workspace; an empty workspace falls back to defaultWorkspace.
+func (tm *TemplateManager) CreateTemplateInWorkspace(workspace, name, description, content, category string) (*Template, error) {
 	return tm.CreateAdvancedTemplate(&Template{
 		Name:        name,
 		Description: description,
@@ -141,13 +262,14 @@ func (tm *TemplateManager) CreateTemplate(name, description, content, category s
 		IsActive:    true,
 		Tags:        []string{},
 		Metadata:    make(map[string]interface{}),
+		Workspace:   workspace,
 	})
 }
 
 // CreateAdvancedTemplate creates a new advanced template with full configuration
 func (tm *TemplateManager) CreateAdvancedTemplate(template *Template) (*Template, error) {
 	if template.Name == "" || template.Content == "" {
-		return nil, fmt.Errorf("name and content are required")
+		return nil, apperr.Validation("TEMPLATE_NAME_CONTENT_REQUIRED", "name and content are required")
 	}
 
 	// Generate unique ID
@@ -176,18 +298,20 @@ func (tm *TemplateManager) CreateAdvancedTemplate(template *Template) (*Template
 
 	// Validate template
 	if err := tm.validateTemplate(template); err != nil {
-		return nil, fmt.Errorf("template validation failed: %w", err)
+		return nil, apperr.Wrap("TEMPLATE_VALIDATION_FAILED", apperr.CategoryValidation, 400, "template validation failed", err)
 	}
 
 	// Save template
 	if err := tm.saveTemplate(template); err != nil {
-		return nil, fmt.Errorf("failed to save template: %w", err)
+		return nil, apperr.Internal("TEMPLATE_SAVE_FAILED", "failed to save template", err)
 	}
 
 	// Create initial version
 	tm.createVersion(template.ID, template.Content, "system", "Initial version")
 
+	tm.reloadMutex.Lock()
 	tm.templates[template.ID] = template
+	tm.reloadMutex.Unlock()
 	logrus.Infof("[TEMPLATES] Created advanced template: %s (%s)", template.Name, template.ID)
 
 	return template, nil
@@ -195,31 +319,265 @@ func (tm *TemplateManager) CreateAdvancedTemplate(template *Template) (*Template
 
 // GetTemplate retrieves a template by ID
 func (tm *TemplateManager) GetTemplate(id string) (*Template, error) {
+	tm.reloadMutex.RLock()
 	template, exists := tm.templates[id]
+	tm.reloadMutex.RUnlock()
 	if !exists {
-		return nil, fmt.Errorf("template not found: %s", id)
+		return nil, apperr.NotFound("TEMPLATE_NOT_FOUND", fmt.Sprintf("template not found: %s", id))
 	}
 	return template, nil
 }
 
-// ListTemplates returns all templates, optionally filtered by category
-func (tm *TemplateManager) ListTemplates(category string) []*Template {
+// VariableCatalog is a clean, form-ready description of a template's
+// variables: the set content extraction found, overlaid with any
+// explicitly-declared metadata that extraction can't infer on its own (select
+// options, a validation regex, a default value). Variables are sorted by name
+// so the schema is stable across calls regardless of extraction order.
+type VariableCatalog struct {
+	TemplateID string     `json:"template_id"`
+	Variables  []Variable `json:"variables"`
+}
+
+// GetTemplateSchema returns template's variable catalog: the variables
+// extracted from its content, merged with any explicit declarations under
+// template.Metadata["variables"] (a map of variable name to an object with
+// the same shape as Variable, e.g. {"type": "select", "options": [...],
+// "validation": "..."}). A declared variable not referenced in the content is
+// still included, so a template can advertise an optional variable it only
+// consumes conditionally.
+func (tm *TemplateManager) GetTemplateSchema(id string) (*VariableCatalog, error) {
+	template, err := tm.GetTemplate(id)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]*Variable, len(template.Variables))
+	for _, v := range template.Variables {
+		variable := v
+		merged[variable.Name] = &variable
+	}
+
+	for name, declared := range declaredVariables(template) {
+		variable, exists := merged[name]
+		if !exists {
+			variable = &Variable{Name: name}
+			merged[name] = variable
+		}
+		applyDeclaredVariable(variable, declared)
+	}
+
+	names := make([]string, 0, len(merged))
+	for name := range merged {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	catalog := &VariableCatalog{
+		TemplateID: template.ID,
+		Variables:  make([]Variable, 0, len(names)),
+	}
+	for _, name := range names {
+		catalog.Variables = append(catalog.Variables, *merged[name])
+	}
+
+	return catalog, nil
+}
+
+// declaredVariables reads template.Metadata["variables"] and returns it as a
+// map of variable name to its declared fields, tolerating the shapes that
+// metadata can arrive in: a native map[string]interface{} (set
+// programmatically) or the same structure after a JSON round-trip (loaded
+// from disk), where each entry is itself a map[string]interface{}.
+func declaredVariables(template *Template) map[string]map[string]interface{} {
+	raw, ok := template.Metadata["variables"]
+	if !ok {
+		return nil
+	}
+
+	declarations, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	result := make(map[string]map[string]interface{}, len(declarations))
+	for name, fields := range declarations {
+		if fieldMap, ok := fields.(map[string]interface{}); ok {
+			result[name] = fieldMap
+		}
+	}
+	return result
+}
+
+// applyDeclaredVariable overlays the fields present in declared onto variable,
+// leaving anything not explicitly declared untouched.
+func applyDeclaredVariable(variable *Variable, declared map[string]interface{}) {
+	if t, ok := declared["type"].(string); ok {
+		variable.Type = t
+	}
+	if required, ok := declared["required"].(bool); ok {
+		variable.Required = required
+	}
+	if defaultValue, ok := declared["default_value"]; ok {
+		variable.DefaultValue = defaultValue
+	}
+	if description, ok := declared["description"].(string); ok {
+		variable.Description = description
+	}
+	if rawOptions, ok := declared["options"].([]interface{}); ok {
+		options := make([]string, 0, len(rawOptions))
+		for _, opt := range rawOptions {
+			if optStr, ok := opt.(string); ok {
+				options = append(options, optStr)
+			}
+		}
+		variable.Options = options
+	}
+	if validation, ok := declared["validation"].(string); ok {
+		variable.Validation = validation
+	}
+	if format, ok := declared["format"].(string); ok {
+		variable.Format = format
+	}
+}
+
+// ListTemplates returns all templates in the given workspace, optionally
+// filtered by category. An empty workspace scopes to defaultWorkspace.
+func (tm *TemplateManager) ListTemplates(workspace, category, sortBy, sortDir string) []*Template {
+	workspace = normalizeWorkspace(workspace)
 	var templates []*Template
-	
+
+	tm.reloadMutex.RLock()
 	for _, template := range tm.templates {
+		if normalizeWorkspace(template.Workspace) != workspace {
+			continue
+		}
 		if category == "" || template.Category == category {
 			templates = append(templates, template)
 		}
 	}
+	tm.reloadMutex.RUnlock()
+
+	sort.Slice(templates, func(i, j int) bool {
+		cmp := compareTemplates(templates[i], templates[j], sortBy)
+		if sortDir == "desc" {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
 
 	return templates
 }
 
-// UpdateTemplate updates an existing template
+// compareTemplates orders two templates by sortBy ("name", "updated_at", or
+// the default "created_at"), falling back to ID as a tie-breaker so the
+// order is stable across calls regardless of map iteration order.
+func compareTemplates(a, b *Template, sortBy string) int {
+	switch sortBy {
+	case "name":
+		if a.Name != b.Name {
+			return strings.Compare(a.Name, b.Name)
+		}
+	case "updated_at":
+		if !a.UpdatedAt.Equal(b.UpdatedAt) {
+			if a.UpdatedAt.Before(b.UpdatedAt) {
+				return -1
+			}
+			return 1
+		}
+	default:
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			if a.CreatedAt.Before(b.CreatedAt) {
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(a.ID, b.ID)
+}
+
+// PickByTag selects one active template tagged tag, weighted by an optional
+// "weight" entry in its Metadata (missing or non-positive defaults to 1, so
+// an unweighted set behaves as uniform random), and records the pick in
+// analytics. This lets an integration reference a tag instead of a single
+// template ID and get automatic variation - e.g. rotating greeting variants
+// without a code change per new variant.
+func (tm *TemplateManager) PickByTag(tag string) (*Template, error) {
+	var candidates []*Template
+	var weights []float64
+	var total float64
+
+	tm.reloadMutex.RLock()
+	for _, tmpl := range tm.templates {
+		if !tmpl.IsActive || !hasTag(tmpl.Tags, tag) {
+			continue
+		}
+		weight := templateWeight(tmpl)
+		candidates = append(candidates, tmpl)
+		weights = append(weights, weight)
+		total += weight
+	}
+	tm.reloadMutex.RUnlock()
+
+	if len(candidates) == 0 {
+		return nil, apperr.NotFound("TEMPLATE_TAG_NOT_FOUND", fmt.Sprintf("no active template tagged %q", tag))
+	}
+
+	pick := rand.Float64() * total
+	selected := candidates[len(candidates)-1] // covers the pick==total edge case
+	var cumulative float64
+	for i, weight := range weights {
+		cumulative += weight
+		if pick < cumulative {
+			selected = candidates[i]
+			break
+		}
+	}
+
+	tm.analytics.TrackEvent("template", "pick", "tag", map[string]interface{}{
+		"tag":         tag,
+		"template_id": selected.ID,
+		"candidates":  len(candidates),
+	})
+
+	return selected, nil
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// templateWeight reads tmpl.Metadata["weight"] for PickByTag, tolerating both
+// a native float64/int (set programmatically) and defaulting to 1 when
+// absent or non-positive.
+func templateWeight(tmpl *Template) float64 {
+	switch w := tmpl.Metadata["weight"].(type) {
+	case float64:
+		if w > 0 {
+			return w
+		}
+	case int:
+		if w > 0 {
+			return float64(w)
+		}
+	}
+	return 1
+}
+
+// UpdateTemplate updates an existing template. The whole read-modify-write
+// runs under reloadMutex, not just the map lookup, so a concurrent render or
+// another update can't observe or clobber a half-updated *Template.
 func (tm *TemplateManager) UpdateTemplate(id string, name, description, content, category string) (*Template, error) {
+	tm.reloadMutex.Lock()
+	defer tm.reloadMutex.Unlock()
+
 	template, exists := tm.templates[id]
 	if !exists {
-		return nil, fmt.Errorf("template not found: %s", id)
+		return nil, apperr.NotFound("TEMPLATE_NOT_FOUND", fmt.Sprintf("template not found: %s", id))
 	}
 
 	// Update fields
@@ -236,7 +594,7 @@ func (tm *TemplateManager) UpdateTemplate(id string, name, description, content,
 	if category != "" {
 		template.Category = category
 	}
-	
+
 	template.UpdatedAt = time.Now()
 
 	// Save updated template
@@ -250,24 +608,60 @@ func (tm *TemplateManager) UpdateTemplate(id string, name, description, content,
 
 // DeleteTemplate removes a template
 func (tm *TemplateManager) DeleteTemplate(id string) error {
+	tm.reloadMutex.Lock()
+	defer tm.reloadMutex.Unlock()
+
 	template, exists := tm.templates[id]
 	if !exists {
-		return fmt.Errorf("template not found: %s", id)
+		return apperr.NotFound("TEMPLATE_NOT_FOUND", fmt.Sprintf("template not found: %s", id))
 	}
 
 	// Remove file
-	filePath := filepath.Join(tm.templatesPath, id+".json")
+	filePath := filepath.Join(tm.templatesPath, normalizeWorkspace(template.Workspace), id+".json")
 	if err := os.Remove(filePath); err != nil {
 		return fmt.Errorf("failed to remove template file: %w", err)
 	}
 
 	// Remove from memory
 	delete(tm.templates, id)
-	
+	tm.invalidateParsedTemplate(id)
+
 	logrus.Infof("[TEMPLATES] Deleted template: %s (%s)", template.Name, id)
 	return nil
 }
 
+// DeleteTemplatesWhere deletes every template matching filters (the same
+// criteria SearchTemplates/matchesSearchCriteria use, e.g. "category" or
+// "tags") and returns how many were removed. Callers must pass confirm=true;
+// this exists to let a migration clean up dozens of obsolete templates in one
+// call instead of deleting them one ID at a time.
+func (tm *TemplateManager) DeleteTemplatesWhere(filters map[string]interface{}, confirm bool) (int, error) {
+	if !confirm {
+		return 0, apperr.Validation("TEMPLATE_BULK_DELETE_NOT_CONFIRMED", "bulk delete requires confirm=true")
+	}
+
+	var matched []string
+	tm.reloadMutex.RLock()
+	for id, tmpl := range tm.templates {
+		if tm.matchesSearchCriteria(tmpl, "", filters) {
+			matched = append(matched, id)
+		}
+	}
+	tm.reloadMutex.RUnlock()
+
+	deleted := 0
+	for _, id := range matched {
+		if err := tm.DeleteTemplate(id); err != nil {
+			logrus.Errorf("[TEMPLATES] Failed to delete template %s during bulk delete: %v", id, err)
+			continue
+		}
+		deleted++
+	}
+
+	logrus.Infof("[TEMPLATES] Bulk deleted %d templates matching filters %v", deleted, filters)
+	return deleted, nil
+}
+
 // RenderTemplate renders a template with provided variables (backward compatibility)
 func (tm *TemplateManager) RenderTemplate(id string, variables map[string]string) (string, error) {
 	// Convert string map to interface map
@@ -280,6 +674,7 @@ func (tm *TemplateManager) RenderTemplate(id string, variables map[string]string
 		Variables: vars,
 		Timestamp: time.Now(),
 		Language:  "en",
+		Sandboxed: true,
 	}
 
 	return tm.RenderAdvancedTemplate(id, context)
@@ -287,10 +682,75 @@ func (tm *TemplateManager) RenderTemplate(id string, variables map[string]string
 
 // RenderAdvancedTemplate renders a template with advanced context and features
 func (tm *TemplateManager) RenderAdvancedTemplate(id string, context *RenderContext) (string, error) {
-	tmpl, exists := tm.templates[id]
+	startTime := time.Now()
+	result, err := tm.renderAdvancedTemplate(id, context)
+	tm.recordRenderMetrics(id, time.Since(startTime), err)
+	return result, err
+}
+
+// DefaultBatchConcurrency caps the number of templates rendered in parallel
+// when RenderBatch is called with concurrency <= 0.
+const DefaultBatchConcurrency = 8
+
+// BatchRenderItem is one unit of work for RenderBatch: render template ID
+// with context.
+type BatchRenderItem struct {
+	ID      string
+	Context *RenderContext
+}
+
+// BatchRenderResult is the outcome of rendering one BatchRenderItem. Err is
+// non-nil if that item's render failed; it never aborts the rest of the
+// batch.
+type BatchRenderResult struct {
+	Content string
+	Err     error
+}
+
+// RenderBatch renders items using a bounded worker pool of size concurrency
+// (DefaultBatchConcurrency if concurrency <= 0), running renders in
+// parallel across CPU cores. Each render parses and executes its own
+// *template.Template, so concurrent renders don't share mutable template
+// state. Results are returned in the same order as items regardless of
+// which goroutine finishes first.
+func (tm *TemplateManager) RenderBatch(items []BatchRenderItem, concurrency int) []BatchRenderResult {
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	results := make([]BatchRenderResult, len(items))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, item BatchRenderItem) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			content, err := tm.RenderAdvancedTemplate(item.ID, item.Context)
+			results[i] = BatchRenderResult{Content: content, Err: err}
+		}(i, item)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func (tm *TemplateManager) renderAdvancedTemplate(id string, context *RenderContext) (string, error) {
+	tm.reloadMutex.RLock()
+	live, exists := tm.templates[id]
 	if !exists {
-		return "", fmt.Errorf("template not found: %s", id)
+		tm.reloadMutex.RUnlock()
+		return "", apperr.NotFound("TEMPLATE_NOT_FOUND", fmt.Sprintf("template not found: %s", id))
 	}
+	// Snapshot every field this render reads so a concurrent UpdateTemplate
+	// or RestoreTemplateVersion can't hand us a struct mid-mutation: RLock
+	// excludes their exclusive Lock while we copy it.
+	tmplCopy := *live
+	tmpl := &tmplCopy
+	tm.reloadMutex.RUnlock()
 
 	if !tmpl.IsActive {
 		return "", fmt.Errorf("template is inactive: %s", id)
@@ -315,55 +775,223 @@ func (tm *TemplateManager) RenderAdvancedTemplate(id string, context *RenderCont
 	// Set default values for missing variables
 	tm.setDefaultValues(tmpl.Variables, context.Variables)
 
-	// Parse and execute template
-	goTemplate, err := textTemplate.New(tmpl.ID).Funcs(tm.funcMap).Parse(tmpl.Content)
+	// Parse (or reuse the cached compilation) and execute template
+	parsed, err := tm.getParsedTemplate(tmpl, context.Sandboxed)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse template: %w", err)
 	}
 
+	// Clone before binding per-render locale functions: the cached template
+	// is shared across concurrent renders, and Funcs mutates the template
+	// in place, so mutating the shared copy directly would race.
+	goTemplate, err := parsed.Clone()
+	if err != nil {
+		return "", fmt.Errorf("failed to prepare template for render: %w", err)
+	}
+	goTemplate.Funcs(tm.localeFuncs(context))
+
 	var result strings.Builder
 	if err := goTemplate.Execute(&result, context); err != nil {
 		return "", fmt.Errorf("failed to execute template: %w", err)
 	}
 
-	// Update usage statistics
-	now := time.Now()
-	tmpl.UsageCount++
-	tmpl.LastUsedAt = &now
-	tmpl.UpdatedAt = now
-	tm.saveTemplate(tmpl)
+	// Update usage statistics on the live template, under the lock, not on
+	// the snapshot above - this is the write half of the read-modify-write
+	// and must not race with another render or an update/restore.
+	tm.reloadMutex.Lock()
+	if live, exists := tm.templates[id]; exists {
+		now := time.Now()
+		live.UsageCount++
+		live.LastUsedAt = &now
+		live.UpdatedAt = now
+		tm.saveTemplate(live)
+	}
+	tm.reloadMutex.Unlock()
 
-	return result.String(), nil
+	return convertOutputFormat(result.String(), context.OutputFormat), nil
 }
 
-// GetTemplateStats returns usage statistics
-func (tm *TemplateManager) GetTemplateStats() map[string]interface{} {
-	stats := map[string]interface{}{
-		"total_templates": len(tm.templates),
-		"categories":      make(map[string]int),
-		"most_used":       "",
-		"total_usage":     0,
+// whatsAppMarkdownPattern matches one WhatsApp-formatted span: monospace
+// (triple backtick), bold (*), italic (_), or strikethrough (~).
+var whatsAppMarkdownPattern = regexp.MustCompile("```(.+?)```" + `|\*(\S(?:[^*\n]*\S)?)\*|_(\S(?:[^_\n]*\S)?)_|~(\S(?:[^~\n]*\S)?)~`)
+
+// convertOutputFormat translates content, written in the canonical WhatsApp
+// markdown template authors write (*bold*, _italic_, ~strike~,
+// ```monospace```), into the requested output format. An empty or
+// unrecognized format is treated as FormatWhatsApp and returns content
+// unchanged.
+func convertOutputFormat(content, format string) string {
+	switch strings.ToLower(format) {
+	case FormatPlain:
+		return whatsAppMarkdownPattern.ReplaceAllStringFunc(content, func(match string) string {
+			groups := whatsAppMarkdownPattern.FindStringSubmatch(match)
+			return firstNonEmpty(groups[1:])
+		})
+	case FormatHTML:
+		// Escape the whole string first, then wrap the recognized markdown
+		// spans in tags. content can carry variable values sourced from
+		// contact/chat data or message text, so it must never reach an HTML
+		// context unescaped - escaping only the text captured inside a span
+		// would still let unrecognized text (or the delimiters themselves)
+		// through raw.
+		escaped := html.EscapeString(content)
+		return whatsAppMarkdownPattern.ReplaceAllStringFunc(escaped, func(match string) string {
+			groups := whatsAppMarkdownPattern.FindStringSubmatch(match)
+			switch {
+			case groups[1] != "":
+				return "<code>" + groups[1] + "</code>"
+			case groups[2] != "":
+				return "<b>" + groups[2] + "</b>"
+			case groups[3] != "":
+				return "<i>" + groups[3] + "</i>"
+			default:
+				return "<s>" + groups[4] + "</s>"
+			}
+		})
+	default:
+		return content
+	}
+}
+
+// firstNonEmpty returns the first non-empty string in values, or "" if none.
+func firstNonEmpty(values []string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// getParsedTemplate returns tmpl.Content compiled into a *template.Template,
+// reusing a cached compilation keyed by tmpl.ID and sandboxed when its
+// content hash still matches. The returned template must not be mutated
+// (via Funcs) by the caller since it's shared across concurrent renders;
+// call Clone first if per-render function bindings are needed.
+func (tm *TemplateManager) getParsedTemplate(tmpl *Template, sandboxed bool) (*textTemplate.Template, error) {
+	key := parsedTemplateCacheKey{id: tmpl.ID, sandboxed: sandboxed}
+	hash := contentHash(tmpl.Content)
+
+	tm.parsedMutex.RLock()
+	cached, ok := tm.parsedTemplates[key]
+	tm.parsedMutex.RUnlock()
+	if ok && cached.contentHash == hash {
+		return cached.tmpl, nil
+	}
+
+	funcMap := tm.funcMap
+	if sandboxed {
+		funcMap = sandboxedFuncMap(funcMap)
+	}
+	parsed, err := textTemplate.New(tmpl.ID).Funcs(funcMap).Parse(tmpl.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	tm.parsedMutex.Lock()
+	tm.parsedTemplates[key] = &cachedTemplate{contentHash: hash, tmpl: parsed}
+	tm.parsedMutex.Unlock()
+
+	return parsed, nil
+}
+
+// contentHash hashes template content for cache invalidation, not security.
+func contentHash(content string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(content))
+	return h.Sum64()
+}
+
+// invalidateParsedTemplate drops every cached compilation of id. Called when
+// a template is deleted so its cache entries don't linger forever; content
+// updates don't need this since getParsedTemplate detects the hash mismatch
+// and recompiles on its own.
+func (tm *TemplateManager) invalidateParsedTemplate(id string) {
+	tm.parsedMutex.Lock()
+	defer tm.parsedMutex.Unlock()
+	delete(tm.parsedTemplates, parsedTemplateCacheKey{id: id, sandboxed: false})
+	delete(tm.parsedTemplates, parsedTemplateCacheKey{id: id, sandboxed: true})
+}
+
+// recordRenderMetrics updates both the per-template and global rendering
+// metrics after a render attempt, whether it succeeded or failed.
+func (tm *TemplateManager) recordRenderMetrics(id string, duration time.Duration, renderErr error) {
+	tm.metricsMutex.Lock()
+	defer tm.metricsMutex.Unlock()
+
+	metrics, exists := tm.renderMetrics[id]
+	if !exists {
+		metrics = &RenderMetrics{}
+		tm.renderMetrics[id] = metrics
+	}
+
+	for _, m := range []*RenderMetrics{metrics, tm.globalMetrics} {
+		now := time.Now()
+		m.TotalRenders++
+		m.TotalDuration += duration
+		m.AverageLatency = m.TotalDuration / time.Duration(m.TotalRenders)
+		if renderErr != nil {
+			m.TotalErrors++
+		}
+		m.ErrorRate = float64(m.TotalErrors) / float64(m.TotalRenders) * 100
+		m.LastRenderedAt = &now
+	}
+}
+
+// GetRenderMetrics returns rendering metrics for a single template.
+func (tm *TemplateManager) GetRenderMetrics(id string) (*RenderMetrics, error) {
+	tm.metricsMutex.Lock()
+	defer tm.metricsMutex.Unlock()
+
+	metrics, exists := tm.renderMetrics[id]
+	if !exists {
+		return nil, apperr.NotFound("TEMPLATE_METRICS_NOT_FOUND", fmt.Sprintf("no render metrics recorded for template: %s", id))
 	}
 
+	copied := *metrics
+	return &copied, nil
+}
+
+// GetGlobalRenderMetrics returns rendering metrics aggregated across all templates.
+func (tm *TemplateManager) GetGlobalRenderMetrics() *RenderMetrics {
+	tm.metricsMutex.Lock()
+	defer tm.metricsMutex.Unlock()
+
+	copied := *tm.globalMetrics
+	return &copied
+}
+
+// GetTemplateStats returns usage statistics
+func (tm *TemplateManager) GetTemplateStats() map[string]interface{} {
 	categories := make(map[string]int)
 	var mostUsed *Template
 	totalUsage := 0
 
+	tm.reloadMutex.RLock()
+	totalTemplates := len(tm.templates)
 	for _, template := range tm.templates {
 		// Count by category
 		categories[template.Category]++
-		
+
 		// Track most used
 		if mostUsed == nil || template.UsageCount > mostUsed.UsageCount {
 			mostUsed = template
 		}
-		
+
 		totalUsage += template.UsageCount
 	}
+	tm.reloadMutex.RUnlock()
+
+	stats := map[string]interface{}{
+		"total_templates": totalTemplates,
+		"categories":      make(map[string]int),
+		"most_used":       "",
+		"total_usage":     0,
+	}
 
 	stats["categories"] = categories
 	stats["total_usage"] = totalUsage
-	
+
 	if mostUsed != nil {
 		stats["most_used"] = map[string]interface{}{
 			"id":          mostUsed.ID,
@@ -379,9 +1007,11 @@ func (tm *TemplateManager) GetTemplateStats() map[string]interface{} {
 
 // CloneTemplate creates a copy of an existing template
 func (tm *TemplateManager) CloneTemplate(id, newName string) (*Template, error) {
+	tm.reloadMutex.RLock()
 	original, exists := tm.templates[id]
+	tm.reloadMutex.RUnlock()
 	if !exists {
-		return nil, fmt.Errorf("template not found: %s", id)
+		return nil, apperr.NotFound("TEMPLATE_NOT_FOUND", fmt.Sprintf("template not found: %s", id))
 	}
 
 	clone := *original // Copy struct
@@ -397,7 +1027,9 @@ func (tm *TemplateManager) CloneTemplate(id, newName string) (*Template, error)
 		return nil, fmt.Errorf("failed to save cloned template: %w", err)
 	}
 
+	tm.reloadMutex.Lock()
 	tm.templates[clone.ID] = &clone
+	tm.reloadMutex.Unlock()
 	tm.createVersion(clone.ID, clone.Content, "system", "Cloned from "+original.Name)
 
 	return &clone, nil
@@ -405,8 +1037,11 @@ func (tm *TemplateManager) CloneTemplate(id, newName string) (*Template, error)
 
 // GetTemplateVersions returns all versions of a template
 func (tm *TemplateManager) GetTemplateVersions(id string) ([]TemplateVersion, error) {
+	tm.reloadMutex.RLock()
+	defer tm.reloadMutex.RUnlock()
+
 	if _, exists := tm.templates[id]; !exists {
-		return nil, fmt.Errorf("template not found: %s", id)
+		return nil, apperr.NotFound("TEMPLATE_NOT_FOUND", fmt.Sprintf("template not found: %s", id))
 	}
 
 	versions, exists := tm.versions[id]
@@ -417,16 +1052,114 @@ func (tm *TemplateManager) GetTemplateVersions(id string) ([]TemplateVersion, er
 	return versions, nil
 }
 
-// RestoreTemplateVersion restores a template to a specific version
+// DiffVersions produces a unified diff of two stored versions' content plus
+// a summary of variables added or removed between them, so reviewers can
+// see what actually changed before restoring an older version.
+func (tm *TemplateManager) DiffVersions(id, fromVersion, toVersion string) (string, error) {
+	tm.reloadMutex.RLock()
+	_, templateExists := tm.templates[id]
+	versions, versionsExist := tm.versions[id]
+	tm.reloadMutex.RUnlock()
+
+	if !templateExists {
+		return "", apperr.NotFound("TEMPLATE_NOT_FOUND", fmt.Sprintf("template not found: %s", id))
+	}
+	if !versionsExist {
+		return "", apperr.NotFound("TEMPLATE_VERSIONS_NOT_FOUND", fmt.Sprintf("no versions found for template: %s", id))
+	}
+
+	from, err := findTemplateVersion(versions, fromVersion)
+	if err != nil {
+		return "", err
+	}
+	to, err := findTemplateVersion(versions, toVersion)
+	if err != nil {
+		return "", err
+	}
+
+	unified, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(from.Content),
+		B:        difflib.SplitLines(to.Content),
+		FromFile: fromVersion,
+		ToFile:   toVersion,
+		Context:  3,
+	})
+	if err != nil {
+		return "", apperr.Internal("TEMPLATE_DIFF_FAILED", "failed to compute diff", err)
+	}
+
+	added, removed := diffVariableNames(tm.extractAdvancedVariables(from.Content), tm.extractAdvancedVariables(to.Content))
+
+	var out strings.Builder
+	out.WriteString(unified)
+	out.WriteString("--- variables ---\n")
+	if len(added) == 0 && len(removed) == 0 {
+		out.WriteString("no variable changes\n")
+	}
+	for _, name := range added {
+		out.WriteString(fmt.Sprintf("+ %s\n", name))
+	}
+	for _, name := range removed {
+		out.WriteString(fmt.Sprintf("- %s\n", name))
+	}
+
+	return out.String(), nil
+}
+
+// findTemplateVersion locates a version by its version string.
+func findTemplateVersion(versions []TemplateVersion, version string) (*TemplateVersion, error) {
+	for i := range versions {
+		if versions[i].Version == version {
+			return &versions[i], nil
+		}
+	}
+	return nil, apperr.NotFound("TEMPLATE_VERSION_NOT_FOUND", fmt.Sprintf("version not found: %s", version))
+}
+
+// diffVariableNames compares two variable sets and reports names present in
+// to but not from (added) and names present in from but not to (removed).
+func diffVariableNames(from, to []Variable) (added, removed []string) {
+	fromSet := make(map[string]bool, len(from))
+	for _, v := range from {
+		fromSet[v.Name] = true
+	}
+	toSet := make(map[string]bool, len(to))
+	for _, v := range to {
+		toSet[v.Name] = true
+	}
+
+	for name := range toSet {
+		if !fromSet[name] {
+			added = append(added, name)
+		}
+	}
+	for name := range fromSet {
+		if !toSet[name] {
+			removed = append(removed, name)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// RestoreTemplateVersion restores a template to a specific version. Like
+// UpdateTemplate, the backup-version append and the content restore run
+// under a single reloadMutex hold so a concurrent render or update can't
+// interleave with the restore.
 func (tm *TemplateManager) RestoreTemplateVersion(id, version string) error {
+	tm.reloadMutex.Lock()
+
 	tmpl, exists := tm.templates[id]
 	if !exists {
-		return fmt.Errorf("template not found: %s", id)
+		tm.reloadMutex.Unlock()
+		return apperr.NotFound("TEMPLATE_NOT_FOUND", fmt.Sprintf("template not found: %s", id))
 	}
-
-	versions, exists := tm.versions[id]
-	if !exists {
-		return fmt.Errorf("no versions found for template: %s", id)
+	versions, versionsExist := tm.versions[id]
+	if !versionsExist {
+		tm.reloadMutex.Unlock()
+		return apperr.NotFound("TEMPLATE_VERSIONS_NOT_FOUND", fmt.Sprintf("no versions found for template: %s", id))
 	}
 
 	var targetVersion *TemplateVersion
@@ -438,30 +1171,43 @@ func (tm *TemplateManager) RestoreTemplateVersion(id, version string) error {
 	}
 
 	if targetVersion == nil {
-		return fmt.Errorf("version not found: %s", version)
+		tm.reloadMutex.Unlock()
+		return apperr.NotFound("TEMPLATE_VERSION_NOT_FOUND", fmt.Sprintf("version not found: %s", version))
 	}
 
 	// Create backup of current version
-	tm.createVersion(id, tmpl.Content, "system", "Backup before restore to "+version)
+	tm.createVersionLocked(id, tmpl.Content, "system", "Backup before restore to "+version)
 
 	// Restore content
 	tmpl.Content = targetVersion.Content
 	tmpl.Variables = tm.extractAdvancedVariables(tmpl.Content)
 	tmpl.UpdatedAt = time.Now()
 
-	return tm.saveTemplate(tmpl)
+	saveErr := tm.saveTemplate(tmpl)
+	tm.reloadMutex.Unlock()
+
+	tm.saveVersions(id)
+
+	return saveErr
 }
 
-// SearchTemplates searches templates by various criteria
-func (tm *TemplateManager) SearchTemplates(query string, filters map[string]interface{}) []*Template {
+// SearchTemplates searches templates by various criteria within a
+// workspace. An empty workspace scopes to defaultWorkspace.
+func (tm *TemplateManager) SearchTemplates(workspace, query string, filters map[string]interface{}) []*Template {
+	workspace = normalizeWorkspace(workspace)
 	var results []*Template
 	query = strings.ToLower(query)
 
+	tm.reloadMutex.RLock()
 	for _, tmpl := range tm.templates {
+		if normalizeWorkspace(tmpl.Workspace) != workspace {
+			continue
+		}
 		if tm.matchesSearchCriteria(tmpl, query, filters) {
 			results = append(results, tmpl)
 		}
 	}
+	tm.reloadMutex.RUnlock()
 
 	return results
 }
@@ -469,7 +1215,9 @@ func (tm *TemplateManager) SearchTemplates(query string, filters map[string]inte
 // BulkUpdateTemplates updates multiple templates at once
 func (tm *TemplateManager) BulkUpdateTemplates(updates map[string]map[string]interface{}) error {
 	for id, updateData := range updates {
+		tm.reloadMutex.RLock()
 		tmpl, exists := tm.templates[id]
+		tm.reloadMutex.RUnlock()
 		if !exists {
 			continue
 		}
@@ -495,6 +1243,68 @@ func (tm *TemplateManager) BulkUpdateTemplates(updates map[string]map[string]int
 	return nil
 }
 
+// startScheduleSync periodically flips IsActive on templates whose
+// Scheduling window has started or ended, so scheduled templates drop out
+// of ListTemplates on their own instead of requiring a manual toggle.
+func (tm *TemplateManager) startScheduleSync() {
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		tm.syncScheduledActivation()
+	}
+}
+
+// syncScheduledActivation checks every template with scheduling enabled and
+// activates or deactivates it based on the current time relative to
+// Scheduling.StartDate/EndDate, emitting an analytics event on each flip.
+func (tm *TemplateManager) syncScheduledActivation() {
+	now := time.Now()
+
+	tm.reloadMutex.RLock()
+	templates := make([]*Template, 0, len(tm.templates))
+	for _, tmpl := range tm.templates {
+		templates = append(templates, tmpl)
+	}
+	tm.reloadMutex.RUnlock()
+
+	for _, tmpl := range templates {
+		schedule := tmpl.Scheduling
+		if schedule == nil || !schedule.Enabled {
+			continue
+		}
+
+		shouldBeActive := true
+		if !schedule.StartDate.IsZero() && now.Before(schedule.StartDate) {
+			shouldBeActive = false
+		} else if !schedule.EndDate.IsZero() && now.After(schedule.EndDate) {
+			shouldBeActive = false
+		}
+
+		if shouldBeActive == tmpl.IsActive {
+			continue
+		}
+
+		tmpl.IsActive = shouldBeActive
+		tmpl.UpdatedAt = now
+		tm.saveTemplate(tmpl)
+
+		action := "deactivated"
+		if shouldBeActive {
+			action = "activated"
+		}
+
+		tm.analytics.TrackEvent("template", "schedule", action, map[string]interface{}{
+			"template_id":   tmpl.ID,
+			"template_name": tmpl.Name,
+			"start_date":    schedule.StartDate,
+			"end_date":      schedule.EndDate,
+		})
+
+		logrus.Infof("[TEMPLATES] Schedule sync %s template %s (%s)", action, tmpl.Name, tmpl.ID)
+	}
+}
+
 // Private methods
 
 func (tm *TemplateManager) generateTemplateID(name string) string {
@@ -509,27 +1319,45 @@ func (tm *TemplateManager) extractAdvancedVariables(content string) []Variable {
 	var variables []Variable
 	variableMap := make(map[string]bool)
 
-	// Find all {{.Variables.variable}} patterns (Go template format)
-	re := regexp.MustCompile(`\{\{\.Variables\.(\w+)(?:\s*\|\s*(\w+))?\}\}`)
+	// Find all {{.Variables.variable}}, {{.Variables.variable.nested}} and
+	// piped {{.Variables.variable | fn}} patterns (Go template format). Only
+	// the root variable is tracked - nested access is resolved at render
+	// time against the value stored under the root.
+	re := regexp.MustCompile(`\{\{\.Variables\.(\w+)((?:\.\w+)*)(?:\s*\|\s*(\w+))?\}\}`)
 	matches := re.FindAllStringSubmatch(content, -1)
 
 	for _, match := range matches {
 		varName := match[1]
+		nested := match[2] != ""
 		varType := "text" // default type
-		
-		if len(match) > 2 && match[2] != "" {
-			varType = match[2]
+
+		if nested {
+			varType = "object"
+		} else if len(match) > 3 && match[3] != "" {
+			varType = match[3]
 		}
 
-		if !variableMap[varName] {
-			variables = append(variables, Variable{
-				Name:        varName,
-				Type:        varType,
-				Required:    true,
-				Description: fmt.Sprintf("Variable: %s", varName),
-			})
-			variableMap[varName] = true
+		if variableMap[varName] {
+			// A nested access seen anywhere for this variable upgrades its
+			// tracked type to "object", even if a bare reference was seen first.
+			if nested {
+				for i := range variables {
+					if variables[i].Name == varName {
+						variables[i].Type = "object"
+						break
+					}
+				}
+			}
+			continue
 		}
+
+		variables = append(variables, Variable{
+			Name:        varName,
+			Type:        varType,
+			Required:    true,
+			Description: fmt.Sprintf("Variable: %s", varName),
+		})
+		variableMap[varName] = true
 	}
 
 	// Also find simple {{variable}} patterns for backward compatibility
@@ -555,7 +1383,7 @@ func (tm *TemplateManager) extractAdvancedVariables(content string) []Variable {
 func (tm *TemplateManager) validateTemplate(template *Template) error {
 	// Create a custom template with required functions
 	tmpl := textTemplate.New(template.ID)
-	
+
 	// Add custom template functions
 	tmpl = tmpl.Funcs(textTemplate.FuncMap{
 		"default": func(value interface{}, defaultValue interface{}) interface{} {
@@ -596,7 +1424,7 @@ func (tm *TemplateManager) validateTemplate(template *Template) error {
 			}
 		},
 	})
-	
+
 	// Parse the template content
 	_, err := tmpl.Parse(template.Content)
 	if err != nil {
@@ -661,8 +1489,7 @@ func (tm *TemplateManager) validateVariableValue(variable Variable, value interf
 		}
 	case "phone":
 		if str, ok := value.(string); ok {
-			phoneRegex := regexp.MustCompile(`^\+?[1-9]\d{1,14}$`)
-			if !phoneRegex.MatchString(str) {
+			if _, err := utils.NormalizePhone(str); err != nil {
 				return fmt.Errorf("invalid phone format")
 			}
 		}
@@ -677,6 +1504,10 @@ func (tm *TemplateManager) validateVariableValue(variable Variable, value interf
 		if _, err := strconv.ParseFloat(fmt.Sprintf("%v", value), 64); err != nil {
 			return fmt.Errorf("invalid number format")
 		}
+	case "object":
+		if _, ok := value.(map[string]interface{}); !ok {
+			return fmt.Errorf("expected an object with nested fields")
+		}
 	}
 
 	// Custom regex validation
@@ -696,76 +1527,223 @@ func (tm *TemplateManager) validateVariableValue(variable Variable, value interf
 }
 
 func (tm *TemplateManager) saveTemplate(template *Template) error {
-	filePath := filepath.Join(tm.templatesPath, template.ID+".json")
-	
+	template.Workspace = normalizeWorkspace(template.Workspace)
+
+	workspaceDir := filepath.Join(tm.templatesPath, template.Workspace)
+	if err := os.MkdirAll(workspaceDir, 0755); err != nil {
+		return err
+	}
+	filePath := filepath.Join(workspaceDir, template.ID+".json")
+
 	data, err := json.MarshalIndent(template, "", "  ")
 	if err != nil {
 		return err
 	}
-	
-	return os.WriteFile(filePath, data, 0644)
+
+	return writeFileAtomic(filePath, data)
+}
+
+// writeFileAtomic writes data to a temp file in the same directory as path
+// and renames it into place, so a crash mid-write leaves either the old
+// file or the new one intact - never a partially written, unparseable file.
+func writeFileAtomic(path string, data []byte) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return nil
 }
 
 func (tm *TemplateManager) loadTemplates() {
-	pattern := filepath.Join(tm.templatesPath, "*.json")
-	matches, err := filepath.Glob(pattern)
+	tm.templates = tm.loadTemplatesFromDisk()
+	logrus.Infof("[TEMPLATES] Loaded %d templates", len(tm.templates))
+}
+
+// loadTemplatesFromDisk reads every template JSON file into a fresh map
+// without touching tm.templates, so callers can build a complete
+// replacement map before swapping it in (see Reload). It reads both the
+// current <workspace>/<id>.json layout and, for backward compatibility,
+// flat <id>.json files left over from before workspaces existed - those are
+// migrated into the default workspace's directory as they're loaded.
+func (tm *TemplateManager) loadTemplatesFromDisk() map[string]*Template {
+	templates := make(map[string]*Template)
+
+	legacyMatches, err := filepath.Glob(filepath.Join(tm.templatesPath, "*.json"))
+	if err != nil {
+		logrus.Errorf("[TEMPLATES] Failed to load templates: %v", err)
+		return templates
+	}
+	workspaceMatches, err := filepath.Glob(filepath.Join(tm.templatesPath, "*", "*.json"))
 	if err != nil {
 		logrus.Errorf("[TEMPLATES] Failed to load templates: %v", err)
+		return templates
+	}
+
+	for _, filePath := range legacyMatches {
+		tm.loadTemplateFile(filePath, true, templates)
+	}
+	for _, filePath := range workspaceMatches {
+		tm.loadTemplateFile(filePath, false, templates)
+	}
+
+	return templates
+}
+
+// loadTemplateFile reads one template JSON file into templates, recovering
+// it from version history if the file is corrupted. legacy is true for a
+// flat pre-workspace file, which gets migrated into the default workspace's
+// directory once successfully loaded.
+func (tm *TemplateManager) loadTemplateFile(filePath string, legacy bool, templates map[string]*Template) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		logrus.Errorf("[TEMPLATES] Failed to read template file %s: %v", filePath, err)
 		return
 	}
 
-	for _, filePath := range matches {
-		data, err := os.ReadFile(filePath)
-		if err != nil {
-			logrus.Errorf("[TEMPLATES] Failed to read template file %s: %v", filePath, err)
-			continue
-		}
+	var template Template
+	if err := json.Unmarshal(data, &template); err != nil {
+		templateID := strings.TrimSuffix(filepath.Base(filePath), ".json")
+		logrus.Errorf("[TEMPLATES] Failed to unmarshal template file %s: %v", filePath, err)
 
-		var template Template
-		if err := json.Unmarshal(data, &template); err != nil {
-			logrus.Errorf("[TEMPLATES] Failed to unmarshal template file %s: %v", filePath, err)
-			continue
+		recovered, recErr := tm.recoverTemplateFromVersions(templateID)
+		if recErr != nil {
+			logrus.Errorf("[TEMPLATES] Could not recover template %s from version history: %v", templateID, recErr)
+			return
 		}
 
-		tm.templates[template.ID] = &template
+		logrus.Warnf("[TEMPLATES] Recovered template %s (version %s) from version history after corrupted file", templateID, recovered.Version)
+		if saveErr := tm.saveTemplate(recovered); saveErr != nil {
+			logrus.Errorf("[TEMPLATES] Failed to persist recovered template %s: %v", templateID, saveErr)
+		}
+		templates[recovered.ID] = recovered
+		return
 	}
 
-	logrus.Infof("[TEMPLATES] Loaded %d templates", len(tm.templates))
+	template.Workspace = normalizeWorkspace(template.Workspace)
+	templates[template.ID] = &template
+
+	if legacy {
+		if err := tm.saveTemplate(&template); err != nil {
+			logrus.Errorf("[TEMPLATES] Failed to migrate legacy template %s into workspace %s: %v", template.ID, template.Workspace, err)
+			return
+		}
+		if err := os.Remove(filePath); err != nil {
+			logrus.Errorf("[TEMPLATES] Failed to remove migrated legacy template file %s: %v", filePath, err)
+		}
+	}
 }
 
 func (tm *TemplateManager) createFuncMap() textTemplate.FuncMap {
 	return textTemplate.FuncMap{
-		"upper":      strings.ToUpper,
-		"lower":      strings.ToLower,
-		"title":      strings.Title,
-		"trim":       strings.TrimSpace,
-		"now":        time.Now,
-		"formatDate": tm.formatDate,
-		"formatNumber": tm.formatNumber,
-		"default":    tm.defaultValue,
-		"contains":   strings.Contains,
-		"replace":    strings.ReplaceAll,
-		"substr":     tm.substr,
-		"add":        tm.add,
-		"multiply":   tm.multiply,
-		"divide":     tm.divide,
-		"modulo":     tm.modulo,
-		"eq":         tm.eq,
-		"ne":         tm.ne,
-		"gt":         tm.gt,
-		"lt":         tm.lt,
-		"gte":        tm.gte,
-		"lte":        tm.lte,
-		"and":        tm.and,
-		"or":         tm.or,
-		"not":        tm.not,
-		"join":       strings.Join,
-		"split":      strings.Split,
-		"len":        tm.length,
-		"first":      tm.first,
-		"last":       tm.last,
-		"slice":      tm.slice,
+		"upper":            strings.ToUpper,
+		"lower":            strings.ToLower,
+		"title":            strings.Title,
+		"trim":             strings.TrimSpace,
+		"now":              time.Now,
+		"formatDate":       tm.formatDate,
+		"formatNumber":     tm.formatNumber,
+		"formatCurrency":   tm.formatCurrency,
+		"formatDateLocale": tm.formatDateLocale,
+		"default":          tm.defaultValue,
+		"contains":         strings.Contains,
+		"replace":          strings.ReplaceAll,
+		"substr":           tm.substr,
+		"add":              tm.add,
+		"multiply":         tm.multiply,
+		"divide":           tm.divide,
+		"modulo":           tm.modulo,
+		"eq":               tm.eq,
+		"ne":               tm.ne,
+		"gt":               tm.gt,
+		"lt":               tm.lt,
+		"gte":              tm.gte,
+		"lte":              tm.lte,
+		"and":              tm.and,
+		"or":               tm.or,
+		"not":              tm.not,
+		"join":             strings.Join,
+		"split":            strings.Split,
+		"len":              tm.length,
+		"first":            tm.first,
+		"last":             tm.last,
+		"slice":            tm.slice,
+	}
+}
+
+// sandboxAllowedFuncs is the explicit allowlist of funcMap entries permitted
+// when RenderContext.Sandboxed is set. It's a deliberate allowlist rather
+// than createFuncMap filtered by some inferred property, so that a future
+// func added to createFuncMap with filesystem, network, or process access
+// doesn't become sandbox-reachable just by existing - it has to be added
+// here on purpose. Every func currently in createFuncMap is pure string,
+// number, and time formatting/comparison with no such access, so today's
+// allowlist happens to match it entry for entry.
+var sandboxAllowedFuncs = map[string]bool{
+	"upper":            true,
+	"lower":            true,
+	"title":            true,
+	"trim":             true,
+	"now":              true,
+	"formatDate":       true,
+	"formatNumber":     true,
+	"formatCurrency":   true,
+	"formatDateLocale": true,
+	"default":          true,
+	"contains":         true,
+	"replace":          true,
+	"substr":           true,
+	"add":              true,
+	"multiply":         true,
+	"divide":           true,
+	"modulo":           true,
+	"eq":               true,
+	"ne":               true,
+	"gt":               true,
+	"lt":               true,
+	"gte":              true,
+	"lte":              true,
+	"and":              true,
+	"or":               true,
+	"not":              true,
+	"join":             true,
+	"split":            true,
+	"len":              true,
+	"first":            true,
+	"last":             true,
+	"slice":            true,
+}
+
+// sandboxedFuncMap filters full down to sandboxAllowedFuncs, dropping any
+// entry (present or future) that isn't explicitly vetted as safe.
+func sandboxedFuncMap(full textTemplate.FuncMap) textTemplate.FuncMap {
+	sandboxed := make(textTemplate.FuncMap, len(sandboxAllowedFuncs))
+	for name, fn := range full {
+		if sandboxAllowedFuncs[name] {
+			sandboxed[name] = fn
+		}
 	}
+	return sandboxed
 }
 
 // Template helper functions
@@ -800,6 +1778,121 @@ func (tm *TemplateManager) formatNumber(format string, number interface{}) strin
 	return fmt.Sprintf("%v", number)
 }
 
+// localizedMonths and localizedWeekdays translate the full English month and
+// weekday names time.Format produces into a handful of common recipient
+// locales, keyed by the ISO 639-1 base language. Abbreviated layout tokens
+// ("Jan", "Mon") aren't covered since Go's time package only emits English
+// abbreviations to translate from.
+var localizedMonths = map[string][12]string{
+	"es": {"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+	"fr": {"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+	"de": {"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+	"pt": {"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+	"id": {"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"},
+}
+
+var localizedWeekdays = map[string][7]string{
+	"es": {"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+	"fr": {"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+	"de": {"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+	"pt": {"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+	"id": {"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu"},
+}
+
+// localeFuncs rebinds formatCurrency and formatDateLocale so that an
+// omitted (empty) locale argument defaults to context.Language instead of
+// always falling back to English.
+func (tm *TemplateManager) localeFuncs(context *RenderContext) textTemplate.FuncMap {
+	defaultLocale := context.Language
+	if defaultLocale == "" {
+		defaultLocale = "en"
+	}
+
+	return textTemplate.FuncMap{
+		"formatCurrency": func(locale string, value interface{}) string {
+			if locale == "" {
+				locale = defaultLocale
+			}
+			return tm.formatCurrency(locale, value)
+		},
+		"formatDateLocale": func(format, locale string, value interface{}) string {
+			if locale == "" {
+				locale = defaultLocale
+			}
+			return tm.formatDateLocale(format, locale, value)
+		},
+	}
+}
+
+// parseLocaleTag resolves locale to a BCP 47 language tag, defaulting to
+// English when locale is empty or unrecognized.
+func parseLocaleTag(locale string) language.Tag {
+	if locale == "" {
+		return language.English
+	}
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return language.English
+	}
+	return tag
+}
+
+// formatCurrency renders value as a currency amount using CLDR grouping,
+// decimal separator, and symbol rules for locale (e.g. "de-DE", "id-ID"),
+// instead of formatNumber's hardcoded "$" and US conventions. locale falls
+// back to English/USD when empty or unrecognized; renderAdvancedTemplate
+// rebinds this func per-render so an omitted locale defaults to the
+// RenderContext's Language instead.
+func (tm *TemplateManager) formatCurrency(locale string, value interface{}) string {
+	f, err := strconv.ParseFloat(fmt.Sprintf("%v", value), 64)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	tag := parseLocaleTag(locale)
+	unit, confidence := currency.FromTag(tag)
+	if confidence == language.No {
+		unit = currency.USD
+	}
+
+	p := message.NewPrinter(tag)
+	return p.Sprint(currency.Symbol(unit.Amount(f)))
+}
+
+// formatDateLocale formats value with the given Go layout, then translates
+// the (always-English) month and weekday names time.Format produces into
+// locale. Numeric layout tokens are unaffected. locale falls back to English
+// when empty or unrecognized; renderAdvancedTemplate rebinds this func
+// per-render so an omitted locale defaults to the RenderContext's Language.
+func (tm *TemplateManager) formatDateLocale(format, locale string, value interface{}) string {
+	var t time.Time
+	switch v := value.(type) {
+	case time.Time:
+		t = v
+	case string:
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return v
+		}
+		t = parsed
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+
+	formatted := t.Format(format)
+
+	base, _ := parseLocaleTag(locale).Base()
+	baseStr := base.String()
+	if months, ok := localizedMonths[baseStr]; ok {
+		formatted = strings.ReplaceAll(formatted, t.Month().String(), months[t.Month()-1])
+	}
+	if weekdays, ok := localizedWeekdays[baseStr]; ok {
+		formatted = strings.ReplaceAll(formatted, t.Weekday().String(), weekdays[t.Weekday()])
+	}
+
+	return formatted
+}
+
 func (tm *TemplateManager) defaultValue(defaultVal, value interface{}) interface{} {
 	if value == nil || value == "" {
 		return defaultVal
@@ -968,11 +2061,113 @@ func (tm *TemplateManager) applyTransformers(transformers []Transformer, variabl
 }
 
 func (tm *TemplateManager) setDefaultValues(variables []Variable, context map[string]interface{}) {
+	// First pass: plain defaults, so conditional defaults on other variables
+	// (second pass) can reference them.
 	for _, variable := range variables {
+		if len(variable.ConditionalDefaults) > 0 {
+			continue
+		}
 		if _, exists := context[variable.Name]; !exists && variable.DefaultValue != nil {
 			context[variable.Name] = variable.DefaultValue
 		}
 	}
+
+	for _, variable := range variables {
+		if len(variable.ConditionalDefaults) == 0 {
+			continue
+		}
+		if _, exists := context[variable.Name]; exists {
+			continue
+		}
+
+		if value, matched := tm.resolveConditionalDefault(variable.ConditionalDefaults, context); matched {
+			context[variable.Name] = value
+		} else if variable.DefaultValue != nil {
+			context[variable.Name] = variable.DefaultValue
+		}
+	}
+}
+
+// resolveConditionalDefault returns the Value of the first ConditionalDefault
+// whose When conditions all match context, in declared order.
+func (tm *TemplateManager) resolveConditionalDefault(defaults []ConditionalDefault, context map[string]interface{}) (interface{}, bool) {
+	for _, def := range defaults {
+		if tm.matchesAllConditions(def.When, context) {
+			return def.Value, true
+		}
+	}
+	return nil, false
+}
+
+func (tm *TemplateManager) matchesAllConditions(conditions []Condition, context map[string]interface{}) bool {
+	if len(conditions) == 0 {
+		return false
+	}
+	for _, condition := range conditions {
+		if !EvaluateCondition(condition, context) {
+			return false
+		}
+	}
+	return true
+}
+
+// EvaluateCondition checks a single Condition against context, supporting
+// the operator vocabulary validated by isValidOperator (eq, ne, gt, lt,
+// contains, regex). Exported so other packages building their own
+// condition-driven matching (e.g. pkg/autoreply's rules) reuse the same
+// vocabulary instead of inventing a divergent one.
+func EvaluateCondition(condition Condition, context map[string]interface{}) bool {
+	actual, exists := context[condition.Field]
+	if !exists {
+		return false
+	}
+
+	switch condition.Operator {
+	case "eq":
+		return fmt.Sprintf("%v", actual) == fmt.Sprintf("%v", condition.Value)
+	case "ne":
+		return fmt.Sprintf("%v", actual) != fmt.Sprintf("%v", condition.Value)
+	case "contains":
+		return strings.Contains(fmt.Sprintf("%v", actual), fmt.Sprintf("%v", condition.Value))
+	case "regex":
+		pattern, ok := condition.Value.(string)
+		if !ok {
+			return false
+		}
+		matched, err := regexp.MatchString(pattern, fmt.Sprintf("%v", actual))
+		return err == nil && matched
+	case "gt", "lt":
+		actualNum, ok1 := toFloat(actual)
+		expectedNum, ok2 := toFloat(condition.Value)
+		if !ok1 || !ok2 {
+			return false
+		}
+		if condition.Operator == "gt" {
+			return actualNum > expectedNum
+		}
+		return actualNum < expectedNum
+	default:
+		return false
+	}
+}
+
+// toFloat coerces common context value types to float64 for gt/lt comparisons.
+func toFloat(v interface{}) (float64, bool) {
+	switch value := v.(type) {
+	case float64:
+		return value, true
+	case float32:
+		return float64(value), true
+	case int:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case string:
+		parsed, err := strconv.ParseFloat(value, 64)
+		return parsed, err == nil
+	default:
+		return 0, false
+	}
 }
 
 func (tm *TemplateManager) isTemplateScheduleValid(schedule *ScheduleConfig) bool {
@@ -1083,6 +2278,16 @@ func (tm *TemplateManager) matchesSearchCriteria(tmpl *Template, query string, f
 }
 
 func (tm *TemplateManager) createVersion(templateID, content, createdBy, changes string) {
+	tm.reloadMutex.Lock()
+	tm.createVersionLocked(templateID, content, createdBy, changes)
+	tm.reloadMutex.Unlock()
+
+	tm.saveVersions(templateID)
+}
+
+// createVersionLocked appends a new version entry for templateID to
+// tm.versions. Callers must already hold reloadMutex for writing.
+func (tm *TemplateManager) createVersionLocked(templateID, content, createdBy, changes string) {
 	if tm.versions[templateID] == nil {
 		tm.versions[templateID] = []TemplateVersion{}
 	}
@@ -1096,45 +2301,115 @@ func (tm *TemplateManager) createVersion(templateID, content, createdBy, changes
 	}
 
 	tm.versions[templateID] = append(tm.versions[templateID], version)
-	tm.saveVersions(templateID)
 }
 
 func (tm *TemplateManager) loadVersions() {
+	tm.versions = tm.loadVersionsFromDisk()
+}
+
+// loadVersionsFromDisk reads every version history file into a fresh map
+// without touching tm.versions, so callers can build a complete
+// replacement map before swapping it in (see Reload).
+func (tm *TemplateManager) loadVersionsFromDisk() map[string][]TemplateVersion {
+	versions := make(map[string][]TemplateVersion)
+
 	pattern := filepath.Join(tm.versionsPath, "*.json")
 	matches, err := filepath.Glob(pattern)
 	if err != nil {
 		logrus.Errorf("[TEMPLATES] Failed to load versions: %v", err)
-		return
+		return versions
 	}
 
 	for _, filePath := range matches {
 		templateID := strings.TrimSuffix(filepath.Base(filePath), ".json")
-		
+
 		data, err := os.ReadFile(filePath)
 		if err != nil {
 			logrus.Errorf("[TEMPLATES] Failed to read version file %s: %v", filePath, err)
 			continue
 		}
 
-		var versions []TemplateVersion
-		if err := json.Unmarshal(data, &versions); err != nil {
+		var fileVersions []TemplateVersion
+		if err := json.Unmarshal(data, &fileVersions); err != nil {
 			logrus.Errorf("[TEMPLATES] Failed to unmarshal version file %s: %v", filePath, err)
 			continue
 		}
 
-		tm.versions[templateID] = versions
+		versions[templateID] = fileVersions
+	}
+
+	return versions
+}
+
+// recoverTemplateFromVersions rebuilds a minimal template from the latest
+// entry in its version history when its own JSON file is corrupted. Only
+// content and version metadata survive in the version history, so fields
+// like Name and Description fall back to placeholders.
+func (tm *TemplateManager) recoverTemplateFromVersions(templateID string) (*Template, error) {
+	filePath := filepath.Join(tm.versionsPath, templateID+".json")
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("no version history available: %w", err)
+	}
+
+	var versions []TemplateVersion
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, fmt.Errorf("version history is also corrupted: %w", err)
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("version history is empty")
 	}
+
+	latest := versions[len(versions)-1]
+	now := time.Now()
+
+	return &Template{
+		ID:          templateID,
+		Name:        fmt.Sprintf("Recovered template %s", templateID),
+		Description: "Recovered from version history after the template file was corrupted",
+		Content:     latest.Content,
+		Category:    "general",
+		Language:    "en",
+		Version:     latest.Version,
+		IsActive:    true,
+		Variables:   tm.extractAdvancedVariables(latest.Content),
+		Tags:        []string{},
+		Metadata:    make(map[string]interface{}),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// Reload re-reads templates and their version history from disk and swaps
+// them into place atomically, so edits made directly to the template files
+// on disk (e.g. synced from git) take effect without restarting the
+// service. In-flight renders hold their own template reference and are
+// unaffected by the swap.
+func (tm *TemplateManager) Reload() error {
+	templates := tm.loadTemplatesFromDisk()
+	versions := tm.loadVersionsFromDisk()
+
+	tm.reloadMutex.Lock()
+	tm.templates = templates
+	tm.versions = versions
+	tm.reloadMutex.Unlock()
+
+	logrus.Infof("[TEMPLATES] Reloaded %d templates and version history from disk", len(templates))
+	return nil
 }
 
 func (tm *TemplateManager) saveVersions(templateID string) error {
 	filePath := filepath.Join(tm.versionsPath, templateID+".json")
-	
+
+	tm.reloadMutex.RLock()
 	data, err := json.MarshalIndent(tm.versions[templateID], "", "  ")
+	tm.reloadMutex.RUnlock()
 	if err != nil {
 		return err
 	}
-	
-	return os.WriteFile(filePath, data, 0644)
+
+	return writeFileAtomic(filePath, data)
 }
 
 func (tm *TemplateManager) createDefaultTemplates() {
@@ -1142,7 +2417,7 @@ func (tm *TemplateManager) createDefaultTemplates() {
 		{
 			Name:        "Welcome Message",
 			Description: "Advanced welcome message with personalization",
-			Content:     `Hello {{.Variables.name | default "there"}}! 🎉
+			Content: `Hello {{.Variables.name | default "there"}}! 🎉
 
 Welcome to our WhatsApp service. We're excited to have you with us!
 
@@ -1157,9 +2432,9 @@ Reply with the option number or just tell us what you need!
 
 Best regards,
 The {{.Variables.company | default "Support"}} Team`,
-			Category:    "greeting",
-			Language:    "en",
-			Tags:        []string{"welcome", "greeting", "onboarding"},
+			Category: "greeting",
+			Language: "en",
+			Tags:     []string{"welcome", "greeting", "onboarding"},
 			Variables: []Variable{
 				{Name: "name", Type: "text", Required: false, DefaultValue: "there", Description: "Customer's name"},
 				{Name: "company", Type: "text", Required: false, Description: "Company name"},
@@ -1168,7 +2443,7 @@ The {{.Variables.company | default "Support"}} Team`,
 		{
 			Name:        "Order Confirmation",
 			Description: "Professional order confirmation with details",
-			Content:     `🛍️ ORDER CONFIRMED
+			Content: `🛍️ ORDER CONFIRMED
 
 Hi {{.Variables.customer_name}},
 
@@ -1188,9 +2463,9 @@ Your order has been successfully confirmed!
 Questions? Reply to this message or call {{.Variables.support_phone}}.
 
 Thank you for your business! 🙏`,
-			Category:    "business",
-			Language:    "en",
-			Tags:        []string{"order", "confirmation", "ecommerce"},
+			Category: "business",
+			Language: "en",
+			Tags:     []string{"order", "confirmation", "ecommerce"},
 			Variables: []Variable{
 				{Name: "customer_name", Type: "text", Required: true, Description: "Customer's name"},
 				{Name: "order_id", Type: "text", Required: true, Description: "Order ID"},
@@ -1205,7 +2480,7 @@ Thank you for your business! 🙏`,
 		{
 			Name:        "Appointment Reminder",
 			Description: "Smart appointment reminder with confirmation",
-			Content:     `⏰ APPOINTMENT REMINDER
+			Content: `⏰ APPOINTMENT REMINDER
 
 Hi {{.Variables.name}},
 
@@ -1229,9 +2504,9 @@ Please reply with:
 Need directions? {{.Variables.maps_link}}
 
 See you soon! 😊`,
-			Category:    "reminder",
-			Language:    "en",
-			Tags:        []string{"appointment", "reminder", "healthcare", "booking"},
+			Category: "reminder",
+			Language: "en",
+			Tags:     []string{"appointment", "reminder", "healthcare", "booking"},
 			Variables: []Variable{
 				{Name: "name", Type: "text", Required: true, Description: "Patient/client name"},
 				{Name: "date", Type: "date", Required: true, Description: "Appointment date"},
@@ -1245,7 +2520,7 @@ See you soon! 😊`,
 		{
 			Name:        "Thank You Message",
 			Description: "Personalized thank you with follow-up",
-			Content:     `🙏 THANK YOU!
+			Content: `🙏 THANK YOU!
 
 Dear {{.Variables.name}},
 
@@ -1270,9 +2545,9 @@ We look forward to serving you again soon!
 
 Warm regards,
 The {{.Variables.company | default "Team"}} 💙`,
-			Category:    "greeting",
-			Language:    "en",
-			Tags:        []string{"thank-you", "appreciation", "follow-up", "loyalty"},
+			Category: "greeting",
+			Language: "en",
+			Tags:     []string{"thank-you", "appreciation", "follow-up", "loyalty"},
 			Variables: []Variable{
 				{Name: "name", Type: "text", Required: true, Description: "Customer's name"},
 				{Name: "company", Type: "text", Required: false, Description: "Company name"},
@@ -1289,9 +2564,9 @@ The {{.Variables.company | default "Team"}} 💙`,
 		tmpl.Version = "1.0.0"
 		tmpl.IsActive = true
 		tmpl.Metadata = make(map[string]interface{})
-		
+
 		if _, err := tm.CreateAdvancedTemplate(tmpl); err != nil {
 			logrus.Errorf("[TEMPLATES] Failed to create default template %s: %v", tmpl.Name, err)
 		}
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,37 @@
+package templates
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func BenchmarkRenderBatch(b *testing.B) {
+	tm := NewTemplateManager()
+
+	template, err := tm.CreateTemplate("bench", "benchmark template", "Hello {{.Variables.name}}, your order {{.Variables.order_id}} is ready.", "benchmark")
+	if err != nil {
+		b.Fatalf("failed to create template: %v", err)
+	}
+
+	const batchSize = 2000
+	items := make([]BatchRenderItem, batchSize)
+	for i := 0; i < batchSize; i++ {
+		items[i] = BatchRenderItem{
+			ID: template.ID,
+			Context: &RenderContext{
+				Variables: map[string]interface{}{
+					"name":     fmt.Sprintf("Customer %d", i),
+					"order_id": fmt.Sprintf("ORD-%d", i),
+				},
+				Timestamp: time.Now(),
+				Language:  "en",
+			},
+		}
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		tm.RenderBatch(items, DefaultBatchConcurrency)
+	}
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// E164PhoneRegex matches an E.164-formatted phone number: an optional
+// leading +, then 2-15 digits, the first of which is nonzero. It's shared
+// with the templates package's "phone" variable validation so both places
+// agree on what counts as a valid number.
+var E164PhoneRegex = regexp.MustCompile(`^\+?[1-9]\d{1,14}$`)
+
+// phoneFormattingReplacer strips characters commonly found in human-entered
+// phone numbers (spaces, dashes, parens, dots) that carry no information for
+// E.164 purposes.
+var phoneFormattingReplacer = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "")
+
+// NormalizePhone cleans up common phone number formatting - spaces, dashes,
+// parens, dots, and a leading "00" international prefix - and validates the
+// result against E164PhoneRegex. It returns an error describing why the
+// number was rejected instead of a value that would fail further downstream,
+// e.g. in a send handler or an outbound webhook.
+func NormalizePhone(raw string) (string, error) {
+	cleaned := phoneFormattingReplacer.Replace(strings.TrimSpace(raw))
+
+	if strings.HasPrefix(cleaned, "00") {
+		cleaned = "+" + strings.TrimPrefix(cleaned, "00")
+	}
+
+	if !E164PhoneRegex.MatchString(cleaned) {
+		return "", fmt.Errorf("invalid phone number %q: expected E.164 format, e.g. +15551234567", raw)
+	}
+
+	return cleaned, nil
+}
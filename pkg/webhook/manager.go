@@ -9,8 +9,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand"
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
+	textTemplate "text/template"
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
@@ -18,28 +23,142 @@ import (
 )
 
 type WebhookManager struct {
-	endpoints   map[string]*WebhookEndpoint
-	client      *http.Client
-	retryPolicy *RetryPolicy
-	mutex       sync.RWMutex
-	stats       *WebhookStats
+	endpoints         map[string]*WebhookEndpoint
+	client            *http.Client
+	retryPolicy       *RetryPolicy
+	mutex             sync.RWMutex
+	stats             *WebhookStats
+	deliverySemaphore chan struct{}
+
+	debounceMutex   sync.Mutex
+	debounceBuffers map[string]*debounceBuffer
+
+	// orderingMutex guards orderingLocks. orderingLocks holds one lock per
+	// (endpoint ID, partition value) pair seen so far for endpoints with
+	// OrderedDelivery enabled; it's never pruned, so a very large number of
+	// distinct partition values seen over the process lifetime does grow
+	// this map permanently. That's an accepted tradeoff for a small,
+	// per-mutex memory cost against the complexity of safely reclaiming
+	// locks that might be acquired again a moment later.
+	orderingMutex sync.Mutex
+	orderingLocks map[string]*sync.Mutex
+
+	// maxResponseBodyBytes caps how much of a delivery's response body
+	// attemptDelivery reads and stores, so an endpoint returning an
+	// enormous error page can't balloon memory or the persisted delivery
+	// record. Guarded by mutex like the other manager-wide settings.
+	maxResponseBodyBytes int64
+
+	// healthBacklogThreshold is how long a delivery may sit queued or in
+	// flight before GetHealth reports the pool unhealthy. Guarded by mutex
+	// like the other manager-wide settings.
+	healthBacklogThreshold time.Duration
+}
+
+// defaultHealthBacklogThreshold is the out-of-the-box backlog age GetHealth
+// tolerates before reporting the delivery pool unhealthy - long enough to
+// absorb a normal retry series, short enough to alert well before events
+// are lost to a stalled worker pool.
+const defaultHealthBacklogThreshold = 2 * time.Minute
+
+// defaultMaxResponseBodyBytes is the out-of-the-box cap on captured webhook
+// response bodies: enough to keep a JSON or HTML error body for debugging
+// without holding onto arbitrarily large payloads.
+const defaultMaxResponseBodyBytes = 8 * 1024
+
+// debounceBuffer accumulates events for one endpoint while its debounce
+// window is open. timer fires flushDebounceBufferLocked when the window
+// elapses; it's also flushed early once DebounceMaxEvents is reached.
+type debounceBuffer struct {
+	events []*WebhookEvent
+	timer  *time.Timer
 }
 
 type WebhookEndpoint struct {
-	ID          string            `json:"id"`
-	Name        string            `json:"name"`
-	URL         string            `json:"url"`
-	Secret      string            `json:"secret"`
-	Events      []string          `json:"events"`
-	Headers     map[string]string `json:"headers"`
-	Timeout     time.Duration     `json:"timeout"`
-	Enabled     bool              `json:"enabled"`
-	CreatedAt   time.Time         `json:"created_at"`
-	UpdatedAt   time.Time         `json:"updated_at"`
-	LastUsed    *time.Time        `json:"last_used,omitempty"`
-	SuccessRate float64           `json:"success_rate"`
-	TotalCalls  int64             `json:"total_calls"`
-	FailedCalls int64             `json:"failed_calls"`
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+	// Headers are sent with every delivery. A value containing a template
+	// expression (e.g. "{{.data.chat_id}}") is rendered against the event
+	// before sending - see renderEndpointHeaders. Values with no "{{" are
+	// sent as-is.
+	Headers               map[string]string `json:"headers"`
+	Timeout               time.Duration     `json:"timeout"`
+	ConnectTimeout        time.Duration     `json:"connect_timeout"`
+	TLSHandshakeTimeout   time.Duration     `json:"tls_handshake_timeout"`
+	ResponseHeaderTimeout time.Duration     `json:"response_header_timeout"`
+	Enabled               bool              `json:"enabled"`
+	CreatedAt             time.Time         `json:"created_at"`
+	UpdatedAt             time.Time         `json:"updated_at"`
+	LastUsed              *time.Time        `json:"last_used,omitempty"`
+	SuccessRate           float64           `json:"success_rate"`
+	TotalCalls            int64             `json:"total_calls"`
+	FailedCalls           int64             `json:"failed_calls"`
+
+	// PreviousSecret holds the secret RotateSecret just replaced, kept
+	// around for its overlap window so a receiver mid-rotation can still be
+	// verified against with VerifySignature. Cleared automatically once the
+	// overlap elapses.
+	PreviousSecret string `json:"previous_secret,omitempty"`
+
+	// MaxPayloadBytes caps the serialized event size sent to this endpoint;
+	// 0 (the default) means no limit. Endpoints that reject large bodies
+	// (e.g. with a 413) can set this instead of failing every media event.
+	MaxPayloadBytes int64 `json:"max_payload_bytes,omitempty"`
+	// OversizePolicy controls what happens when a payload exceeds
+	// MaxPayloadBytes: "truncate" (the default) replaces TruncateField with a
+	// placeholder and still delivers; "skip" fails the delivery immediately,
+	// without retries, with status "payload_too_large".
+	OversizePolicy string `json:"oversize_policy,omitempty"`
+	// TruncateField names a top-level key in the event's Data map to replace
+	// with a placeholder when OversizePolicy is "truncate" and the payload is
+	// too large, e.g. a base64 media body that could instead be referenced by
+	// URL out of band. Ignored when the key isn't present.
+	TruncateField string `json:"truncate_field,omitempty"`
+
+	// DebounceWindow batches events arriving within this duration and
+	// delivers them together as a single "webhook.batch" payload instead of
+	// one HTTP call per event. 0 (the default) delivers every event
+	// immediately, preserving existing behavior.
+	DebounceWindow time.Duration `json:"debounce_window,omitempty"`
+	// DebounceMaxEvents flushes the current batch early once it reaches
+	// this many events, so a long debounce window can't let a batch grow
+	// without bound. 0 means only DebounceWindow triggers a flush.
+	DebounceMaxEvents int `json:"debounce_max_events,omitempty"`
+
+	// OrderedDelivery serializes deliveries to this endpoint that share the
+	// same OrderingPartitionField value: a later event for a partition waits
+	// for the earlier one to finish (success, or exhaustion of retries)
+	// before its own attempt starts, so a stateful receiver never observes
+	// two events for the same partition out of order. Events in different
+	// partitions still deliver concurrently, and this only orders delivery
+	// - callers are still responsible for calling SendEvent for same-partition
+	// events in the order they should arrive.
+	//
+	// Latency tradeoff: a delivery that's retrying (backing off per
+	// RetryPolicy, up to MaxAttempts times) blocks every later event queued
+	// behind it in the same partition for the whole retry series, so a
+	// single unhealthy partition can build up a growing backlog even while
+	// every other partition keeps flowing normally. Enable this only for
+	// endpoints that genuinely can't tolerate reordering.
+	OrderedDelivery bool `json:"ordered_delivery,omitempty"`
+	// OrderingPartitionField is the dot path into the event's Data map used
+	// to group deliveries for OrderedDelivery, e.g. "chat_id". Events where
+	// the field is absent share a single "" partition rather than skipping
+	// ordering. Ignored unless OrderedDelivery is true.
+	OrderingPartitionField string `json:"ordering_partition_field,omitempty"`
+
+	// Shadow marks this endpoint as a copy target for validating a new
+	// receiver against real traffic before promoting it. Shadow endpoints
+	// still receive every matching event, but deliver at most once (no
+	// retries, so a slow or broken candidate receiver never blocks the
+	// caller) and their outcomes are excluded from wm.stats, so a shadow
+	// endpoint that's still misbehaving doesn't skew the production success
+	// rate. Their own TotalCalls/FailedCalls/SuccessRate are still tracked
+	// so the shadow can be evaluated on its own.
+	Shadow bool `json:"shadow,omitempty"`
 }
 
 type WebhookEvent struct {
@@ -52,21 +171,128 @@ type WebhookEvent struct {
 }
 
 type WebhookDelivery struct {
-	ID           string            `json:"id"`
-	EndpointID   string            `json:"endpoint_id"`
-	EventID      string            `json:"event_id"`
-	URL          string            `json:"url"`
-	Status       string            `json:"status"` // pending, success, failed, retrying
-	StatusCode   int               `json:"status_code"`
-	Response     string            `json:"response"`
-	Error        string            `json:"error,omitempty"`
-	Attempts     int               `json:"attempts"`
-	MaxAttempts  int               `json:"max_attempts"`
-	CreatedAt    time.Time         `json:"created_at"`
-	DeliveredAt  *time.Time        `json:"delivered_at,omitempty"`
-	NextRetryAt  *time.Time        `json:"next_retry_at,omitempty"`
-	Headers      map[string]string `json:"headers"`
-	Duration     time.Duration     `json:"duration"`
+	ID          string            `json:"id"`
+	EndpointID  string            `json:"endpoint_id"`
+	EventID     string            `json:"event_id"`
+	URL         string            `json:"url"`
+	Status      string            `json:"status"` // pending, success, failed, retrying
+	StatusCode  int               `json:"status_code"`
+	Response    string            `json:"response"`
+	Error       string            `json:"error,omitempty"`
+	Attempts    int               `json:"attempts"`
+	MaxAttempts int               `json:"max_attempts"`
+	CreatedAt   time.Time         `json:"created_at"`
+	DeliveredAt *time.Time        `json:"delivered_at,omitempty"`
+	NextRetryAt *time.Time        `json:"next_retry_at,omitempty"`
+	Headers     map[string]string `json:"headers"`
+	Duration    time.Duration     `json:"duration"`
+	// Shadow tags a delivery made to a Shadow endpoint, so callers inspecting
+	// delivery history can tell a validation copy apart from a production
+	// delivery.
+	Shadow bool `json:"shadow,omitempty"`
+	// Truncated is set when Response was cut short at maxResponseBodyBytes
+	// because the endpoint's response body exceeded the captured limit.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// lookupField walks a dot-separated path (e.g. "user.id") into a nested
+// map[string]interface{}, returning the leaf value if every segment resolves
+// to a map until the last one.
+func lookupField(data map[string]interface{}, path string) (interface{}, bool) {
+	if data == nil || path == "" {
+		return nil, false
+	}
+
+	segments := strings.Split(path, ".")
+	current := interface{}(data)
+
+	for _, segment := range segments {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		value, exists := m[segment]
+		if !exists {
+			return nil, false
+		}
+		current = value
+	}
+
+	return current, true
+}
+
+// renderEventTemplate renders raw as a text/template against event, exposing
+// the event as {{.data.<field>}} (a lookup into event.Data), {{.type}}, and
+// {{.id}} - e.g. "{{.data.chat_id}}" or "Bearer {{.data.token}}". A value
+// with no "{{" is returned unchanged without the template overhead, so plain
+// static headers and URLs pass through untouched.
+func renderEventTemplate(raw string, event *WebhookEvent) (string, error) {
+	if !strings.Contains(raw, "{{") {
+		return raw, nil
+	}
+
+	tmpl, err := textTemplate.New("webhook_template").Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, map[string]interface{}{
+		"data": event.Data,
+		"type": event.Type,
+		"id":   event.ID,
+	}); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return rendered.String(), nil
+}
+
+// renderEndpointURL renders rawURL against event via renderEventTemplate, so
+// one endpoint can route to a receiver that expects the instance or chat ID
+// in the URL path instead of the request body (e.g.
+// "https://api.example.com/hooks/{{.data.instance_id}}"). The rendered
+// result must still parse as a valid absolute URL - a template mistake or a
+// missing field turning it into garbage fails the delivery instead of
+// silently calling an unintended address.
+func renderEndpointURL(rawURL string, event *WebhookEvent) (string, error) {
+	renderedURL, err := renderEventTemplate(rawURL, event)
+	if err != nil {
+		return "", fmt.Errorf("failed to render URL template: %w", err)
+	}
+	if renderedURL == rawURL {
+		return renderedURL, nil
+	}
+
+	parsed, err := url.Parse(renderedURL)
+	if err != nil || !parsed.IsAbs() || parsed.Host == "" {
+		return "", fmt.Errorf("rendered URL %q is not a valid absolute URL", renderedURL)
+	}
+
+	return renderedURL, nil
+}
+
+// renderEndpointHeaders renders every header value in headers against event
+// via renderEventTemplate, so a header like
+// X-Chat-Id: {{.data.chat_id}} is filled in per delivery from the event
+// payload instead of requiring a proxy in front of the receiver to inject
+// it. Static values (no "{{") pass through unchanged. A rendering error on
+// any single header fails the whole delivery, matching renderEndpointURL.
+func renderEndpointHeaders(headers map[string]string, event *WebhookEvent) (map[string]string, error) {
+	if len(headers) == 0 {
+		return headers, nil
+	}
+
+	rendered := make(map[string]string, len(headers))
+	for key, value := range headers {
+		renderedValue, err := renderEventTemplate(value, event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render header %q: %w", key, err)
+		}
+		rendered[key] = renderedValue
+	}
+
+	return rendered, nil
 }
 
 type RetryPolicy struct {
@@ -74,19 +300,89 @@ type RetryPolicy struct {
 	BaseDelay   time.Duration `json:"base_delay"`
 	MaxDelay    time.Duration `json:"max_delay"`
 	Multiplier  float64       `json:"multiplier"`
+	// Jitter enables full jitter on the computed delay: the actual delay is
+	// randomized uniformly within [0, computed] instead of always being the
+	// exact computed value, so a batch of deliveries that failed at the same
+	// moment don't all retry in lockstep and hammer a recovering endpoint
+	// together.
+	Jitter bool `json:"jitter,omitempty"`
 }
 
 type WebhookStats struct {
-	TotalEndpoints   int                        `json:"total_endpoints"`
-	ActiveEndpoints  int                        `json:"active_endpoints"`
-	TotalDeliveries  int64                      `json:"total_deliveries"`
-	SuccessfulDeliveries int64                  `json:"successful_deliveries"`
-	FailedDeliveries int64                      `json:"failed_deliveries"`
-	AverageResponseTime time.Duration           `json:"average_response_time"`
-	DeliveriesByStatus map[string]int64         `json:"deliveries_by_status"`
-	DeliveriesByEvent  map[string]int64         `json:"deliveries_by_event"`
-	LastUpdated      time.Time                  `json:"last_updated"`
-	mutex            sync.RWMutex
+	TotalEndpoints       int              `json:"total_endpoints"`
+	ActiveEndpoints      int              `json:"active_endpoints"`
+	TotalDeliveries      int64            `json:"total_deliveries"`
+	SuccessfulDeliveries int64            `json:"successful_deliveries"`
+	FailedDeliveries     int64            `json:"failed_deliveries"`
+	AverageResponseTime  time.Duration    `json:"average_response_time"`
+	DeliveriesByStatus   map[string]int64 `json:"deliveries_by_status"`
+	DeliveriesByEvent    map[string]int64 `json:"deliveries_by_event"`
+	LastUpdated          time.Time        `json:"last_updated"`
+	ActiveDeliveries     int              `json:"active_deliveries"`
+	QueuedDeliveries     int              `json:"queued_deliveries"`
+	LastDeliveryAt       *time.Time       `json:"last_delivery_at,omitempty"`
+	// OldestPendingAt is the start time of the longest-waiting delivery that
+	// is queued for the semaphore or currently in flight, or nil when
+	// nothing is pending. Compared against a threshold by GetHealth to
+	// detect a stalled or saturated worker pool.
+	OldestPendingAt *time.Time `json:"oldest_pending_at,omitempty"`
+	mutex           sync.RWMutex
+
+	// pendingStarted holds the start time of every delivery currently queued
+	// or in flight, keyed by a per-dispatch id from nextPendingID, so
+	// OldestPendingAt can be derived without scanning deliveries themselves.
+	// Guarded by mutex like the other stats fields.
+	pendingStarted map[uint64]time.Time
+	nextPendingID  uint64
+}
+
+// beginPendingDelivery records the start of a delivery attempt for
+// backlog-age tracking and returns an id to pass to endPendingDelivery once
+// it finishes.
+func (s *WebhookStats) beginPendingDelivery() uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.nextPendingID++
+	id := s.nextPendingID
+	s.pendingStarted[id] = time.Now()
+	return id
+}
+
+// endPendingDelivery stops tracking id for backlog-age purposes.
+func (s *WebhookStats) endPendingDelivery(id uint64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.pendingStarted, id)
+}
+
+// oldestPendingAtLocked returns the start time of the longest-waiting
+// delivery still tracked, or nil if none are pending. The caller must
+// already hold mutex (for reading or writing).
+func (s *WebhookStats) oldestPendingAtLocked() *time.Time {
+	var oldest time.Time
+	for _, startedAt := range s.pendingStarted {
+		if oldest.IsZero() || startedAt.Before(oldest) {
+			oldest = startedAt
+		}
+	}
+	if oldest.IsZero() {
+		return nil
+	}
+	return &oldest
+}
+
+// WebhookHealth reports whether the webhook delivery pool is ready to accept
+// and process events.
+type WebhookHealth struct {
+	Healthy          bool       `json:"healthy"`
+	TotalEndpoints   int        `json:"total_endpoints"`
+	ActiveEndpoints  int        `json:"active_endpoints"`
+	ActiveDeliveries int        `json:"active_deliveries"`
+	QueuedDeliveries int        `json:"queued_deliveries"`
+	OldestPendingAt  *time.Time `json:"oldest_pending_at,omitempty"`
+	LastDeliveryAt   *time.Time `json:"last_delivery_at,omitempty"`
 }
 
 func NewWebhookManager() *WebhookManager {
@@ -100,12 +396,19 @@ func NewWebhookManager() *WebhookManager {
 			BaseDelay:   1 * time.Second,
 			MaxDelay:    60 * time.Second,
 			Multiplier:  2.0,
+			Jitter:      true,
 		},
 		stats: &WebhookStats{
 			DeliveriesByStatus: make(map[string]int64),
 			DeliveriesByEvent:  make(map[string]int64),
 			LastUpdated:        time.Now(),
+			pendingStarted:     make(map[uint64]time.Time),
 		},
+		deliverySemaphore:      make(chan struct{}, config.WebhookMaxConcurrentDeliveries),
+		debounceBuffers:        make(map[string]*debounceBuffer),
+		orderingLocks:          make(map[string]*sync.Mutex),
+		maxResponseBodyBytes:   defaultMaxResponseBodyBytes,
+		healthBacklogThreshold: defaultHealthBacklogThreshold,
 	}
 
 	// Load existing webhooks from config
@@ -117,16 +420,29 @@ func NewWebhookManager() *WebhookManager {
 
 // AddEndpoint adds a new webhook endpoint
 func (wm *WebhookManager) AddEndpoint(endpoint *WebhookEndpoint) error {
-	if endpoint.URL == "" {
-		return fmt.Errorf("webhook URL is required")
+	_, err := wm.AddEndpointWithValidation(endpoint, false)
+	return err
+}
+
+// applyEndpointDefaults fills in an endpoint's timeout, event, and header
+// fields with their defaults wherever the caller left them unset, shared
+// between AddEndpointWithValidation (new endpoint) and UpsertEndpoint's
+// new-endpoint path.
+func applyEndpointDefaults(endpoint *WebhookEndpoint) {
+	if endpoint.Timeout == 0 {
+		endpoint.Timeout = 30 * time.Second
 	}
 
-	if endpoint.ID == "" {
-		endpoint.ID = wm.generateEndpointID()
+	if endpoint.ConnectTimeout == 0 {
+		endpoint.ConnectTimeout = 5 * time.Second
 	}
 
-	if endpoint.Timeout == 0 {
-		endpoint.Timeout = 30 * time.Second
+	if endpoint.TLSHandshakeTimeout == 0 {
+		endpoint.TLSHandshakeTimeout = 5 * time.Second
+	}
+
+	if endpoint.ResponseHeaderTimeout == 0 {
+		endpoint.ResponseHeaderTimeout = 10 * time.Second
 	}
 
 	if endpoint.Events == nil {
@@ -136,18 +452,119 @@ func (wm *WebhookManager) AddEndpoint(endpoint *WebhookEndpoint) error {
 	if endpoint.Headers == nil {
 		endpoint.Headers = make(map[string]string)
 	}
+}
+
+// AddEndpointWithValidation is AddEndpoint with an optional dry-run
+// connection test. When validate is true, a test event is fired at the
+// proposed endpoint before it's persisted; the endpoint is only saved (and
+// starts receiving real events) if that test delivery succeeds. On failure
+// the endpoint is left unsaved and the failed test delivery is returned
+// alongside an error so the caller can inspect why. When validate is false
+// the endpoint is saved unconditionally, and the returned delivery is nil.
+func (wm *WebhookManager) AddEndpointWithValidation(endpoint *WebhookEndpoint, validate bool) (*WebhookDelivery, error) {
+	if endpoint.URL == "" {
+		return nil, fmt.Errorf("webhook URL is required")
+	}
+
+	if endpoint.ID == "" {
+		endpoint.ID = wm.generateEndpointID()
+	}
+
+	applyEndpointDefaults(endpoint)
 
 	endpoint.CreatedAt = time.Now()
 	endpoint.UpdatedAt = time.Now()
 	endpoint.Enabled = true
 
+	var delivery *WebhookDelivery
+	if validate {
+		delivery = wm.deliverEvent(endpoint, wm.buildTestEvent(endpoint.ID))
+		if delivery.Status != "success" {
+			return delivery, fmt.Errorf("endpoint validation failed: %s", delivery.Error)
+		}
+	}
+
 	wm.mutex.Lock()
 	wm.endpoints[endpoint.ID] = endpoint
 	wm.mutex.Unlock()
 
 	wm.updateStats()
 	logrus.Infof("[WEBHOOK] Added endpoint: %s (%s)", endpoint.Name, endpoint.URL)
-	return nil
+	return delivery, nil
+}
+
+// UpsertEndpoint declaratively creates or replaces a webhook endpoint, keyed
+// by a stable ID if provided, otherwise by a unique Name. This makes
+// endpoint provisioning idempotent: re-applying the same declaration updates
+// the existing endpoint in place instead of accumulating duplicates the way
+// AddEndpoint's timestamp-based IDs would.
+func (wm *WebhookManager) UpsertEndpoint(endpoint *WebhookEndpoint) (*WebhookEndpoint, error) {
+	if endpoint.URL == "" {
+		return nil, fmt.Errorf("webhook URL is required")
+	}
+
+	// The lookup and the insert/replace must happen under the same write
+	// lock. Releasing it in between (e.g. to delegate to AddEndpoint once
+	// existing is found to be nil) would let two concurrent upserts for the
+	// same new name/ID both observe no match and both create an endpoint.
+	wm.mutex.Lock()
+	var existing *WebhookEndpoint
+	if endpoint.ID != "" {
+		existing = wm.endpoints[endpoint.ID]
+	}
+	if existing == nil && endpoint.Name != "" {
+		for _, ep := range wm.endpoints {
+			if ep.Name == endpoint.Name {
+				existing = ep
+				break
+			}
+		}
+	}
+
+	if existing == nil {
+		if endpoint.ID == "" {
+			endpoint.ID = wm.generateEndpointID()
+		}
+		applyEndpointDefaults(endpoint)
+		endpoint.CreatedAt = time.Now()
+		endpoint.UpdatedAt = time.Now()
+		endpoint.Enabled = true
+	} else {
+		endpoint.ID = existing.ID
+		endpoint.CreatedAt = existing.CreatedAt
+		endpoint.UpdatedAt = time.Now()
+		endpoint.Enabled = true
+		if endpoint.Timeout == 0 {
+			endpoint.Timeout = existing.Timeout
+		}
+		if endpoint.ConnectTimeout == 0 {
+			endpoint.ConnectTimeout = existing.ConnectTimeout
+		}
+		if endpoint.TLSHandshakeTimeout == 0 {
+			endpoint.TLSHandshakeTimeout = existing.TLSHandshakeTimeout
+		}
+		if endpoint.ResponseHeaderTimeout == 0 {
+			endpoint.ResponseHeaderTimeout = existing.ResponseHeaderTimeout
+		}
+		if endpoint.Events == nil {
+			endpoint.Events = existing.Events
+		}
+		if endpoint.Headers == nil {
+			endpoint.Headers = existing.Headers
+		}
+		// Preserve delivery history - a re-declared endpoint isn't a fresh one.
+		endpoint.SuccessRate = existing.SuccessRate
+		endpoint.TotalCalls = existing.TotalCalls
+		endpoint.FailedCalls = existing.FailedCalls
+		endpoint.LastUsed = existing.LastUsed
+	}
+
+	wm.endpoints[endpoint.ID] = endpoint
+	wm.mutex.Unlock()
+
+	wm.updateStats()
+	logrus.Infof("[WEBHOOK] Upserted endpoint: %s (%s)", endpoint.Name, endpoint.URL)
+	return endpoint, nil
 }
 
 // UpdateEndpoint updates an existing webhook endpoint
@@ -179,6 +596,30 @@ func (wm *WebhookManager) UpdateEndpoint(id string, updates map[string]interface
 	if enabled, ok := updates["enabled"].(bool); ok {
 		endpoint.Enabled = enabled
 	}
+	if maxPayloadBytes, ok := updates["max_payload_bytes"].(float64); ok {
+		endpoint.MaxPayloadBytes = int64(maxPayloadBytes)
+	}
+	if oversizePolicy, ok := updates["oversize_policy"].(string); ok {
+		endpoint.OversizePolicy = oversizePolicy
+	}
+	if truncateField, ok := updates["truncate_field"].(string); ok {
+		endpoint.TruncateField = truncateField
+	}
+	if debounceWindowMs, ok := updates["debounce_window_ms"].(float64); ok {
+		endpoint.DebounceWindow = time.Duration(debounceWindowMs) * time.Millisecond
+	}
+	if debounceMaxEvents, ok := updates["debounce_max_events"].(float64); ok {
+		endpoint.DebounceMaxEvents = int(debounceMaxEvents)
+	}
+	if orderedDelivery, ok := updates["ordered_delivery"].(bool); ok {
+		endpoint.OrderedDelivery = orderedDelivery
+	}
+	if orderingPartitionField, ok := updates["ordering_partition_field"].(string); ok {
+		endpoint.OrderingPartitionField = orderingPartitionField
+	}
+	if shadow, ok := updates["shadow"].(bool); ok {
+		endpoint.Shadow = shadow
+	}
 
 	endpoint.UpdatedAt = time.Now()
 	wm.updateStats()
@@ -187,6 +628,50 @@ func (wm *WebhookManager) UpdateEndpoint(id string, updates map[string]interface
 	return nil
 }
 
+// RotateSecret replaces endpoint's signing secret with newSecret. Outbound
+// deliveries switch to signing with newSecret immediately, but oldSecret is
+// kept as PreviousSecret for overlap, so a receiver that hasn't finished
+// deploying its own new secret yet can still verify deliveries against it
+// (via VerifySignature) until overlap elapses, at which point it's dropped.
+// A zero overlap drops the old secret immediately - a hard cutover.
+func (wm *WebhookManager) RotateSecret(id string, newSecret string, overlap time.Duration) error {
+	if newSecret == "" {
+		return fmt.Errorf("new secret is required")
+	}
+
+	wm.mutex.Lock()
+	endpoint, exists := wm.endpoints[id]
+	if !exists {
+		wm.mutex.Unlock()
+		return fmt.Errorf("webhook endpoint not found: %s", id)
+	}
+
+	oldSecret := endpoint.Secret
+	endpoint.Secret = newSecret
+	if overlap > 0 && oldSecret != "" {
+		endpoint.PreviousSecret = oldSecret
+	} else {
+		endpoint.PreviousSecret = ""
+	}
+	endpoint.UpdatedAt = time.Now()
+	wm.mutex.Unlock()
+
+	logrus.Infof("[WEBHOOK] Rotated secret for endpoint %s (overlap: %s)", id, overlap)
+
+	if overlap > 0 && oldSecret != "" {
+		time.AfterFunc(overlap, func() {
+			wm.mutex.Lock()
+			defer wm.mutex.Unlock()
+			if ep, ok := wm.endpoints[id]; ok && ep.PreviousSecret == oldSecret {
+				ep.PreviousSecret = ""
+				logrus.Infof("[WEBHOOK] Overlap window elapsed, dropped previous secret for endpoint %s", id)
+			}
+		})
+	}
+
+	return nil
+}
+
 // RemoveEndpoint removes a webhook endpoint
 func (wm *WebhookManager) RemoveEndpoint(id string) error {
 	wm.mutex.Lock()
@@ -247,13 +732,22 @@ func (wm *WebhookManager) SendEvent(event *WebhookEvent) error {
 		return nil
 	}
 
-	// Send to all matching endpoints concurrently
+	// Send to all matching endpoints concurrently, bounded by the global
+	// delivery semaphore so an event storm across many endpoints can't
+	// spawn unbounded concurrent HTTP requests and exhaust file descriptors.
+	// Endpoints with a debounce window configured are buffered instead of
+	// dispatched immediately.
 	var wg sync.WaitGroup
 	for _, endpoint := range matchingEndpoints {
+		if endpoint.DebounceWindow > 0 {
+			wm.enqueueDebounced(endpoint, event)
+			continue
+		}
+
 		wg.Add(1)
 		go func(ep *WebhookEndpoint) {
 			defer wg.Done()
-			wm.deliverEvent(ep, event)
+			wm.dispatchToEndpoint(ep, event)
 		}(endpoint)
 	}
 
@@ -262,6 +756,127 @@ func (wm *WebhookManager) SendEvent(event *WebhookEvent) error {
 	return nil
 }
 
+// dispatchToEndpoint delivers event to endpoint, tracking queued/active
+// delivery stats around the shared delivery semaphore. Shared by both the
+// immediate delivery path and debounced batch flushes.
+func (wm *WebhookManager) dispatchToEndpoint(endpoint *WebhookEndpoint, event *WebhookEvent) {
+	pendingID := wm.stats.beginPendingDelivery()
+	defer wm.stats.endPendingDelivery(pendingID)
+
+	wm.stats.mutex.Lock()
+	wm.stats.QueuedDeliveries++
+	wm.stats.mutex.Unlock()
+
+	wm.mutex.RLock()
+	sem := wm.deliverySemaphore
+	wm.mutex.RUnlock()
+	sem <- struct{}{}
+	defer func() { <-sem }()
+
+	wm.stats.mutex.Lock()
+	wm.stats.QueuedDeliveries--
+	wm.stats.ActiveDeliveries++
+	wm.stats.mutex.Unlock()
+
+	if endpoint.OrderedDelivery {
+		lock := wm.partitionLock(endpoint, event)
+		lock.Lock()
+		defer lock.Unlock()
+	}
+
+	wm.deliverEvent(endpoint, event)
+
+	wm.stats.mutex.Lock()
+	wm.stats.ActiveDeliveries--
+	now := time.Now()
+	wm.stats.LastDeliveryAt = &now
+	wm.stats.mutex.Unlock()
+}
+
+// partitionLock returns the serialization lock for endpoint's ordering
+// partition of event (see WebhookEndpoint.OrderedDelivery), creating it on
+// first use.
+func (wm *WebhookManager) partitionLock(endpoint *WebhookEndpoint, event *WebhookEvent) *sync.Mutex {
+	partitionValue := ""
+	if v, ok := lookupField(event.Data, endpoint.OrderingPartitionField); ok {
+		partitionValue = fmt.Sprintf("%v", v)
+	}
+	key := endpoint.ID + "|" + partitionValue
+
+	wm.orderingMutex.Lock()
+	defer wm.orderingMutex.Unlock()
+	lock, exists := wm.orderingLocks[key]
+	if !exists {
+		lock = &sync.Mutex{}
+		wm.orderingLocks[key] = lock
+	}
+	return lock
+}
+
+// enqueueDebounced buffers event for endpoint, starting its debounce timer
+// on the first buffered event and flushing early once DebounceMaxEvents is
+// reached.
+func (wm *WebhookManager) enqueueDebounced(endpoint *WebhookEndpoint, event *WebhookEvent) {
+	wm.debounceMutex.Lock()
+	defer wm.debounceMutex.Unlock()
+
+	buf, exists := wm.debounceBuffers[endpoint.ID]
+	if !exists {
+		buf = &debounceBuffer{}
+		wm.debounceBuffers[endpoint.ID] = buf
+	}
+	buf.events = append(buf.events, event)
+
+	if endpoint.DebounceMaxEvents > 0 && len(buf.events) >= endpoint.DebounceMaxEvents {
+		wm.flushDebounceBufferLocked(endpoint)
+		return
+	}
+
+	if buf.timer == nil {
+		buf.timer = time.AfterFunc(endpoint.DebounceWindow, func() {
+			wm.debounceMutex.Lock()
+			defer wm.debounceMutex.Unlock()
+			wm.flushDebounceBufferLocked(endpoint)
+		})
+	}
+}
+
+// flushDebounceBufferLocked delivers endpoint's buffered events as a single
+// batch and clears its buffer. Callers must hold debounceMutex.
+func (wm *WebhookManager) flushDebounceBufferLocked(endpoint *WebhookEndpoint) {
+	buf, exists := wm.debounceBuffers[endpoint.ID]
+	if !exists || len(buf.events) == 0 {
+		return
+	}
+
+	buf.timer.Stop()
+	events := buf.events
+	delete(wm.debounceBuffers, endpoint.ID)
+
+	go wm.deliverBatch(endpoint, events)
+}
+
+// deliverBatch wraps buffered events into a single synthetic "webhook.batch"
+// event and delivers it like any other event, so debounced endpoints get
+// the same retry, stats, and signature handling as immediate deliveries -
+// the whole batch is signed as one payload rather than each event
+// separately.
+func (wm *WebhookManager) deliverBatch(endpoint *WebhookEndpoint, events []*WebhookEvent) {
+	batchEvent := &WebhookEvent{
+		ID:        wm.generateEventID(),
+		Type:      "webhook.batch",
+		Timestamp: time.Now(),
+		Source:    "webhook_manager",
+		Data: map[string]interface{}{
+			"count":  len(events),
+			"events": events,
+		},
+	}
+
+	wm.dispatchToEndpoint(endpoint, batchEvent)
+	logrus.Debugf("[WEBHOOK] Delivered debounced batch of %d events to endpoint %s", len(events), endpoint.ID)
+}
+
 // TestEndpoint tests a webhook endpoint with a sample event
 func (wm *WebhookManager) TestEndpoint(id string) (*WebhookDelivery, error) {
 	endpoint, err := wm.GetEndpoint(id)
@@ -269,8 +884,13 @@ func (wm *WebhookManager) TestEndpoint(id string) (*WebhookDelivery, error) {
 		return nil, err
 	}
 
-	// Create test event
-	testEvent := &WebhookEvent{
+	return wm.deliverEvent(endpoint, wm.buildTestEvent(id)), nil
+}
+
+// buildTestEvent creates the sample event fired at an endpoint by
+// TestEndpoint, TestAllEndpoints and AddEndpointWithValidation's dry run.
+func (wm *WebhookManager) buildTestEvent(endpointID string) *WebhookEvent {
+	return &WebhookEvent{
 		ID:   "test_" + wm.generateEventID(),
 		Type: "webhook.test",
 		Data: map[string]interface{}{
@@ -280,11 +900,151 @@ func (wm *WebhookManager) TestEndpoint(id string) (*WebhookDelivery, error) {
 		Timestamp: time.Now(),
 		Source:    "webhook_manager",
 		Metadata: map[string]interface{}{
-			"endpoint_id": id,
+			"endpoint_id": endpointID,
 		},
 	}
+}
+
+// TestAllEndpoints fires the sample test event at every enabled endpoint
+// concurrently and returns each endpoint's delivery result keyed by
+// endpoint ID, so operators can verify a config change in one call instead
+// of testing endpoints one at a time.
+func (wm *WebhookManager) TestAllEndpoints() map[string]*WebhookDelivery {
+	wm.mutex.RLock()
+	endpoints := make([]*WebhookEndpoint, 0, len(wm.endpoints))
+	for _, endpoint := range wm.endpoints {
+		if endpoint.Enabled {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	wm.mutex.RUnlock()
+
+	results := make(map[string]*WebhookDelivery, len(endpoints))
+	var resultsMutex sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, endpoint := range endpoints {
+		wg.Add(1)
+		go func(ep *WebhookEndpoint) {
+			defer wg.Done()
+
+			delivery, err := wm.TestEndpoint(ep.ID)
+			if err != nil {
+				delivery = &WebhookDelivery{
+					EndpointID: ep.ID,
+					URL:        ep.URL,
+					Status:     "failed",
+					Error:      err.Error(),
+				}
+			}
+
+			resultsMutex.Lock()
+			results[ep.ID] = delivery
+			resultsMutex.Unlock()
+		}(endpoint)
+	}
 
-	return wm.deliverEvent(endpoint, testEvent), nil
+	wg.Wait()
+	logrus.Infof("[WEBHOOK] Tested %d enabled endpoints", len(results))
+	return results
+}
+
+// DebugResult captures the exact HTTP exchange performed by DebugDeliver: the
+// request as sent (including the computed signature header) and the raw
+// response received, with timing. It carries no relation to the endpoint's
+// stats or the normal delivery/retry bookkeeping.
+type DebugResult struct {
+	EndpointID      string            `json:"endpoint_id"`
+	URL             string            `json:"url"`
+	RequestHeaders  map[string]string `json:"request_headers"`
+	RequestBody     string            `json:"request_body"`
+	StatusCode      int               `json:"status_code"`
+	ResponseHeaders map[string]string `json:"response_headers"`
+	ResponseBody    string            `json:"response_body"`
+	Error           string            `json:"error,omitempty"`
+	Duration        time.Duration     `json:"duration"`
+}
+
+// DebugDeliver sends event to endpointID synchronously exactly once,
+// bypassing the retry policy, and returns the full request/response exchange
+// for inspection. Unlike deliverEvent, it does not touch endpoint or global
+// delivery stats - it exists purely to debug a failing integration.
+func (wm *WebhookManager) DebugDeliver(endpointID string, event *WebhookEvent) (*DebugResult, error) {
+	endpoint, err := wm.GetEndpoint(endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	renderedURL, err := renderEndpointURL(endpoint.URL, event)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), endpoint.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", renderedURL, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", fmt.Sprintf("WhatsApp-Webhook/%s", config.AppVersion))
+
+	renderedHeaders, err := renderEndpointHeaders(endpoint.Headers, event)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range renderedHeaders {
+		req.Header.Set(key, value)
+	}
+
+	if endpoint.Secret != "" {
+		req.Header.Set("X-Webhook-Signature", wm.generateSignature(payload, endpoint.Secret))
+	}
+
+	result := &DebugResult{
+		EndpointID:     endpointID,
+		URL:            renderedURL,
+		RequestHeaders: flattenHeaders(req.Header),
+		RequestBody:    string(payload),
+	}
+
+	startTime := time.Now()
+	resp, err := wm.clientForEndpoint(endpoint).Do(req)
+	result.Duration = time.Since(startTime)
+	if err != nil {
+		result.Error = fmt.Sprintf("request failed: %v", err)
+		return result, nil
+	}
+	defer resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	result.ResponseHeaders = flattenHeaders(resp.Header)
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		result.ResponseBody = "failed to read response"
+	} else {
+		result.ResponseBody = string(responseBody)
+	}
+
+	return result, nil
+}
+
+// flattenHeaders collapses an http.Header's possibly multi-valued entries
+// into a single comma-joined string per key so the result serializes cleanly.
+func flattenHeaders(header http.Header) map[string]string {
+	flat := make(map[string]string, len(header))
+	for key, values := range header {
+		flat[key] = strings.Join(values, ", ")
+	}
+	return flat
 }
 
 // GetStats returns webhook statistics
@@ -303,6 +1063,10 @@ func (wm *WebhookManager) GetStats() *WebhookStats {
 		DeliveriesByStatus:   make(map[string]int64),
 		DeliveriesByEvent:    make(map[string]int64),
 		LastUpdated:          wm.stats.LastUpdated,
+		ActiveDeliveries:     wm.stats.ActiveDeliveries,
+		QueuedDeliveries:     wm.stats.QueuedDeliveries,
+		LastDeliveryAt:       wm.stats.LastDeliveryAt,
+		OldestPendingAt:      wm.stats.oldestPendingAtLocked(),
 	}
 
 	for k, v := range wm.stats.DeliveriesByStatus {
@@ -315,6 +1079,91 @@ func (wm *WebhookManager) GetStats() *WebhookStats {
 	return stats
 }
 
+// SetMaxConcurrentDeliveries reconfigures the global cap on in-flight
+// webhook deliveries. Deliveries already queued on the old semaphore drain
+// against it normally; only newly dispatched events observe the new limit.
+func (wm *WebhookManager) SetMaxConcurrentDeliveries(max int) error {
+	if max <= 0 {
+		return fmt.Errorf("max concurrent deliveries must be positive")
+	}
+
+	wm.mutex.Lock()
+	wm.deliverySemaphore = make(chan struct{}, max)
+	wm.mutex.Unlock()
+
+	logrus.Infof("[WEBHOOK] Max concurrent deliveries set to %d", max)
+	return nil
+}
+
+// SetMaxResponseBodySize reconfigures how many bytes of a delivery response
+// body attemptDelivery captures. Only deliveries started after this call
+// observe the new limit.
+func (wm *WebhookManager) SetMaxResponseBodySize(maxBytes int64) error {
+	if maxBytes <= 0 {
+		return fmt.Errorf("max response body size must be positive")
+	}
+
+	wm.mutex.Lock()
+	wm.maxResponseBodyBytes = maxBytes
+	wm.mutex.Unlock()
+
+	logrus.Infof("[WEBHOOK] Max captured response body size set to %d bytes", maxBytes)
+	return nil
+}
+
+// GetHealth reports whether the webhook manager is ready to deliver events.
+// It is unhealthy when the oldest delivery still queued or in flight has
+// been pending longer than healthBacklogThreshold, which catches a stalled
+// or saturated worker pool - a plain "at least one active endpoint" check
+// would stay healthy even while every delivery is backing up behind a dead
+// receiver.
+func (wm *WebhookManager) GetHealth() *WebhookHealth {
+	wm.mutex.RLock()
+	totalEndpoints := len(wm.endpoints)
+	activeEndpoints := 0
+	for _, endpoint := range wm.endpoints {
+		if endpoint.Enabled {
+			activeEndpoints++
+		}
+	}
+	threshold := wm.healthBacklogThreshold
+	wm.mutex.RUnlock()
+
+	wm.stats.mutex.RLock()
+	activeDeliveries := wm.stats.ActiveDeliveries
+	queuedDeliveries := wm.stats.QueuedDeliveries
+	lastDeliveryAt := wm.stats.LastDeliveryAt
+	oldestPendingAt := wm.stats.oldestPendingAtLocked()
+	wm.stats.mutex.RUnlock()
+
+	healthy := oldestPendingAt == nil || time.Since(*oldestPendingAt) <= threshold
+
+	return &WebhookHealth{
+		Healthy:          healthy,
+		TotalEndpoints:   totalEndpoints,
+		ActiveEndpoints:  activeEndpoints,
+		ActiveDeliveries: activeDeliveries,
+		QueuedDeliveries: queuedDeliveries,
+		OldestPendingAt:  oldestPendingAt,
+		LastDeliveryAt:   lastDeliveryAt,
+	}
+}
+
+// SetHealthBacklogThreshold reconfigures how long a delivery may sit queued
+// or in flight before GetHealth reports the pool unhealthy.
+func (wm *WebhookManager) SetHealthBacklogThreshold(threshold time.Duration) error {
+	if threshold <= 0 {
+		return fmt.Errorf("health backlog threshold must be positive")
+	}
+
+	wm.mutex.Lock()
+	wm.healthBacklogThreshold = threshold
+	wm.mutex.Unlock()
+
+	logrus.Infof("[WEBHOOK] Health backlog threshold set to %s", threshold)
+	return nil
+}
+
 // Private methods
 
 func (wm *WebhookManager) loadConfiguredWebhooks() {
@@ -355,6 +1204,13 @@ func (wm *WebhookManager) getMatchingEndpoints(eventType string) []*WebhookEndpo
 }
 
 func (wm *WebhookManager) deliverEvent(endpoint *WebhookEndpoint, event *WebhookEvent) *WebhookDelivery {
+	maxAttempts := wm.retryPolicy.MaxAttempts
+	if endpoint.Shadow {
+		// Shadow endpoints never block the caller waiting on retries/backoff
+		// for a candidate receiver that's still being validated.
+		maxAttempts = 1
+	}
+
 	delivery := &WebhookDelivery{
 		ID:          wm.generateDeliveryID(),
 		EndpointID:  endpoint.ID,
@@ -362,9 +1218,32 @@ func (wm *WebhookManager) deliverEvent(endpoint *WebhookEndpoint, event *Webhook
 		URL:         endpoint.URL,
 		Status:      "pending",
 		Attempts:    0,
-		MaxAttempts: wm.retryPolicy.MaxAttempts,
+		MaxAttempts: maxAttempts,
 		CreatedAt:   time.Now(),
 		Headers:     make(map[string]string),
+		Shadow:      endpoint.Shadow,
+	}
+
+	renderedURL, err := renderEndpointURL(endpoint.URL, event)
+	if err != nil {
+		delivery.Status = "failed"
+		delivery.Error = err.Error()
+		logrus.Errorf("[WEBHOOK] Failed to render URL template for endpoint %s: %v", endpoint.ID, err)
+		wm.updateEndpointStats(endpoint, delivery)
+		if !endpoint.Shadow {
+			wm.updateGlobalStats(delivery)
+		}
+		return delivery
+	}
+	delivery.URL = renderedURL
+
+	deliverableEvent, skip := wm.applyPayloadPolicy(endpoint, event, delivery)
+	if skip {
+		wm.updateEndpointStats(endpoint, delivery)
+		if !endpoint.Shadow {
+			wm.updateGlobalStats(delivery)
+		}
+		return delivery
 	}
 
 	// Attempt delivery with retries
@@ -372,7 +1251,7 @@ func (wm *WebhookManager) deliverEvent(endpoint *WebhookEndpoint, event *Webhook
 		delivery.Attempts++
 		startTime := time.Now()
 
-		success := wm.attemptDelivery(endpoint, event, delivery)
+		success := wm.attemptDelivery(endpoint, deliverableEvent, delivery)
 		delivery.Duration = time.Since(startTime)
 
 		if success {
@@ -389,25 +1268,81 @@ func (wm *WebhookManager) deliverEvent(endpoint *WebhookEndpoint, event *Webhook
 			nextRetry := time.Now().Add(delay)
 			delivery.NextRetryAt = &nextRetry
 			delivery.Status = "retrying"
-			
-			logrus.Warnf("[WEBHOOK] Delivery failed, retrying in %v (attempt %d/%d): %s", 
+
+			logrus.Warnf("[WEBHOOK] Delivery failed, retrying in %v (attempt %d/%d): %s",
 				delay, delivery.Attempts, delivery.MaxAttempts, delivery.Error)
-			
+
 			time.Sleep(delay)
 		} else {
 			delivery.Status = "failed"
-			logrus.Errorf("[WEBHOOK] Delivery failed permanently after %d attempts: %s", 
-				delivery.Attempts, delivery.Error)
+			if endpoint.Shadow {
+				logrus.Warnf("[WEBHOOK] Shadow delivery to %s failed: %s", endpoint.URL, delivery.Error)
+			} else {
+				logrus.Errorf("[WEBHOOK] Delivery failed permanently after %d attempts: %s",
+					delivery.Attempts, delivery.Error)
+			}
 		}
 	}
 
-	// Update endpoint stats
+	// Update endpoint stats. Shadow failures are excluded from the global
+	// stats so a candidate receiver that's still misbehaving doesn't skew the
+	// production success rate the rest of the system relies on.
 	wm.updateEndpointStats(endpoint, delivery)
-	wm.updateGlobalStats(delivery)
+	if !endpoint.Shadow {
+		wm.updateGlobalStats(delivery)
+	}
 
 	return delivery
 }
 
+// applyPayloadPolicy checks event against endpoint.MaxPayloadBytes and, if
+// it's exceeded, applies OversizePolicy. It returns the event to actually
+// deliver (unchanged, or with TruncateField replaced) and whether delivery
+// should be skipped entirely. delivery is updated in place when skipped.
+func (wm *WebhookManager) applyPayloadPolicy(endpoint *WebhookEndpoint, event *WebhookEvent, delivery *WebhookDelivery) (*WebhookEvent, bool) {
+	if endpoint.MaxPayloadBytes <= 0 {
+		return event, false
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil || int64(len(payload)) <= endpoint.MaxPayloadBytes {
+		return event, false
+	}
+
+	if endpoint.OversizePolicy == "skip" {
+		delivery.Status = "payload_too_large"
+		delivery.Error = fmt.Sprintf("payload of %d bytes exceeds max_payload_bytes %d", len(payload), endpoint.MaxPayloadBytes)
+		logrus.Warnf("[WEBHOOK] Skipping delivery to %s: %s", endpoint.URL, delivery.Error)
+		return event, true
+	}
+
+	logrus.Warnf("[WEBHOOK] Payload of %d bytes exceeds max_payload_bytes %d for %s, truncating field %q",
+		len(payload), endpoint.MaxPayloadBytes, endpoint.URL, endpoint.TruncateField)
+	return truncateEventField(event, endpoint.TruncateField), false
+}
+
+// truncateEventField returns a shallow copy of event with field replaced by a
+// placeholder in Data, leaving the original event (which may be shared across
+// concurrent deliveries to other endpoints) untouched. It's a no-op if field
+// is empty or not present in Data.
+func truncateEventField(event *WebhookEvent, field string) *WebhookEvent {
+	if field == "" {
+		return event
+	}
+	if _, ok := event.Data[field]; !ok {
+		return event
+	}
+
+	truncated := *event
+	truncated.Data = make(map[string]interface{}, len(event.Data))
+	for k, v := range event.Data {
+		truncated.Data[k] = v
+	}
+	truncated.Data[field] = "[truncated: payload exceeded max_payload_bytes]"
+
+	return &truncated
+}
+
 func (wm *WebhookManager) attemptDelivery(endpoint *WebhookEndpoint, event *WebhookEvent, delivery *WebhookDelivery) bool {
 	// Prepare payload
 	payload, err := json.Marshal(event)
@@ -420,7 +1355,7 @@ func (wm *WebhookManager) attemptDelivery(endpoint *WebhookEndpoint, event *Webh
 	ctx, cancel := context.WithTimeout(context.Background(), endpoint.Timeout)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, "POST", endpoint.URL, bytes.NewBuffer(payload))
+	req, err := http.NewRequestWithContext(ctx, "POST", delivery.URL, bytes.NewBuffer(payload))
 	if err != nil {
 		delivery.Error = fmt.Sprintf("failed to create request: %v", err)
 		return false
@@ -429,8 +1364,13 @@ func (wm *WebhookManager) attemptDelivery(endpoint *WebhookEndpoint, event *Webh
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", fmt.Sprintf("WhatsApp-Webhook/%s", config.AppVersion))
-	
-	for key, value := range endpoint.Headers {
+
+	renderedHeaders, err := renderEndpointHeaders(endpoint.Headers, event)
+	if err != nil {
+		delivery.Error = fmt.Sprintf("failed to render headers: %v", err)
+		return false
+	}
+	for key, value := range renderedHeaders {
 		req.Header.Set(key, value)
 		delivery.Headers[key] = value
 	}
@@ -442,8 +1382,8 @@ func (wm *WebhookManager) attemptDelivery(endpoint *WebhookEndpoint, event *Webh
 		delivery.Headers["X-Webhook-Signature"] = signature
 	}
 
-	// Send request
-	resp, err := wm.client.Do(req)
+	// Send request using a client scoped to this endpoint's timeouts
+	resp, err := wm.clientForEndpoint(endpoint).Do(req)
 	if err != nil {
 		delivery.Error = fmt.Sprintf("request failed: %v", err)
 		return false
@@ -452,12 +1392,21 @@ func (wm *WebhookManager) attemptDelivery(endpoint *WebhookEndpoint, event *Webh
 
 	delivery.StatusCode = resp.StatusCode
 
-	// Read response
-	responseBody, err := io.ReadAll(resp.Body)
+	// Read response, capped so a misbehaving endpoint returning an enormous
+	// body can't balloon memory or the persisted delivery record.
+	wm.mutex.RLock()
+	maxBodyBytes := wm.maxResponseBodyBytes
+	wm.mutex.RUnlock()
+
+	responseBody, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
 	if err != nil {
 		delivery.Response = "failed to read response"
 	} else {
 		delivery.Response = string(responseBody)
+		if int64(len(responseBody)) >= maxBodyBytes {
+			delivery.Truncated = true
+			delivery.Response += fmt.Sprintf("... (truncated at %d bytes)", maxBodyBytes)
+		}
 	}
 
 	// Check if delivery was successful
@@ -469,26 +1418,82 @@ func (wm *WebhookManager) attemptDelivery(endpoint *WebhookEndpoint, event *Webh
 	return false
 }
 
+// clientForEndpoint builds an HTTP client whose dial, TLS handshake, and
+// response-header timeouts reflect the endpoint's own configuration, falling
+// back to the manager's default client when no per-endpoint overrides are
+// set. Without a TLS handshake timeout, a slow or stalled TLS peer could
+// hang a worker indefinitely even with the dial and response-header
+// timeouts in place.
+func (wm *WebhookManager) clientForEndpoint(endpoint *WebhookEndpoint) *http.Client {
+	if endpoint.ConnectTimeout == 0 && endpoint.TLSHandshakeTimeout == 0 && endpoint.ResponseHeaderTimeout == 0 {
+		return wm.client
+	}
+
+	return &http.Client{
+		Timeout: endpoint.Timeout,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: endpoint.ConnectTimeout,
+			}).DialContext,
+			TLSHandshakeTimeout:   endpoint.TLSHandshakeTimeout,
+			ResponseHeaderTimeout: endpoint.ResponseHeaderTimeout,
+		},
+	}
+}
+
 func (wm *WebhookManager) generateSignature(payload []byte, secret string) string {
 	h := hmac.New(sha256.New, []byte(secret))
 	h.Write(payload)
 	return "sha256=" + hex.EncodeToString(h.Sum(nil))
 }
 
+// VerifySignature reports whether signature (the value of the
+// X-Webhook-Signature header) is a valid HMAC-SHA256 signature of payload
+// for any of secrets. A receiver should pass both an endpoint's current
+// secret and, during a RotateSecret overlap window, its PreviousSecret, so
+// deliveries signed with either are accepted instead of being rejected for
+// the duration of the rotation.
+func VerifySignature(payload []byte, signature string, secrets ...string) bool {
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		h := hmac.New(sha256.New, []byte(secret))
+		h.Write(payload)
+		expected := "sha256=" + hex.EncodeToString(h.Sum(nil))
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return true
+		}
+	}
+	return false
+}
+
 func (wm *WebhookManager) calculateRetryDelay(attempt int) time.Duration {
-	delay := time.Duration(float64(wm.retryPolicy.BaseDelay) * 
+	delay := time.Duration(float64(wm.retryPolicy.BaseDelay) *
 		(wm.retryPolicy.Multiplier * float64(attempt-1)))
-	
+
 	if delay > wm.retryPolicy.MaxDelay {
 		delay = wm.retryPolicy.MaxDelay
 	}
-	
+
+	if wm.retryPolicy.Jitter && delay > 0 {
+		delay = time.Duration(rand.Int63n(int64(delay) + 1))
+	}
+
 	return delay
 }
 
+// SetRetryJitter enables or disables full jitter on webhook retry delays
+// (see RetryPolicy.Jitter).
+func (wm *WebhookManager) SetRetryJitter(enabled bool) {
+	wm.mutex.Lock()
+	wm.retryPolicy.Jitter = enabled
+	wm.mutex.Unlock()
+}
+
 func (wm *WebhookManager) updateEndpointStats(endpoint *WebhookEndpoint, delivery *WebhookDelivery) {
 	endpoint.TotalCalls++
-	
+
 	if delivery.Status == "success" {
 		endpoint.SuccessRate = float64(endpoint.TotalCalls-endpoint.FailedCalls) / float64(endpoint.TotalCalls)
 	} else if delivery.Status == "failed" {
@@ -503,7 +1508,7 @@ func (wm *WebhookManager) updateGlobalStats(delivery *WebhookDelivery) {
 
 	wm.stats.TotalDeliveries++
 	wm.stats.DeliveriesByStatus[delivery.Status]++
-	
+
 	if delivery.Status == "success" {
 		wm.stats.SuccessfulDeliveries++
 	} else if delivery.Status == "failed" {
@@ -546,4 +1551,4 @@ func (wm *WebhookManager) generateEventID() string {
 
 func (wm *WebhookManager) generateDeliveryID() string {
 	return fmt.Sprintf("delivery_%d", time.Now().UnixNano())
-}
\ No newline at end of file
+}
@@ -0,0 +1,481 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCalculateRetryDelayJitterSpread(t *testing.T) {
+	wm := NewWebhookManager()
+	wm.retryPolicy = &RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   1 * time.Second,
+		MaxDelay:    60 * time.Second,
+		Multiplier:  2.0,
+		Jitter:      true,
+	}
+
+	computed := wm.retryPolicy.BaseDelay * time.Duration(wm.retryPolicy.Multiplier*float64(3-1))
+
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 50; i++ {
+		delay := wm.calculateRetryDelay(3)
+		if delay < 0 || delay > computed {
+			t.Fatalf("delay %v out of expected range [0, %v]", delay, computed)
+		}
+		seen[delay] = true
+	}
+
+	if len(seen) < 2 {
+		t.Fatalf("expected jittered delays to spread across multiple values, got %d distinct value(s)", len(seen))
+	}
+}
+
+func TestCalculateRetryDelayNoJitterIsDeterministic(t *testing.T) {
+	wm := NewWebhookManager()
+	wm.retryPolicy.Jitter = false
+
+	first := wm.calculateRetryDelay(3)
+	for i := 0; i < 10; i++ {
+		if delay := wm.calculateRetryDelay(3); delay != first {
+			t.Fatalf("expected deterministic delay %v without jitter, got %v", first, delay)
+		}
+	}
+}
+
+// newTestManager returns a manager with retries disabled and a short timeout,
+// so tests exercising failing/slow endpoints don't wait through backoff.
+func newTestManager() *WebhookManager {
+	wm := NewWebhookManager()
+	wm.retryPolicy = &RetryPolicy{MaxAttempts: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1}
+	return wm
+}
+
+func TestUpsertEndpointCreatesThenUpdatesInPlace(t *testing.T) {
+	wm := newTestManager()
+
+	created, err := wm.UpsertEndpoint(&WebhookEndpoint{Name: "receiver", URL: "https://example.com/hook"})
+	if err != nil {
+		t.Fatalf("UpsertEndpoint create: %v", err)
+	}
+	if created.ID == "" {
+		t.Fatal("expected a generated ID for a new endpoint")
+	}
+
+	updated, err := wm.UpsertEndpoint(&WebhookEndpoint{Name: "receiver", URL: "https://example.com/hook/v2"})
+	if err != nil {
+		t.Fatalf("UpsertEndpoint update: %v", err)
+	}
+
+	if updated.ID != created.ID {
+		t.Fatalf("expected re-applying the same name to keep ID %q, got %q", created.ID, updated.ID)
+	}
+	if updated.URL != "https://example.com/hook/v2" {
+		t.Fatalf("expected URL to be updated, got %q", updated.URL)
+	}
+	if wm.ListEndpoints(); len(wm.ListEndpoints()) != 1 {
+		t.Fatalf("expected exactly one endpoint after upserting the same name twice, got %d", len(wm.ListEndpoints()))
+	}
+}
+
+func TestUpsertEndpointConcurrentNewNameCreatesOnlyOne(t *testing.T) {
+	wm := newTestManager()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := wm.UpsertEndpoint(&WebhookEndpoint{Name: "shared", URL: "https://example.com/hook"}); err != nil {
+				t.Errorf("UpsertEndpoint: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := len(wm.ListEndpoints()); got != 1 {
+		t.Fatalf("expected concurrent upserts for a new name to create exactly one endpoint, got %d", got)
+	}
+}
+
+func TestAddEndpointWithValidationDryRunFailureLeavesEndpointUnsaved(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	wm := newTestManager()
+	_, err := wm.AddEndpointWithValidation(&WebhookEndpoint{Name: "flaky", URL: server.URL}, true)
+	if err == nil {
+		t.Fatal("expected dry-run validation against a failing receiver to return an error")
+	}
+	if len(wm.ListEndpoints()) != 0 {
+		t.Fatalf("expected a failed dry run to leave the endpoint unsaved, got %d endpoints", len(wm.ListEndpoints()))
+	}
+}
+
+func TestAddEndpointWithValidationDryRunSuccessSaves(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	wm := newTestManager()
+	if _, err := wm.AddEndpointWithValidation(&WebhookEndpoint{Name: "healthy", URL: server.URL}, true); err != nil {
+		t.Fatalf("expected dry-run validation against a healthy receiver to succeed, got %v", err)
+	}
+	if len(wm.ListEndpoints()) != 1 {
+		t.Fatalf("expected a successful dry run to save the endpoint, got %d endpoints", len(wm.ListEndpoints()))
+	}
+}
+
+func TestRenderEndpointHeadersTemplatesAgainstEventData(t *testing.T) {
+	event := &WebhookEvent{ID: "evt1", Type: "message.sent", Data: map[string]interface{}{"chat_id": "6281234"}}
+
+	headers, err := renderEndpointHeaders(map[string]string{
+		"X-Chat-Id":     "{{.data.chat_id}}",
+		"Authorization": "Bearer static-token",
+	}, event)
+	if err != nil {
+		t.Fatalf("renderEndpointHeaders: %v", err)
+	}
+
+	if headers["X-Chat-Id"] != "6281234" {
+		t.Fatalf("expected templated header to resolve to the event field, got %q", headers["X-Chat-Id"])
+	}
+	if headers["Authorization"] != "Bearer static-token" {
+		t.Fatalf("expected static header to pass through unchanged, got %q", headers["Authorization"])
+	}
+}
+
+func TestRenderEndpointURLSubstitutesPathVariable(t *testing.T) {
+	event := &WebhookEvent{ID: "evt1", Type: "message.sent", Data: map[string]interface{}{"instance_id": "abc123"}}
+
+	renderedURL, err := renderEndpointURL("https://example.com/hooks/{{.data.instance_id}}", event)
+	if err != nil {
+		t.Fatalf("renderEndpointURL: %v", err)
+	}
+	if renderedURL != "https://example.com/hooks/abc123" {
+		t.Fatalf("expected rendered URL with substituted instance ID, got %q", renderedURL)
+	}
+}
+
+func TestRenderEndpointURLRejectsGarbageResult(t *testing.T) {
+	event := &WebhookEvent{ID: "evt1", Type: "message.sent", Data: map[string]interface{}{}}
+
+	if _, err := renderEndpointURL("{{.data.missing.field}}", event); err == nil {
+		t.Fatal("expected a template referencing a missing nested field to fail rendering")
+	}
+}
+
+func TestAttemptDeliveryTruncatesOversizedPayload(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	wm := newTestManager()
+	endpoint := &WebhookEndpoint{
+		ID:              "ep1",
+		URL:             server.URL,
+		Timeout:         time.Second,
+		MaxPayloadBytes: 10,
+		OversizePolicy:  "truncate",
+		TruncateField:   "media",
+		Headers:         map[string]string{},
+	}
+
+	event := &WebhookEvent{ID: "evt1", Type: "media.sent", Data: map[string]interface{}{"media": "a very large base64 blob well over ten bytes"}}
+	delivery := wm.deliverEvent(endpoint, event)
+
+	if delivery.Status != "success" {
+		t.Fatalf("expected the truncated delivery to still succeed, got status %q (%s)", delivery.Status, delivery.Error)
+	}
+	if received["data"].(map[string]interface{})["media"] == event.Data["media"] {
+		t.Fatal("expected the oversized field to be replaced with a placeholder before sending")
+	}
+}
+
+func TestAttemptDeliverySkipsOversizedPayloadWhenPolicyIsSkip(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	wm := newTestManager()
+	endpoint := &WebhookEndpoint{
+		ID:              "ep1",
+		URL:             server.URL,
+		MaxPayloadBytes: 10,
+		OversizePolicy:  "skip",
+		Headers:         map[string]string{},
+	}
+
+	event := &WebhookEvent{ID: "evt1", Type: "media.sent", Data: map[string]interface{}{"media": "a very large base64 blob well over ten bytes"}}
+	delivery := wm.deliverEvent(endpoint, event)
+
+	if delivery.Status != "payload_too_large" {
+		t.Fatalf("expected status payload_too_large, got %q", delivery.Status)
+	}
+	if called {
+		t.Fatal("expected the oversized payload to be skipped without calling the receiver")
+	}
+}
+
+func TestShadowEndpointDeliversWithoutRetryOrGlobalStats(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	wm := NewWebhookManager()
+	wm.retryPolicy = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1}
+	endpoint := &WebhookEndpoint{ID: "ep1", URL: server.URL, Timeout: time.Second, Shadow: true, Headers: map[string]string{}}
+
+	statsBefore := wm.GetStats()
+	delivery := wm.deliverEvent(endpoint, &WebhookEvent{ID: "evt1", Type: "message.sent", Data: map[string]interface{}{}})
+	statsAfter := wm.GetStats()
+
+	if delivery.Attempts != 1 {
+		t.Fatalf("expected a shadow endpoint to make exactly one attempt regardless of MaxAttempts, got %d", delivery.Attempts)
+	}
+	if atomic.LoadInt32(&attempts) != 1 {
+		t.Fatalf("expected exactly one HTTP request to the shadow receiver, got %d", attempts)
+	}
+	if statsAfter.TotalDeliveries != statsBefore.TotalDeliveries {
+		t.Fatal("expected a shadow delivery to be excluded from global stats")
+	}
+	if endpoint.FailedCalls == 0 {
+		t.Fatal("expected the shadow endpoint's own call counters to still be tracked")
+	}
+}
+
+func TestSendEventDebouncesIntoASingleBatch(t *testing.T) {
+	var requestCount int32
+	var batch map[string]interface{}
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requestCount, 1)
+		mu.Lock()
+		_ = json.NewDecoder(r.Body).Decode(&batch)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	wm := newTestManager()
+	if _, err := wm.AddEndpointWithValidation(&WebhookEndpoint{
+		Name:           "debounced",
+		URL:            server.URL,
+		Events:         []string{"message.sent"},
+		DebounceWindow: 30 * time.Millisecond,
+	}, false); err != nil {
+		t.Fatalf("AddEndpointWithValidation: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := wm.SendEvent(&WebhookEvent{Type: "message.sent", Data: map[string]interface{}{"n": i}}); err != nil {
+			t.Fatalf("SendEvent: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&requestCount) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if got := atomic.LoadInt32(&requestCount); got != 1 {
+		t.Fatalf("expected debounced events to be delivered as a single batched request, got %d requests", got)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if batch["type"] != "webhook.batch" {
+		t.Fatalf("expected the batched delivery type to be webhook.batch, got %v", batch["type"])
+	}
+	if count, ok := batch["data"].(map[string]interface{})["count"].(float64); !ok || count != 3 {
+		t.Fatalf("expected the batch to report 3 buffered events, got %v", batch["data"])
+	}
+}
+
+func TestOrderedDeliverySerializesSamePartition(t *testing.T) {
+	var inFlight, maxInFlight, completed int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond) // widen the window a race would need to land in
+		atomic.AddInt32(&inFlight, -1)
+		atomic.AddInt32(&completed, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	wm := newTestManager()
+	endpoint, err := wm.UpsertEndpoint(&WebhookEndpoint{
+		Name:                   "ordered",
+		URL:                    server.URL,
+		Events:                 []string{"message.sent"},
+		OrderedDelivery:        true,
+		OrderingPartitionField: "chat_id",
+	})
+	if err != nil {
+		t.Fatalf("UpsertEndpoint: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(seq int) {
+			defer wg.Done()
+			wm.dispatchToEndpoint(endpoint, &WebhookEvent{
+				ID:   wm.generateEventID(),
+				Type: "message.sent",
+				Data: map[string]interface{}{"chat_id": "same-chat", "seq": float64(seq)},
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	if completed != 3 {
+		t.Fatalf("expected all 3 same-partition deliveries to complete, got %d", completed)
+	}
+	if maxInFlight > 1 {
+		t.Fatalf("expected OrderedDelivery to serialize same-partition deliveries, but saw %d in flight at once", maxInFlight)
+	}
+}
+
+func TestRotateSecretKeepsPreviousSecretForOverlapWindow(t *testing.T) {
+	wm := newTestManager()
+	endpoint, err := wm.AddEndpointWithValidation(&WebhookEndpoint{Name: "signed", URL: "https://example.com/hook", Secret: "old-secret"}, false)
+	_ = endpoint
+	if err != nil {
+		t.Fatalf("AddEndpointWithValidation: %v", err)
+	}
+	created := wm.ListEndpoints()[0]
+
+	if err := wm.RotateSecret(created.ID, "new-secret", 50*time.Millisecond); err != nil {
+		t.Fatalf("RotateSecret: %v", err)
+	}
+
+	updated, _ := wm.GetEndpoint(created.ID)
+	if updated.Secret != "new-secret" {
+		t.Fatalf("expected the active secret to be updated, got %q", updated.Secret)
+	}
+	if updated.PreviousSecret != "old-secret" {
+		t.Fatalf("expected the previous secret to be retained during the overlap window, got %q", updated.PreviousSecret)
+	}
+
+	payload := []byte(`{"hello":"world"}`)
+	oldSignature := wm.generateSignature(payload, "old-secret")
+	if !VerifySignature(payload, oldSignature, updated.Secret, updated.PreviousSecret) {
+		t.Fatal("expected a signature from the old secret to still verify during the overlap window")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	afterOverlap, _ := wm.GetEndpoint(created.ID)
+	if afterOverlap.PreviousSecret != "" {
+		t.Fatalf("expected the previous secret to be dropped once the overlap window elapses, got %q", afterOverlap.PreviousSecret)
+	}
+}
+
+func TestTestAllEndpointsReturnsPerEndpointResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	wm := newTestManager()
+	if _, err := wm.AddEndpointWithValidation(&WebhookEndpoint{Name: "one", URL: server.URL}, false); err != nil {
+		t.Fatalf("AddEndpointWithValidation: %v", err)
+	}
+	if _, err := wm.AddEndpointWithValidation(&WebhookEndpoint{Name: "two", URL: server.URL}, false); err != nil {
+		t.Fatalf("AddEndpointWithValidation: %v", err)
+	}
+
+	results := wm.TestAllEndpoints()
+	if len(results) != 2 {
+		t.Fatalf("expected one result per endpoint, got %d", len(results))
+	}
+	for id, delivery := range results {
+		if delivery.Status != "success" {
+			t.Fatalf("expected endpoint %s test delivery to succeed, got %q", id, delivery.Status)
+		}
+	}
+}
+
+func TestDebugDeliverReturnsFullExchangeWithoutTouchingStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Reply", "ack")
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte(`{"ok":false}`))
+	}))
+	defer server.Close()
+
+	wm := newTestManager()
+	created, err := wm.AddEndpointWithValidation(&WebhookEndpoint{Name: "debug-target", URL: server.URL}, false)
+	_ = created
+	if err != nil {
+		t.Fatalf("AddEndpointWithValidation: %v", err)
+	}
+	endpoint := wm.ListEndpoints()[0]
+
+	statsBefore := wm.GetStats()
+	result, err := wm.DebugDeliver(endpoint.ID, &WebhookEvent{ID: "evt1", Type: "message.sent", Data: map[string]interface{}{}})
+	if err != nil {
+		t.Fatalf("DebugDeliver: %v", err)
+	}
+	statsAfter := wm.GetStats()
+
+	if result.StatusCode != http.StatusTeapot {
+		t.Fatalf("expected the raw receiver status code %d, got %d", http.StatusTeapot, result.StatusCode)
+	}
+	if result.ResponseHeaders["X-Reply"] != "ack" {
+		t.Fatalf("expected the receiver's response headers to be captured, got %v", result.ResponseHeaders)
+	}
+	if statsAfter.TotalDeliveries != statsBefore.TotalDeliveries {
+		t.Fatal("expected DebugDeliver to leave global delivery stats untouched")
+	}
+	if endpoint.TotalCalls != 0 {
+		t.Fatal("expected DebugDeliver to leave endpoint call counters untouched")
+	}
+}
+
+func TestGetHealthReportsUnhealthyPastBacklogThreshold(t *testing.T) {
+	wm := NewWebhookManager()
+	wm.healthBacklogThreshold = time.Millisecond
+
+	if !wm.GetHealth().Healthy {
+		t.Fatal("expected a manager with nothing pending to be healthy")
+	}
+
+	pendingID := wm.stats.beginPendingDelivery()
+	time.Sleep(5 * time.Millisecond)
+
+	health := wm.GetHealth()
+	if health.Healthy {
+		t.Fatal("expected the pool to be unhealthy once the oldest pending delivery exceeds the backlog threshold")
+	}
+	if health.OldestPendingAt == nil {
+		t.Fatal("expected OldestPendingAt to be set while a delivery is pending")
+	}
+
+	wm.stats.endPendingDelivery(pendingID)
+	if !wm.GetHealth().Healthy {
+		t.Fatal("expected the pool to recover once the pending delivery clears")
+	}
+}
@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"strings"
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/analytics"
@@ -12,6 +13,12 @@ type Analytics struct {
 	manager *analytics.Analytics
 }
 
+// Manager exposes the underlying analytics manager for read-only aggregation
+// by other route groups (e.g. scheduled report delivery).
+func (handler *Analytics) Manager() *analytics.Analytics {
+	return handler.manager
+}
+
 func InitRestAnalytics(app fiber.Router) Analytics {
 	am := analytics.NewAnalytics()
 	rest := Analytics{manager: am}
@@ -23,6 +30,9 @@ func InitRestAnalytics(app fiber.Router) Analytics {
 	app.Get("/analytics/monthly", rest.GetMonthlyReport)
 	app.Get("/analytics/custom", rest.GetCustomReport)
 	app.Post("/analytics/track", rest.TrackEvent)
+	app.Get("/analytics/sampling", rest.GetSamplingRates)
+	app.Put("/analytics/sampling", rest.SetSamplingRate)
+	app.Get("/analytics/spill", rest.GetSpillMetrics)
 
 	return rest
 }
@@ -110,7 +120,20 @@ func (handler *Analytics) GetCustomReport(c *fiber.Ctx) error {
 	// Add 24 hours to end date to include the entire day
 	endDate = endDate.Add(24 * time.Hour)
 
-	report := handler.manager.GenerateReport(period, startDate, endDate)
+	// Any query param prefixed with "filter_" restricts the report to
+	// events whose Data field matches it, e.g. ?filter_campaign_id=summer23
+	var filters map[string]interface{}
+	for key, value := range c.Queries() {
+		if !strings.HasPrefix(key, "filter_") {
+			continue
+		}
+		if filters == nil {
+			filters = make(map[string]interface{})
+		}
+		filters[strings.TrimPrefix(key, "filter_")] = value
+	}
+
+	report := handler.manager.GenerateReport(period, startDate, endDate, filters)
 
 	return c.JSON(utils.ResponseData{
 		Status:  200,
@@ -120,6 +143,72 @@ func (handler *Analytics) GetCustomReport(c *fiber.Ctx) error {
 	})
 }
 
+func (handler *Analytics) GetSamplingRates(c *fiber.Ctx) error {
+	rates := handler.manager.GetSamplingRates()
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Sampling rates retrieved",
+		Results: rates,
+	})
+}
+
+func (handler *Analytics) SetSamplingRate(c *fiber.Ctx) error {
+	var request struct {
+		Type string  `json:"type"`
+		Rate float64 `json:"rate"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if request.Type == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Event type is required",
+			Results: nil,
+		})
+	}
+
+	if err := handler.manager.SetSamplingRate(request.Type, request.Rate); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Sampling rate updated",
+		Results: map[string]interface{}{
+			"type": request.Type,
+			"rate": request.Rate,
+		},
+	})
+}
+
+func (handler *Analytics) GetSpillMetrics(c *fiber.Ctx) error {
+	metrics := handler.manager.GetSpillMetrics()
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Analytics spill metrics retrieved",
+		Results: metrics,
+	})
+}
+
 func (handler *Analytics) TrackEvent(c *fiber.Ctx) error {
 	var request struct {
 		Type     string                 `json:"type"`
@@ -171,21 +260,21 @@ func (handler *Analytics) TrackEvent(c *fiber.Ctx) error {
 func (handler *Analytics) TrackingMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		start := time.Now()
-		
+
 		// Process request
 		err := c.Next()
-		
+
 		// Track the API call
 		duration := time.Since(start)
 		statusCode := c.Response().StatusCode()
-		
+
 		handler.manager.TrackAPICall(
 			c.Path(),
 			c.Method(),
 			statusCode,
 			duration,
 		)
-		
+
 		return err
 	}
-}
\ No newline at end of file
+}
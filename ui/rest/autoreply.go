@@ -0,0 +1,172 @@
+package rest
+
+import (
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/autoreply"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/queue"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/templates"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AutoReply struct {
+	manager *autoreply.Manager
+}
+
+// Manager exposes the underlying auto-reply manager so infrastructure code
+// can evaluate inbound messages against the configured rules.
+func (handler *AutoReply) Manager() *autoreply.Manager {
+	return handler.manager
+}
+
+func InitRestAutoReply(app fiber.Router, templateMgr *templates.TemplateManager, queueMgr *queue.QueueManager) AutoReply {
+	am := autoreply.NewManager(templateMgr, queueMgr)
+	rest := AutoReply{manager: am}
+
+	app.Post("/autoreply/rules", rest.AddRule)
+	app.Get("/autoreply/rules", rest.ListRules)
+	app.Get("/autoreply/rules/:id", rest.GetRule)
+	app.Put("/autoreply/rules/:id", rest.UpdateRule)
+	app.Delete("/autoreply/rules/:id", rest.RemoveRule)
+
+	return rest
+}
+
+func (handler *AutoReply) AddRule(c *fiber.Ctx) error {
+	var rule autoreply.Rule
+
+	if err := c.BodyParser(&rule); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if err := handler.manager.AddRule(&rule); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "AUTOREPLY_ERROR",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  201,
+		Code:    "SUCCESS",
+		Message: "Auto-reply rule added successfully",
+		Results: rule,
+	})
+}
+
+func (handler *AutoReply) ListRules(c *fiber.Ctx) error {
+	rules := handler.manager.ListRules()
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Auto-reply rules retrieved successfully",
+		Results: map[string]interface{}{
+			"rules": rules,
+			"count": len(rules),
+		},
+	})
+}
+
+func (handler *AutoReply) GetRule(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Rule ID is required",
+			Results: nil,
+		})
+	}
+
+	rule, err := handler.manager.GetRule(id)
+	if err != nil {
+		return c.Status(404).JSON(utils.ResponseData{
+			Status:  404,
+			Code:    "NOT_FOUND",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Auto-reply rule retrieved successfully",
+		Results: rule,
+	})
+}
+
+func (handler *AutoReply) UpdateRule(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Rule ID is required",
+			Results: nil,
+		})
+	}
+
+	var updates map[string]interface{}
+	if err := c.BodyParser(&updates); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if err := handler.manager.UpdateRule(id, updates); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "UPDATE_ERROR",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	rule, _ := handler.manager.GetRule(id)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Auto-reply rule updated successfully",
+		Results: rule,
+	})
+}
+
+func (handler *AutoReply) RemoveRule(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Rule ID is required",
+			Results: nil,
+		})
+	}
+
+	if err := handler.manager.RemoveRule(id); err != nil {
+		return c.Status(404).JSON(utils.ResponseData{
+			Status:  404,
+			Code:    "NOT_FOUND",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Auto-reply rule removed successfully",
+		Results: nil,
+	})
+}
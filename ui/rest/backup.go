@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/backup"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/multiinstance"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/gofiber/fiber/v2"
 	"github.com/spf13/viper"
@@ -14,11 +15,11 @@ type Backup struct {
 	manager *backup.BackupManager
 }
 
-func InitRestBackup(app fiber.Router) Backup {
+func InitRestBackup(app fiber.Router, instanceMgr *multiinstance.InstanceManager) Backup {
 	// Load backup configuration from environment variables
 	backupConfig := loadBackupConfig()
 
-	bm := backup.NewBackupManager(backupConfig)
+	bm := backup.NewBackupManager(backupConfig, instanceMgr)
 	rest := Backup{manager: bm}
 
 	// Backup management routes
@@ -28,10 +29,12 @@ func InitRestBackup(app fiber.Router) Backup {
 	app.Post("/backup/full", rest.BackupFull)
 	app.Get("/backup/list", rest.ListBackups)
 	app.Post("/backup/restore/:jobId", rest.RestoreBackup)
+	app.Post("/backup/restore/:jobId/as-instance", rest.RestoreAsInstance)
 	app.Delete("/backup/:jobId", rest.DeleteBackup)
 	app.Post("/backup/schedule", rest.ScheduleBackup)
 	app.Get("/backup/stats", rest.GetStats)
 	app.Get("/backup/config", rest.GetConfig)
+	app.Post("/backup/validate", rest.ValidateConfig)
 
 	return rest
 }
@@ -293,6 +296,57 @@ func (handler *Backup) RestoreBackup(c *fiber.Ctx) error {
 	})
 }
 
+func (handler *Backup) RestoreAsInstance(c *fiber.Ctx) error {
+	jobID := c.Params("jobId")
+	if jobID == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Job ID is required",
+			Results: nil,
+		})
+	}
+
+	var request struct {
+		NewName string `json:"new_name"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if request.NewName == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "new_name is required",
+			Results: nil,
+		})
+	}
+
+	instance, err := handler.manager.RestoreAsInstance(jobID, request.NewName)
+	if err != nil {
+		return c.Status(500).JSON(utils.ResponseData{
+			Status:  500,
+			Code:    "RESTORE_ERROR",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  201,
+		Code:    "SUCCESS",
+		Message: "Backup restored as new instance successfully",
+		Results: instance,
+	})
+}
+
 func (handler *Backup) DeleteBackup(c *fiber.Ctx) error {
 	jobID := c.Params("jobId")
 	if jobID == "" {
@@ -390,4 +444,20 @@ func (handler *Backup) GetConfig(c *fiber.Ctx) error {
 		Message: "Backup configuration retrieved",
 		Results: config,
 	})
-}
\ No newline at end of file
+}
+
+// ValidateConfig performs a real connectivity/permission check against the
+// configured backup provider, so an operator can confirm their setup works
+// before relying on it for a scheduled backup.
+func (handler *Backup) ValidateConfig(c *fiber.Ctx) error {
+	if err := handler.manager.ValidateBackupConfig(); err != nil {
+		return respondError(c, err, 500, "VALIDATE_ERROR")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Backup configuration is valid",
+		Results: nil,
+	})
+}
@@ -23,6 +23,8 @@ func InitRestCache(app fiber.Router) Cache {
 		DB:       config.RedisDB,
 		Prefix:   config.RedisPrefix,
 		URL:      config.RedisURL, // For Upstash Redis
+
+		StatsIntervalSeconds: config.RedisStatsIntervalSeconds,
 	}
 
 	rm := cache.NewRedisManager(cacheConfig)
@@ -41,9 +43,14 @@ func InitRestCache(app fiber.Router) Cache {
 	app.Post("/cache/expire", rest.SetExpiration)
 	app.Delete("/cache/flush", rest.FlushAll)
 	app.Get("/cache/stats", rest.GetStats)
+	app.Post("/cache/stats/reset", rest.ResetStats)
+	app.Get("/cache/keys/count", rest.CountKeys)
 	app.Get("/cache/health", rest.GetHealth)
 	app.Get("/cache/test", rest.TestConnection)
 	app.Get("/cache/keys", rest.ListKeys)
+	app.Post("/cache/versioned", rest.SetVersioned)
+	app.Get("/cache/versioned/:key", rest.GetVersioned)
+	app.Post("/cache/compare-and-swap", rest.CompareAndSwap)
 
 	return rest
 }
@@ -132,6 +139,154 @@ func (handler *Cache) GetCache(c *fiber.Ctx) error {
 	})
 }
 
+func (handler *Cache) SetVersioned(c *fiber.Ctx) error {
+	var request struct {
+		Key             string      `json:"key"`
+		Value           interface{} `json:"value"`
+		ExpectedVersion int64       `json:"expected_version"`
+		Expiration      int         `json:"expiration"` // seconds
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if request.Key == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Key is required",
+			Results: nil,
+		})
+	}
+
+	expiration := time.Duration(request.Expiration) * time.Second
+
+	newVersion, err := handler.manager.SetVersioned(request.Key, request.Value, request.ExpectedVersion, expiration)
+	if err != nil {
+		return c.Status(409).JSON(utils.ResponseData{
+			Status:  409,
+			Code:    "VERSION_CONFLICT",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Cache set successfully",
+		Results: map[string]interface{}{
+			"key":     request.Key,
+			"version": newVersion,
+		},
+	})
+}
+
+func (handler *Cache) GetVersioned(c *fiber.Ctx) error {
+	key := c.Params("key")
+	if key == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Key is required",
+			Results: nil,
+		})
+	}
+
+	var value interface{}
+	version, err := handler.manager.GetVersioned(key, &value)
+	if err != nil {
+		return c.Status(404).JSON(utils.ResponseData{
+			Status:  404,
+			Code:    "CACHE_MISS",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Cache retrieved successfully",
+		Results: map[string]interface{}{
+			"key":     key,
+			"value":   value,
+			"version": version,
+		},
+	})
+}
+
+// CompareAndSwap replaces a cached value with a new one only if it still
+// matches the caller's expected value, letting callers do a safe optimistic
+// update without holding a separate lock. Omit "expected" (or send null) to
+// require that the key doesn't exist yet.
+func (handler *Cache) CompareAndSwap(c *fiber.Ctx) error {
+	var request struct {
+		Key        string      `json:"key"`
+		Expected   interface{} `json:"expected"`
+		New        interface{} `json:"new"`
+		Expiration int         `json:"expiration"` // seconds
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if request.Key == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Key is required",
+			Results: nil,
+		})
+	}
+
+	expiration := time.Duration(request.Expiration) * time.Second
+
+	swapped, err := handler.manager.CompareAndSwap(request.Key, request.Expected, request.New, expiration)
+	if err != nil {
+		return c.Status(500).JSON(utils.ResponseData{
+			Status:  500,
+			Code:    "CACHE_ERROR",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	if !swapped {
+		return c.Status(409).JSON(utils.ResponseData{
+			Status:  409,
+			Code:    "VALUE_CONFLICT",
+			Message: "Current value did not match expected value",
+			Results: map[string]interface{}{
+				"key":     request.Key,
+				"swapped": false,
+			},
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Cache value swapped successfully",
+		Results: map[string]interface{}{
+			"key":     request.Key,
+			"swapped": true,
+		},
+	})
+}
+
 func (handler *Cache) DeleteCache(c *fiber.Ctx) error {
 	key := c.Params("key")
 	if key == "" {
@@ -489,6 +644,46 @@ func (handler *Cache) GetStats(c *fiber.Ctx) error {
 	})
 }
 
+// ResetStats zeroes out the accumulated hit/miss/operation counters, useful
+// between test runs or right after a deploy so stats reflect the new
+// process instead of carrying over stale totals.
+func (handler *Cache) ResetStats(c *fiber.Ctx) error {
+	handler.manager.ResetStats()
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Cache statistics reset",
+		Results: nil,
+	})
+}
+
+// CountKeys counts keys matching a pattern using SCAN, so it's safe to call
+// against a large keyspace without blocking Redis the way KEYS would.
+func (handler *Cache) CountKeys(c *fiber.Ctx) error {
+	pattern := c.Query("pattern", "*")
+
+	count, err := handler.manager.CountKeys(pattern)
+	if err != nil {
+		return c.Status(500).JSON(utils.ResponseData{
+			Status:  500,
+			Code:    "CACHE_ERROR",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Key count retrieved successfully",
+		Results: map[string]interface{}{
+			"pattern": pattern,
+			"count":   count,
+		},
+	})
+}
+
 func (handler *Cache) GetHealth(c *fiber.Ctx) error {
 	stats := handler.manager.GetStats()
 	health := map[string]interface{}{
@@ -502,12 +697,12 @@ func (handler *Cache) GetHealth(c *fiber.Ctx) error {
 		},
 		"timestamp": time.Now(),
 	}
-	
+
 	if !stats.Connected {
 		health["status"] = "unhealthy"
 		health["message"] = "Redis connection failed"
 	}
-	
+
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
@@ -518,7 +713,7 @@ func (handler *Cache) GetHealth(c *fiber.Ctx) error {
 
 func (handler *Cache) TestConnection(c *fiber.Ctx) error {
 	connectionStatus := handler.manager.TestConnection()
-	
+
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
@@ -530,7 +725,7 @@ func (handler *Cache) TestConnection(c *fiber.Ctx) error {
 func (handler *Cache) ListKeys(c *fiber.Ctx) error {
 	// Get pattern from query parameter, default to all keys
 	pattern := c.Query("pattern", "*")
-	
+
 	keys, err := handler.manager.ListKeys(pattern)
 	if err != nil {
 		return c.Status(500).JSON(utils.ResponseData{
@@ -540,7 +735,7 @@ func (handler *Cache) ListKeys(c *fiber.Ctx) error {
 			Results: nil,
 		})
 	}
-	
+
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
@@ -550,4 +745,4 @@ func (handler *Cache) ListKeys(c *fiber.Ctx) error {
 			"count": len(keys),
 		},
 	})
-}
\ No newline at end of file
+}
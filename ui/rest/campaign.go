@@ -0,0 +1,193 @@
+package rest
+
+import (
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/campaign"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/queue"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/templates"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Campaign struct {
+	manager *campaign.Manager
+}
+
+func InitRestCampaign(app fiber.Router, templateMgr *templates.TemplateManager, queueMgr *queue.QueueManager) Campaign {
+	cm := campaign.NewManager(templateMgr, queueMgr)
+	rest := Campaign{manager: cm}
+
+	app.Post("/campaigns", rest.CreateCampaign)
+	app.Get("/campaigns", rest.ListCampaigns)
+	app.Get("/campaigns/:id", rest.GetCampaign)
+	app.Post("/campaigns/:id/start", rest.StartCampaign)
+	app.Post("/campaigns/:id/pause", rest.PauseCampaign)
+	app.Post("/campaigns/:id/resume", rest.ResumeCampaign)
+	app.Post("/campaigns/:id/cancel", rest.CancelCampaign)
+
+	return rest
+}
+
+func (handler *Campaign) CreateCampaign(c *fiber.Ctx) error {
+	var request struct {
+		Name          string               `json:"name"`
+		TemplateID    string               `json:"template_id"`
+		Recipients    []campaign.Recipient `json:"recipients"`
+		RatePerMinute int                  `json:"rate_per_minute"`
+		Priority      queue.Priority       `json:"priority"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if request.Priority == 0 {
+		request.Priority = queue.PriorityNormal
+	}
+
+	created, err := handler.manager.CreateCampaign(request.Name, request.TemplateID, request.Recipients, request.RatePerMinute, request.Priority)
+	if err != nil {
+		return respondError(c, err, 400, "CAMPAIGN_ERROR")
+	}
+
+	return c.Status(201).JSON(utils.ResponseData{
+		Status:  201,
+		Code:    "SUCCESS",
+		Message: "Campaign created successfully",
+		Results: created,
+	})
+}
+
+func (handler *Campaign) ListCampaigns(c *fiber.Ctx) error {
+	campaigns := handler.manager.ListCampaigns()
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Campaigns retrieved successfully",
+		Results: map[string]interface{}{
+			"campaigns": campaigns,
+			"count":     len(campaigns),
+		},
+	})
+}
+
+func (handler *Campaign) GetCampaign(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Campaign ID is required",
+			Results: nil,
+		})
+	}
+
+	snapshot, err := handler.manager.GetCampaign(id)
+	if err != nil {
+		return respondError(c, err, 404, "NOT_FOUND")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Campaign retrieved successfully",
+		Results: snapshot,
+	})
+}
+
+func (handler *Campaign) StartCampaign(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Campaign ID is required",
+			Results: nil,
+		})
+	}
+
+	if err := handler.manager.StartCampaign(id); err != nil {
+		return respondError(c, err, 400, "CAMPAIGN_ERROR")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Campaign started successfully",
+		Results: nil,
+	})
+}
+
+func (handler *Campaign) PauseCampaign(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Campaign ID is required",
+			Results: nil,
+		})
+	}
+
+	if err := handler.manager.PauseCampaign(id); err != nil {
+		return respondError(c, err, 400, "CAMPAIGN_ERROR")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Campaign paused successfully",
+		Results: nil,
+	})
+}
+
+func (handler *Campaign) ResumeCampaign(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Campaign ID is required",
+			Results: nil,
+		})
+	}
+
+	if err := handler.manager.ResumeCampaign(id); err != nil {
+		return respondError(c, err, 400, "CAMPAIGN_ERROR")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Campaign resumed successfully",
+		Results: nil,
+	})
+}
+
+func (handler *Campaign) CancelCampaign(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Campaign ID is required",
+			Results: nil,
+		})
+	}
+
+	if err := handler.manager.CancelCampaign(id); err != nil {
+		return respondError(c, err, 400, "CAMPAIGN_ERROR")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Campaign canceled successfully",
+		Results: nil,
+	})
+}
@@ -0,0 +1,28 @@
+package rest
+
+import (
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/apperr"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// respondError maps a manager error into the standard ResponseData envelope.
+// AppError values carry their own status/code; any other error falls back to
+// fallbackStatus/fallbackCode, matching the pre-taxonomy behavior.
+func respondError(c *fiber.Ctx, err error, fallbackStatus int, fallbackCode string) error {
+	if appErr, ok := apperr.As(err); ok {
+		return c.Status(appErr.HTTPStatus).JSON(utils.ResponseData{
+			Status:  appErr.HTTPStatus,
+			Code:    appErr.Code,
+			Message: appErr.Message,
+			Results: nil,
+		})
+	}
+
+	return c.Status(fallbackStatus).JSON(utils.ResponseData{
+		Status:  fallbackStatus,
+		Code:    fallbackCode,
+		Message: err.Error(),
+		Results: nil,
+	})
+}
@@ -0,0 +1,116 @@
+package rest
+
+import (
+	"os"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/instancecontrol"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type InstanceControl struct {
+	controller *instancecontrol.Controller
+}
+
+// InitRestInstanceControl exposes a small authenticated control surface
+// (readiness, in-flight request count, graceful stop) that a parent
+// InstanceManager can use to supervise this process as a child instance,
+// instead of relying solely on process signals and the shared filesystem.
+// Requests must carry an X-Instance-Timestamp header and an
+// X-Instance-Signature header holding the HMAC-SHA256 of the request's
+// method, path, and timestamp, signed with the secret injected via the
+// WHATSAPP_INSTANCE_SECRET env var. The timestamp must also be within 30s of
+// the server's clock, so a captured header pair can't be replayed later.
+func InitRestInstanceControl(app fiber.Router) InstanceControl {
+	rest := InstanceControl{controller: instancecontrol.NewController(config.InstanceControlSecret)}
+
+	group := app.Group("/internal/instance", rest.authenticate)
+	group.Get("/ready", rest.Ready)
+	group.Get("/inflight", rest.InFlight)
+	group.Post("/stop", rest.Stop)
+
+	return rest
+}
+
+// TrackingMiddleware counts requests currently in flight across the whole
+// app, so /internal/instance/inflight and the graceful stop drain reflect
+// real load.
+func (handler *InstanceControl) TrackingMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		handler.controller.BeginRequest()
+		defer handler.controller.EndRequest()
+
+		return c.Next()
+	}
+}
+
+func (handler *InstanceControl) authenticate(c *fiber.Ctx) error {
+	timestamp := c.Get("X-Instance-Timestamp")
+	signature := c.Get("X-Instance-Signature")
+
+	if timestamp == "" || signature == "" || !handler.controller.Verify(c.Method(), c.Path(), timestamp, signature) {
+		return c.Status(401).JSON(utils.ResponseData{
+			Status:  401,
+			Code:    "UNAUTHORIZED",
+			Message: "Invalid or missing instance control signature",
+			Results: nil,
+		})
+	}
+
+	return c.Next()
+}
+
+func (handler *InstanceControl) Ready(c *fiber.Ctx) error {
+	draining := handler.controller.IsDraining()
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Instance readiness retrieved",
+		Results: map[string]interface{}{
+			"ready":       !draining,
+			"draining":    draining,
+			"uptime_s":    int(handler.controller.Uptime().Seconds()),
+			"in_flight":   handler.controller.InFlight(),
+			"instance_id": config.InstanceID,
+		},
+	})
+}
+
+func (handler *InstanceControl) InFlight(c *fiber.Ctx) error {
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "In-flight request count retrieved",
+		Results: map[string]interface{}{
+			"in_flight": handler.controller.InFlight(),
+		},
+	})
+}
+
+// Stop marks the instance as draining and exits the process once its
+// in-flight requests finish (or a 30s deadline passes), so the parent's
+// graceful drain can observe a clean shutdown instead of sending a kill
+// signal.
+func (handler *InstanceControl) Stop(c *fiber.Ctx) error {
+	handler.controller.BeginDrain()
+	logrus.Info("[INSTANCE_CONTROL] Graceful stop requested by parent")
+
+	go func() {
+		deadline := time.Now().Add(30 * time.Second)
+		for handler.controller.InFlight() > 0 && time.Now().Before(deadline) {
+			time.Sleep(200 * time.Millisecond)
+		}
+		os.Exit(0)
+	}()
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Graceful stop initiated",
+		Results: nil,
+	})
+}
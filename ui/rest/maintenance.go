@@ -0,0 +1,121 @@
+package rest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/cache"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// maintenanceCacheKey holds the cluster-wide maintenance flag. It lives in
+// the shared cache (rather than a package-level bool) so every instance
+// behind a load balancer honors the same toggle, matching the multi-instance
+// deployment this API already supports.
+const maintenanceCacheKey = "maintenance:enabled"
+
+// maintenanceRetryAfterSeconds is a reasonable default hint for how long a
+// client should wait before retrying a request rejected during maintenance.
+const maintenanceRetryAfterSeconds = 60
+
+// maintenanceAllowedPathSubstrings lets health checks, config inspection,
+// and the maintenance toggle itself keep working while maintenance mode is
+// on - otherwise there'd be no way to tell the API is back up, or to turn
+// maintenance mode back off.
+var maintenanceAllowedPathSubstrings = []string{"health", "/config", "/maintenance"}
+
+type Maintenance struct{}
+
+func InitRestMaintenance(app fiber.Router) Maintenance {
+	rest := Maintenance{}
+
+	app.Post("/maintenance", rest.SetMaintenance)
+	app.Get("/maintenance", rest.GetMaintenance)
+
+	return rest
+}
+
+// Middleware returns a Fiber handler that rejects data-mutating requests
+// with 503 while maintenance mode is enabled. GET requests and the allowed
+// paths always pass through so health checks, config inspection, and the
+// maintenance toggle itself keep working.
+func (handler *Maintenance) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Method() == fiber.MethodGet || isMaintenanceAllowedPath(c.Path()) {
+			return c.Next()
+		}
+
+		enabled, err := isMaintenanceEnabled()
+		if err != nil || !enabled {
+			return c.Next()
+		}
+
+		c.Set(fiber.HeaderRetryAfter, fmt.Sprintf("%d", maintenanceRetryAfterSeconds))
+		return c.Status(fiber.StatusServiceUnavailable).JSON(utils.ResponseData{
+			Status:  fiber.StatusServiceUnavailable,
+			Code:    "MAINTENANCE_MODE",
+			Message: "the API is in maintenance mode, please retry later",
+		})
+	}
+}
+
+func isMaintenanceAllowedPath(path string) bool {
+	for _, substr := range maintenanceAllowedPathSubstrings {
+		if strings.Contains(path, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func isMaintenanceEnabled() (bool, error) {
+	var enabled bool
+	if err := cache.Get(maintenanceCacheKey, &enabled); err != nil {
+		return false, err
+	}
+	return enabled, nil
+}
+
+func (handler *Maintenance) SetMaintenance(c *fiber.Ctx) error {
+	var request struct {
+		Enabled bool `json:"enabled"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ResponseData{
+			Status:  fiber.StatusBadRequest,
+			Code:    "INVALID_REQUEST",
+			Message: "invalid request body: " + err.Error(),
+		})
+	}
+
+	if err := cache.Set(maintenanceCacheKey, request.Enabled, 0); err != nil {
+		return respondError(c, err, fiber.StatusInternalServerError, "MAINTENANCE_TOGGLE_FAILED")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "maintenance mode updated successfully",
+		Results: map[string]interface{}{
+			"enabled": request.Enabled,
+		},
+	})
+}
+
+func (handler *Maintenance) GetMaintenance(c *fiber.Ctx) error {
+	enabled, err := isMaintenanceEnabled()
+	if err != nil {
+		enabled = false
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "maintenance status retrieved successfully",
+		Results: map[string]interface{}{
+			"enabled": enabled,
+		},
+	})
+}
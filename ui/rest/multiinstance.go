@@ -10,6 +10,12 @@ type MultiInstance struct {
 	manager *multiinstance.InstanceManager
 }
 
+// Manager exposes the underlying instance manager for read-only aggregation
+// by other route groups (e.g. GET /system/info).
+func (handler *MultiInstance) Manager() *multiinstance.InstanceManager {
+	return handler.manager
+}
+
 func InitRestMultiInstance(app fiber.Router) MultiInstance {
 	im := multiinstance.NewInstanceManager()
 	rest := MultiInstance{manager: im}
@@ -18,12 +24,18 @@ func InitRestMultiInstance(app fiber.Router) MultiInstance {
 	app.Post("/instances", rest.CreateInstance)
 	app.Get("/instances", rest.ListInstances)
 	app.Get("/instances/:id", rest.GetInstance)
+	app.Get("/instances/:id/history", rest.GetInstanceHistory)
 	app.Post("/instances/:id/start", rest.StartInstance)
 	app.Post("/instances/:id/stop", rest.StopInstance)
 	app.Post("/instances/:id/restart", rest.RestartInstance)
+	app.Patch("/instances/:id/config", rest.UpdateInstanceConfig)
+	app.Post("/instances/:id/clone", rest.CloneInstance)
+	app.Post("/instances/:id/failover", rest.FailoverInstance)
 	app.Delete("/instances/:id", rest.DeleteInstance)
+	app.Post("/instances/:id/vacuum", rest.VacuumInstanceDatabase)
+	app.Post("/instances/:id/migrate-database", rest.MigrateInstanceDatabase)
 	app.Get("/instances/stats", rest.GetStats)
-	
+
 	// Alias routes for compatibility with frontend
 	app.Get("/multiinstance/list", rest.ListInstances)
 
@@ -32,8 +44,8 @@ func InitRestMultiInstance(app fiber.Router) MultiInstance {
 
 func (handler *MultiInstance) CreateInstance(c *fiber.Ctx) error {
 	var request struct {
-		Name   string                        `json:"name"`
-		Phone  string                        `json:"phone"`
+		Name   string                       `json:"name"`
+		Phone  string                       `json:"phone"`
 		Config multiinstance.InstanceConfig `json:"config"`
 	}
 
@@ -84,8 +96,112 @@ func (handler *MultiInstance) CreateInstance(c *fiber.Ctx) error {
 	})
 }
 
+func (handler *MultiInstance) CloneInstance(c *fiber.Ctx) error {
+	sourceID := c.Params("id")
+	if sourceID == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Instance ID is required",
+			Results: nil,
+		})
+	}
+
+	var request struct {
+		Name  string `json:"name"`
+		Phone string `json:"phone"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if request.Name == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "New instance name is required",
+			Results: nil,
+		})
+	}
+
+	instance, err := handler.manager.CloneInstance(sourceID, request.Name, request.Phone)
+	if err != nil {
+		return c.Status(500).JSON(utils.ResponseData{
+			Status:  500,
+			Code:    "CLONE_ERROR",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  201,
+		Code:    "SUCCESS",
+		Message: "Instance cloned successfully",
+		Results: instance,
+	})
+}
+
+func (handler *MultiInstance) FailoverInstance(c *fiber.Ctx) error {
+	fromID := c.Params("id")
+	if fromID == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Instance ID is required",
+			Results: nil,
+		})
+	}
+
+	var request struct {
+		StandbyID string `json:"standby_id"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if request.StandbyID == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Standby instance ID is required",
+			Results: nil,
+		})
+	}
+
+	if err := handler.manager.Failover(fromID, request.StandbyID); err != nil {
+		return c.Status(500).JSON(utils.ResponseData{
+			Status:  500,
+			Code:    "FAILOVER_ERROR",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Failover completed successfully",
+		Results: nil,
+	})
+}
+
 func (handler *MultiInstance) ListInstances(c *fiber.Ctx) error {
-	instances := handler.manager.ListInstances()
+	sortBy := c.Query("sort", "created_at")
+	sortDir := c.Query("order", "asc")
+	instances := handler.manager.ListInstances(sortBy, sortDir)
 
 	return c.JSON(utils.ResponseData{
 		Status:  200,
@@ -94,6 +210,8 @@ func (handler *MultiInstance) ListInstances(c *fiber.Ctx) error {
 		Results: map[string]interface{}{
 			"instances": instances,
 			"count":     len(instances),
+			"sort":      sortBy,
+			"order":     sortDir,
 		},
 	})
 }
@@ -127,6 +245,45 @@ func (handler *MultiInstance) GetInstance(c *fiber.Ctx) error {
 	})
 }
 
+// GetInstanceHistory returns instance's bounded status transition history
+// along with its restart counter and last observed crash exit code, so an
+// operator can diagnose a flapping instance without reconstructing the
+// timeline from logs.
+func (handler *MultiInstance) GetInstanceHistory(c *fiber.Ctx) error {
+	instanceID := c.Params("id")
+	if instanceID == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Instance ID is required",
+			Results: nil,
+		})
+	}
+
+	instance, err := handler.manager.GetInstance(instanceID)
+	if err != nil {
+		return c.Status(404).JSON(utils.ResponseData{
+			Status:  404,
+			Code:    "NOT_FOUND",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Instance status history retrieved successfully",
+		Results: map[string]interface{}{
+			"instance_id":    instance.ID,
+			"status":         instance.Status,
+			"history":        instance.StatusHistory,
+			"restart_count":  instance.RestartCount,
+			"last_exit_code": instance.LastExitCode,
+		},
+	})
+}
+
 func (handler *MultiInstance) StartInstance(c *fiber.Ctx) error {
 	instanceID := c.Params("id")
 	if instanceID == "" {
@@ -223,6 +380,49 @@ func (handler *MultiInstance) RestartInstance(c *fiber.Ctx) error {
 	})
 }
 
+// UpdateInstanceConfig applies a partial InstanceConfig update to an
+// existing instance and restarts it (if running) so the change takes
+// effect, avoiding a delete-and-recreate for a simple config tweak.
+func (handler *MultiInstance) UpdateInstanceConfig(c *fiber.Ctx) error {
+	instanceID := c.Params("id")
+	if instanceID == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Instance ID is required",
+			Results: nil,
+		})
+	}
+
+	var updates map[string]interface{}
+	if err := c.BodyParser(&updates); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if err := handler.manager.UpdateInstanceConfig(instanceID, updates); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "UPDATE_ERROR",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	instance, _ := handler.manager.GetInstance(instanceID)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Instance config updated successfully",
+		Results: instance,
+	})
+}
+
 func (handler *MultiInstance) DeleteInstance(c *fiber.Ctx) error {
 	instanceID := c.Params("id")
 	if instanceID == "" {
@@ -254,6 +454,89 @@ func (handler *MultiInstance) DeleteInstance(c *fiber.Ctx) error {
 	})
 }
 
+// VacuumInstanceDatabase compacts the isolated database backing an
+// instance, reclaiming disk space without stopping the instance.
+func (handler *MultiInstance) VacuumInstanceDatabase(c *fiber.Ctx) error {
+	instanceID := c.Params("id")
+	if instanceID == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Instance ID is required",
+			Results: nil,
+		})
+	}
+
+	report, err := handler.manager.VacuumInstanceDatabase(instanceID)
+	if err != nil {
+		return c.Status(500).JSON(utils.ResponseData{
+			Status:  500,
+			Code:    "VACUUM_ERROR",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Database vacuumed successfully",
+		Results: report,
+	})
+}
+
+// MigrateInstanceDatabase migrates a stopped instance's SQLite database onto
+// a PostgreSQL cluster and updates the instance's config to point at it.
+func (handler *MultiInstance) MigrateInstanceDatabase(c *fiber.Ctx) error {
+	instanceID := c.Params("id")
+	if instanceID == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Instance ID is required",
+			Results: nil,
+		})
+	}
+
+	var request struct {
+		TargetURI string `json:"target_uri"`
+	}
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+	if request.TargetURI == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "target_uri is required",
+			Results: nil,
+		})
+	}
+
+	if err := handler.manager.MigrateInstanceDatabase(instanceID, request.TargetURI); err != nil {
+		return c.Status(500).JSON(utils.ResponseData{
+			Status:  500,
+			Code:    "MIGRATION_ERROR",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Database migrated to PostgreSQL successfully",
+		Results: map[string]interface{}{
+			"instance_id": instanceID,
+		},
+	})
+}
+
 func (handler *MultiInstance) GetStats(c *fiber.Ctx) error {
 	stats := handler.manager.GetStats()
 
@@ -263,4 +546,4 @@ func (handler *MultiInstance) GetStats(c *fiber.Ctx) error {
 		Message: "Instance statistics retrieved",
 		Results: stats,
 	})
-}
\ No newline at end of file
+}
@@ -2,26 +2,124 @@ package rest
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
+	"sync"
 	"time"
 
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/analytics"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/cache"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/isolation"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/queue"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
 	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
 )
 
+// defaultCleanupMaxAge is how old a file under PathSendItems/PathQrCode/
+// PathMedia must be before the "cleanup" job removes it, when the job wasn't
+// given an explicit "max_age_hours" in its Data.
+const defaultCleanupMaxAge = 24 * time.Hour
+
+// defaultDedupeWindow is how long a "send_message" fingerprint is remembered
+// for duplicate detection when the job wasn't given an explicit
+// "dedupe_ttl_seconds" in its Data.
+const defaultDedupeWindow = 5 * time.Minute
+
+// dedupeCacheKeyPrefix namespaces dedup fingerprints in the shared cache so
+// they can't collide with unrelated keys.
+const dedupeCacheKeyPrefix = "queue:dedupe:"
+
+// defaultLeaseTTL is how long a job stays leased to an external worker via
+// POST /queue/lease when the request doesn't specify a "lease_seconds".
+const defaultLeaseTTL = 5 * time.Minute
+
+// recurringCleanupJobID is the RegisterRecurringJob id used for the periodic
+// housekeeping cleanup registered by InitRestQueue.
+const recurringCleanupJobID = "housekeeping-cleanup"
+
+// queueEventThrottleInterval is the minimum gap between two monitoring
+// broadcasts for the same job type and lifecycle event, so a burst of jobs
+// doesn't flood websocket clients. Terminal events (completed/failed) are
+// always sent through, since a dashboard needs those unconditionally.
+const queueEventThrottleInterval = 250 * time.Millisecond
+
+// queueEventThrottler drops repeated non-terminal job events for the same
+// job type within queueEventThrottleInterval of each other.
+type queueEventThrottler struct {
+	mutex    sync.Mutex
+	lastSent map[string]time.Time
+}
+
+func (t *queueEventThrottler) allow(event queue.JobEvent) bool {
+	if event.Event == "completed" || event.Event == "failed" {
+		return true
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := event.Type + ":" + event.Event
+	if last, ok := t.lastSent[key]; ok && time.Since(last) < queueEventThrottleInterval {
+		return false
+	}
+	t.lastSent[key] = time.Now()
+	return true
+}
+
 type Queue struct {
-	manager *queue.QueueManager
+	manager          *queue.QueueManager
+	sessionIsolation *isolation.SessionIsolationManager
+	analyticsManager *analytics.Analytics
+}
+
+// Manager exposes the underlying queue manager for read-only aggregation by
+// other route groups (e.g. GET /system/info).
+func (handler *Queue) Manager() *queue.QueueManager {
+	return handler.manager
 }
 
 func InitRestQueue(app fiber.Router) Queue {
 	qm := queue.NewQueueManager()
-	rest := Queue{manager: qm}
+	rest := Queue{
+		manager:          qm,
+		sessionIsolation: isolation.NewSessionIsolationManager(config.PathStorages),
+		analyticsManager: analytics.NewAnalytics(),
+	}
 
 	// Register default handlers
 	rest.registerDefaultHandlers()
 
+	if err := qm.RegisterRecurringJob(recurringCleanupJobID, "cleanup", map[string]interface{}{
+		"type": "scheduled",
+	}, queue.PriorityLow, 24*time.Hour); err != nil {
+		logrus.Errorf("[QUEUE] Failed to register recurring cleanup job: %v", err)
+	}
+
+	// Stream job lifecycle events to the websocket "monitoring" channel so a
+	// live dashboard can watch throughput without polling GET /queue/jobs.
+	// Clients narrow the stream to one job type by subscribing with
+	// {"channel": "monitoring", "job_type": "<type>"}.
+	throttler := &queueEventThrottler{lastSent: make(map[string]time.Time)}
+	qm.OnJobEvent(func(event queue.JobEvent) {
+		if !throttler.allow(event) {
+			return
+		}
+		websocket.BroadcastToChannel("monitoring", websocket.BroadcastMessage{
+			Code:        "QUEUE_JOB_EVENT",
+			Message:     fmt.Sprintf("Job %s %s", event.JobID, event.Event),
+			Result:      event,
+			FilterValue: event.Type,
+		})
+	})
+
 	// Queue management routes
 	app.Post("/queue/jobs", rest.AddJob)
 	app.Post("/queue/jobs/schedule", rest.ScheduleJob)
@@ -31,6 +129,21 @@ func InitRestQueue(app fiber.Router) Queue {
 	app.Get("/queue/stats", rest.GetStats)
 	app.Post("/queue/handlers/:type", rest.RegisterHandler)
 	app.Post("/queue/jobs/:id/retry", rest.RetryJob) // Add missing retry endpoint
+	app.Get("/queue/export", rest.ExportState)
+	app.Post("/queue/import", rest.ImportState)
+	app.Get("/queue/quiet-hours", rest.GetQuietHoursPolicy)
+	app.Put("/queue/quiet-hours", rest.SetQuietHoursPolicy)
+	app.Get("/queue/jobs/:id/result", rest.GetJobResult)
+	app.Get("/queue/jobs/:id/diagnose", rest.DiagnoseJob)
+	app.Post("/queue/pause", rest.PauseQueue)
+	app.Post("/queue/resume", rest.ResumeQueue)
+	app.Get("/queue/retry-policy", rest.GetRetryPolicy)
+	app.Put("/queue/retry-policy", rest.SetRetryPolicy)
+	app.Post("/queue/requeue-failed", rest.RequeueFailed)
+	app.Get("/queue/rate-limit/:type", rest.GetRateLimitState)
+	app.Post("/queue/lease", rest.LeaseJob)
+	app.Post("/queue/ack", rest.AckJob)
+	app.Post("/queue/nack", rest.NackJob)
 
 	return rest
 }
@@ -46,9 +159,10 @@ func (handler *Queue) registerDefaultHandlers() {
 
 func (handler *Queue) AddJob(c *fiber.Ctx) error {
 	var request struct {
-		Type     string                 `json:"type"`
-		Data     map[string]interface{} `json:"data"`
-		Priority int                    `json:"priority"`
+		Type         string                 `json:"type"`
+		Data         map[string]interface{} `json:"data"`
+		Priority     int                    `json:"priority"`
+		Dependencies []string               `json:"dependencies"`
 	}
 
 	if err := c.BodyParser(&request); err != nil {
@@ -69,20 +183,30 @@ func (handler *Queue) AddJob(c *fiber.Ctx) error {
 		})
 	}
 
+	if err := normalizeJobPhone(request.Type, request.Data); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "INVALID_PHONE",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
 	// Validate priority
 	priority := queue.Priority(request.Priority)
-	if priority < queue.PriorityLow || priority > queue.PriorityUrgent {
+	if priority < queue.PriorityMin || priority > queue.PriorityMax {
 		priority = queue.PriorityNormal
 	}
 
-	job, err := handler.manager.AddJob(request.Type, request.Data, priority)
+	var job *queue.Job
+	var err error
+	if len(request.Dependencies) > 0 {
+		job, err = handler.manager.AddJobWithDependencies(request.Type, request.Data, priority, request.Dependencies)
+	} else {
+		job, err = handler.manager.AddJob(request.Type, request.Data, priority)
+	}
 	if err != nil {
-		return c.Status(400).JSON(utils.ResponseData{
-			Status:  400,
-			Code:    "QUEUE_ERROR",
-			Message: err.Error(),
-			Results: nil,
-		})
+		return respondError(c, err, 400, "QUEUE_ERROR")
 	}
 
 	return c.JSON(utils.ResponseData{
@@ -130,20 +254,24 @@ func (handler *Queue) ScheduleJob(c *fiber.Ctx) error {
 		})
 	}
 
+	if err := normalizeJobPhone(request.Type, request.Data); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "INVALID_PHONE",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
 	// Validate priority
 	priority := queue.Priority(request.Priority)
-	if priority < queue.PriorityLow || priority > queue.PriorityUrgent {
+	if priority < queue.PriorityMin || priority > queue.PriorityMax {
 		priority = queue.PriorityNormal
 	}
 
 	job, err := handler.manager.ScheduleJob(request.Type, request.Data, priority, scheduledAt)
 	if err != nil {
-		return c.Status(400).JSON(utils.ResponseData{
-			Status:  400,
-			Code:    "QUEUE_ERROR",
-			Message: err.Error(),
-			Results: nil,
-		})
+		return respondError(c, err, 400, "QUEUE_ERROR")
 	}
 
 	return c.JSON(utils.ResponseData{
@@ -157,6 +285,8 @@ func (handler *Queue) ScheduleJob(c *fiber.Ctx) error {
 func (handler *Queue) ListJobs(c *fiber.Ctx) error {
 	status := queue.JobStatus(c.Query("status", ""))
 	jobType := c.Query("type", "")
+	sortBy := c.Query("sort", "priority")
+	sortDir := c.Query("order", "desc")
 	limitStr := c.Query("limit", "50")
 
 	limit, err := strconv.Atoi(limitStr)
@@ -164,7 +294,7 @@ func (handler *Queue) ListJobs(c *fiber.Ctx) error {
 		limit = 50
 	}
 
-	jobs := handler.manager.ListJobs(status, jobType, limit)
+	jobs := handler.manager.ListJobs(status, jobType, sortBy, sortDir, limit)
 
 	return c.JSON(utils.ResponseData{
 		Status:  200,
@@ -175,6 +305,8 @@ func (handler *Queue) ListJobs(c *fiber.Ctx) error {
 			"count":  len(jobs),
 			"status": status,
 			"type":   jobType,
+			"sort":   sortBy,
+			"order":  sortDir,
 		},
 	})
 }
@@ -192,12 +324,7 @@ func (handler *Queue) GetJob(c *fiber.Ctx) error {
 
 	job, err := handler.manager.GetJob(jobID)
 	if err != nil {
-		return c.Status(404).JSON(utils.ResponseData{
-			Status:  404,
-			Code:    "NOT_FOUND",
-			Message: err.Error(),
-			Results: nil,
-		})
+		return respondError(c, err, 404, "NOT_FOUND")
 	}
 
 	return c.JSON(utils.ResponseData{
@@ -208,7 +335,7 @@ func (handler *Queue) GetJob(c *fiber.Ctx) error {
 	})
 }
 
-func (handler *Queue) CancelJob(c *fiber.Ctx) error {
+func (handler *Queue) GetJobResult(c *fiber.Ctx) error {
 	jobID := c.Params("id")
 	if jobID == "" {
 		return c.Status(400).JSON(utils.ResponseData{
@@ -219,16 +346,84 @@ func (handler *Queue) CancelJob(c *fiber.Ctx) error {
 		})
 	}
 
-	err := handler.manager.CancelJob(jobID)
+	result, err := handler.manager.GetJobResult(jobID)
 	if err != nil {
+		return respondError(c, err, 404, "NOT_FOUND")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Job result retrieved successfully",
+		Results: map[string]interface{}{
+			"job_id": jobID,
+			"result": result,
+		},
+	})
+}
+
+func (handler *Queue) DiagnoseJob(c *fiber.Ctx) error {
+	jobID := c.Params("id")
+	if jobID == "" {
 		return c.Status(400).JSON(utils.ResponseData{
 			Status:  400,
-			Code:    "CANCEL_ERROR",
-			Message: err.Error(),
+			Code:    "BAD_REQUEST",
+			Message: "Job ID is required",
 			Results: nil,
 		})
 	}
 
+	diagnosis, err := handler.manager.DiagnoseJob(jobID)
+	if err != nil {
+		return respondError(c, err, 404, "NOT_FOUND")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Job diagnosis retrieved successfully",
+		Results: diagnosis,
+	})
+}
+
+func (handler *Queue) PauseQueue(c *fiber.Ctx) error {
+	handler.manager.Pause()
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Queue paused",
+		Results: nil,
+	})
+}
+
+func (handler *Queue) ResumeQueue(c *fiber.Ctx) error {
+	handler.manager.Resume()
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Queue resumed",
+		Results: nil,
+	})
+}
+
+func (handler *Queue) CancelJob(c *fiber.Ctx) error {
+	jobID := c.Params("id")
+	if jobID == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Job ID is required",
+			Results: nil,
+		})
+	}
+
+	err := handler.manager.CancelJob(jobID)
+	if err != nil {
+		return respondError(c, err, 400, "CANCEL_ERROR")
+	}
+
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
@@ -260,6 +455,314 @@ func (handler *Queue) RetryJob(c *fiber.Ctx) error {
 	})
 }
 
+func (handler *Queue) ExportState(c *fiber.Ctx) error {
+	data, err := handler.manager.ExportState()
+	if err != nil {
+		return respondError(c, err, 500, "EXPORT_ERROR")
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(data)
+}
+
+func (handler *Queue) ImportState(c *fiber.Ctx) error {
+	var request struct {
+		Merge bool            `json:"merge"`
+		State json.RawMessage `json:"state"`
+	}
+
+	if err := c.BodyParser(&request); err != nil || len(request.State) == 0 {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body: expected a \"state\" field containing the exported queue state",
+			Results: nil,
+		})
+	}
+
+	if err := handler.manager.ImportState(request.State, request.Merge); err != nil {
+		return respondError(c, err, 400, "IMPORT_ERROR")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Queue state imported successfully",
+		Results: nil,
+	})
+}
+
+func (handler *Queue) GetQuietHoursPolicy(c *fiber.Ctx) error {
+	policy := handler.manager.GetQuietHoursPolicy()
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Quiet hours policy retrieved",
+		Results: policy,
+	})
+}
+
+func (handler *Queue) SetQuietHoursPolicy(c *fiber.Ctx) error {
+	var policy queue.QuietHoursPolicy
+
+	if err := c.BodyParser(&policy); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	handler.manager.SetQuietHoursPolicy(&policy)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Quiet hours policy updated",
+		Results: policy,
+	})
+}
+
+func (handler *Queue) GetRetryPolicy(c *fiber.Ctx) error {
+	policy := handler.manager.GetRetryPolicy()
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Retry policy retrieved",
+		Results: policy,
+	})
+}
+
+func (handler *Queue) SetRetryPolicy(c *fiber.Ctx) error {
+	var policy queue.RetryPolicy
+
+	if err := c.BodyParser(&policy); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if err := handler.manager.SetRetryPolicy(policy); err != nil {
+		return respondError(c, err, 400, "SET_RETRY_POLICY_ERROR")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Retry policy updated",
+		Results: policy,
+	})
+}
+
+func (handler *Queue) RequeueFailed(c *fiber.Ctx) error {
+	var request struct {
+		Type  string `json:"type"`
+		Since string `json:"since"` // RFC3339 format
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if request.Type == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Job type is required",
+			Results: nil,
+		})
+	}
+
+	since := time.Time{}
+	if request.Since != "" {
+		parsed, err := time.Parse(time.RFC3339, request.Since)
+		if err != nil {
+			return c.Status(400).JSON(utils.ResponseData{
+				Status:  400,
+				Code:    "BAD_REQUEST",
+				Message: "Invalid since format. Use RFC3339 format",
+				Results: nil,
+			})
+		}
+		since = parsed
+	}
+
+	count, err := handler.manager.RequeueFailed(request.Type, since)
+	if err != nil {
+		return respondError(c, err, 500, "REQUEUE_FAILED_ERROR")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Failed jobs requeued successfully",
+		Results: map[string]interface{}{
+			"type":     request.Type,
+			"since":    since,
+			"requeued": count,
+		},
+	})
+}
+
+func (handler *Queue) GetRateLimitState(c *fiber.Ctx) error {
+	jobType := c.Params("type")
+	if jobType == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Job type is required",
+			Results: nil,
+		})
+	}
+
+	remaining, resetAt := handler.manager.GetRateLimitState(jobType)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Rate limit state retrieved",
+		Results: map[string]interface{}{
+			"type":      jobType,
+			"remaining": remaining,
+			"reset_at":  resetAt,
+		},
+	})
+}
+
+// LeaseJob lets an external worker pull the next ready job of a given type
+// for it to execute out-of-process, instead of relying on a handler
+// registered via the Go API. The job must be acknowledged with AckJob or
+// NackJob before its lease expires, or it's automatically requeued.
+func (handler *Queue) LeaseJob(c *fiber.Ctx) error {
+	var request struct {
+		Type         string `json:"type"`
+		LeaseSeconds int    `json:"lease_seconds"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if request.Type == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Job type is required",
+			Results: nil,
+		})
+	}
+
+	leaseTTL := defaultLeaseTTL
+	if request.LeaseSeconds > 0 {
+		leaseTTL = time.Duration(request.LeaseSeconds) * time.Second
+	}
+
+	job, err := handler.manager.LeaseJob(request.Type, leaseTTL)
+	if err != nil {
+		return respondError(c, err, 404, "NOT_FOUND")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Job leased successfully",
+		Results: job,
+	})
+}
+
+// AckJob lets an external worker report that a job it leased via LeaseJob
+// finished successfully.
+func (handler *Queue) AckJob(c *fiber.Ctx) error {
+	var request struct {
+		JobID  string      `json:"job_id"`
+		Result interface{} `json:"result"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if request.JobID == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "job_id is required",
+			Results: nil,
+		})
+	}
+
+	if err := handler.manager.AckJob(request.JobID, request.Result); err != nil {
+		return respondError(c, err, 400, "ACK_ERROR")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Job acknowledged",
+		Results: map[string]interface{}{"job_id": request.JobID},
+	})
+}
+
+// NackJob lets an external worker report that a job it leased via LeaseJob
+// failed, optionally requesting it be retried.
+func (handler *Queue) NackJob(c *fiber.Ctx) error {
+	var request struct {
+		JobID string `json:"job_id"`
+		Error string `json:"error"`
+		Retry bool   `json:"retry"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if request.JobID == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "job_id is required",
+			Results: nil,
+		})
+	}
+
+	if err := handler.manager.NackJob(request.JobID, request.Error, request.Retry); err != nil {
+		return respondError(c, err, 400, "NACK_ERROR")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Job nacked",
+		Results: map[string]interface{}{"job_id": request.JobID},
+	})
+}
+
 func (handler *Queue) GetStats(c *fiber.Ctx) error {
 	stats := handler.manager.GetQueueStats()
 
@@ -296,6 +799,55 @@ func (handler *Queue) RegisterHandler(c *fiber.Ctx) error {
 
 // Default job handlers
 
+// jobPhoneFields maps queue job types to the Data key holding the recipient
+// phone number that AddJob/ScheduleJob must normalize before queuing.
+var jobPhoneFields = map[string]string{
+	"send_message": "phone",
+	"send_media":   "phone",
+}
+
+// normalizeJobPhone validates and normalizes the phone number in data for
+// job types listed in jobPhoneFields, mutating data in place. It rejects the
+// job with a structured error before it's ever queued, instead of letting a
+// malformed number reach the handler and fail (or retry pointlessly) later.
+func normalizeJobPhone(jobType string, data map[string]interface{}) error {
+	field, ok := jobPhoneFields[jobType]
+	if !ok {
+		return nil
+	}
+
+	phone, ok := data[field].(string)
+	if !ok || phone == "" {
+		return fmt.Errorf("%s is required for job type: %s", field, jobType)
+	}
+
+	normalized, err := utils.NormalizePhone(phone)
+	if err != nil {
+		return err
+	}
+
+	data[field] = normalized
+	return nil
+}
+
+// dedupeFingerprintKey builds the cache key used to detect a duplicate
+// "send_message" job for the same recipient and content, keyed by a hash so
+// the key stays short and safe regardless of message length or characters.
+func dedupeFingerprintKey(phone, message string) string {
+	sum := sha256.Sum256([]byte(phone + "|" + message))
+	return dedupeCacheKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+// dedupeWindow returns how long a dedupe fingerprint should be remembered,
+// honoring a per-job "dedupe_ttl_seconds" override and falling back to
+// defaultDedupeWindow otherwise.
+func dedupeWindow(data map[string]interface{}) time.Duration {
+	if seconds, ok := data["dedupe_ttl_seconds"].(float64); ok && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultDedupeWindow
+}
+
 func (handler *Queue) handleSendMessage(ctx context.Context, job *queue.Job) error {
 	// Extract message data
 	phone, ok := job.Data["phone"].(string)
@@ -303,11 +855,30 @@ func (handler *Queue) handleSendMessage(ctx context.Context, job *queue.Job) err
 		return fmt.Errorf("phone number is required")
 	}
 
+	phone, err := utils.NormalizePhone(phone)
+	if err != nil {
+		return err
+	}
+
 	message, ok := job.Data["message"].(string)
 	if !ok {
 		return fmt.Errorf("message content is required")
 	}
 
+	dedupe, _ := job.Data["dedupe"].(bool)
+	fingerprintKey := dedupeFingerprintKey(phone, message)
+	if dedupe {
+		var seen bool
+		if err := cache.Get(fingerprintKey, &seen); err == nil && seen {
+			job.Result = map[string]interface{}{
+				"phone":             phone,
+				"message":           message,
+				"skipped_duplicate": true,
+			}
+			return nil
+		}
+	}
+
 	// Simulate message sending (replace with actual WhatsApp sending logic)
 	time.Sleep(100 * time.Millisecond) // Simulate API call delay
 
@@ -318,6 +889,12 @@ func (handler *Queue) handleSendMessage(ctx context.Context, job *queue.Job) err
 		"sent_at":    time.Now(),
 	}
 
+	if dedupe {
+		if err := cache.Set(fingerprintKey, true, dedupeWindow(job.Data)); err != nil {
+			logrus.Warnf("[QUEUE] Failed to store dedupe fingerprint: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -328,6 +905,11 @@ func (handler *Queue) handleSendMedia(ctx context.Context, job *queue.Job) error
 		return fmt.Errorf("phone number is required")
 	}
 
+	phone, err := utils.NormalizePhone(phone)
+	if err != nil {
+		return err
+	}
+
 	mediaType, ok := job.Data["media_type"].(string)
 	if !ok {
 		return fmt.Errorf("media type is required")
@@ -396,24 +978,81 @@ func (handler *Queue) handleSendBulk(ctx context.Context, job *queue.Job) error
 }
 
 func (handler *Queue) handleCleanup(ctx context.Context, job *queue.Job) error {
-	// Simulate cleanup operations
 	cleanupType, ok := job.Data["type"].(string)
 	if !ok {
 		cleanupType = "general"
 	}
 
-	time.Sleep(2 * time.Second) // Simulate cleanup time
+	maxAge := defaultCleanupMaxAge
+	if maxAgeHours, ok := job.Data["max_age_hours"].(float64); ok && maxAgeHours > 0 {
+		maxAge = time.Duration(maxAgeHours * float64(time.Hour))
+	}
+
+	filesRemoved, bytesFreed := 0, int64(0)
+	for _, dir := range []string{config.PathSendItems, config.PathQrCode, config.PathMedia} {
+		removed, freed := removeFilesOlderThan(dir, maxAge)
+		filesRemoved += removed
+		bytesFreed += freed
+	}
+
+	if err := handler.sessionIsolation.CleanupInactiveSessions(maxAge); err != nil {
+		logrus.Warnf("[QUEUE] Cleanup job %s: failed to clean up inactive sessions: %v", job.ID, err)
+	}
+	sessionsRemaining := len(handler.sessionIsolation.ListSessions())
+
+	eventsTrimmed := handler.analyticsManager.TrimEvents(maxAge)
 
 	job.Result = map[string]interface{}{
-		"cleanup_type":  cleanupType,
-		"files_cleaned": 42,
-		"space_freed":   "150MB",
-		"completed_at":  time.Now(),
+		"cleanup_type":             cleanupType,
+		"max_age":                  maxAge.String(),
+		"files_removed":            filesRemoved,
+		"bytes_freed":              bytesFreed,
+		"sessions_remaining":       sessionsRemaining,
+		"analytics_events_trimmed": eventsTrimmed,
+		"completed_at":             time.Now(),
 	}
 
 	return nil
 }
 
+// removeFilesOlderThan deletes regular files under dir whose modification
+// time is older than maxAge, returning how many were removed and their
+// combined size. A missing dir is not an error - there's simply nothing to
+// clean up yet.
+func removeFilesOlderThan(dir string, maxAge time.Duration) (removed int, freedBytes int64) {
+	cutoff := time.Now().Add(-maxAge)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logrus.Warnf("[QUEUE] Cleanup: failed to read directory %s: %v", dir, err)
+		}
+		return 0, 0
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		if err := os.Remove(path); err != nil {
+			logrus.Warnf("[QUEUE] Cleanup: failed to remove %s: %v", path, err)
+			continue
+		}
+
+		removed++
+		freedBytes += info.Size()
+	}
+
+	return removed, freedBytes
+}
+
 func (handler *Queue) handleBackup(ctx context.Context, job *queue.Job) error {
 	// Simulate backup operations
 	backupType, ok := job.Data["type"].(string)
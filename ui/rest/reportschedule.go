@@ -0,0 +1,154 @@
+package rest
+
+import (
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/analytics"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/queue"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/reportschedule"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/templates"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/webhook"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReportSchedule struct {
+	manager *reportschedule.Manager
+}
+
+// Manager exposes the underlying report schedule manager for read-only
+// aggregation by other route groups.
+func (handler *ReportSchedule) Manager() *reportschedule.Manager {
+	return handler.manager
+}
+
+func InitRestReportSchedule(app fiber.Router, analyticsMgr *analytics.Analytics, webhookMgr *webhook.WebhookManager, templateMgr *templates.TemplateManager, queueMgr *queue.QueueManager) ReportSchedule {
+	rm := reportschedule.NewManager(analyticsMgr, webhookMgr, templateMgr, queueMgr)
+	rest := ReportSchedule{manager: rm}
+
+	app.Post("/analytics/schedules", rest.AddSchedule)
+	app.Get("/analytics/schedules", rest.ListSchedules)
+	app.Get("/analytics/schedules/:id", rest.GetSchedule)
+	app.Put("/analytics/schedules/:id", rest.UpdateSchedule)
+	app.Delete("/analytics/schedules/:id", rest.RemoveSchedule)
+
+	return rest
+}
+
+func (handler *ReportSchedule) AddSchedule(c *fiber.Ctx) error {
+	var schedule reportschedule.ReportSchedule
+
+	if err := c.BodyParser(&schedule); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if err := handler.manager.AddSchedule(&schedule); err != nil {
+		return respondError(c, err, 400, "REPORT_SCHEDULE_ERROR")
+	}
+
+	return c.Status(201).JSON(utils.ResponseData{
+		Status:  201,
+		Code:    "SUCCESS",
+		Message: "Report schedule added successfully",
+		Results: schedule,
+	})
+}
+
+func (handler *ReportSchedule) ListSchedules(c *fiber.Ctx) error {
+	schedules := handler.manager.ListSchedules()
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Report schedules retrieved successfully",
+		Results: map[string]interface{}{
+			"schedules": schedules,
+			"count":     len(schedules),
+		},
+	})
+}
+
+func (handler *ReportSchedule) GetSchedule(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Schedule ID is required",
+			Results: nil,
+		})
+	}
+
+	schedule, err := handler.manager.GetSchedule(id)
+	if err != nil {
+		return respondError(c, err, 404, "NOT_FOUND")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Report schedule retrieved successfully",
+		Results: schedule,
+	})
+}
+
+func (handler *ReportSchedule) UpdateSchedule(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Schedule ID is required",
+			Results: nil,
+		})
+	}
+
+	var updates map[string]interface{}
+	if err := c.BodyParser(&updates); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if err := handler.manager.UpdateSchedule(id, updates); err != nil {
+		return respondError(c, err, 400, "REPORT_SCHEDULE_ERROR")
+	}
+
+	schedule, _ := handler.manager.GetSchedule(id)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Report schedule updated successfully",
+		Results: schedule,
+	})
+}
+
+func (handler *ReportSchedule) RemoveSchedule(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Schedule ID is required",
+			Results: nil,
+		})
+	}
+
+	if err := handler.manager.RemoveSchedule(id); err != nil {
+		return respondError(c, err, 404, "NOT_FOUND")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Report schedule removed successfully",
+		Results: nil,
+	})
+}
@@ -2,13 +2,51 @@ package rest
 
 import (
 	"fmt"
+	"os"
 	"runtime"
 	"time"
 
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/cache"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/multiinstance"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/queue"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/webhook"
 	"github.com/gofiber/fiber/v2"
 )
 
-type SystemHandler struct{}
+type SystemHandler struct {
+	startTime   time.Time
+	instanceMgr *multiinstance.InstanceManager
+	queueMgr    *queue.QueueManager
+	webhookMgr  *webhook.WebhookManager
+}
+
+// SystemInfo is the consolidated diagnostic payload for GET /system/info,
+// aggregating build/runtime facts with the summary counts operators
+// otherwise have to gather from several endpoints.
+type SystemInfo struct {
+	AppVersion string `json:"app_version"`
+	GoVersion  string `json:"go_version"`
+	OS         string `json:"os"`
+	Arch       string `json:"arch"`
+	PID        int    `json:"pid"`
+	Uptime     string `json:"uptime"`
+	Goroutines int    `json:"goroutines"`
+	Memory     struct {
+		HeapAllocMB   float64 `json:"heap_alloc_mb"`
+		HeapSysMB     float64 `json:"heap_sys_mb"`
+		HeapInuseMB   float64 `json:"heap_inuse_mb"`
+		HeapIdleMB    float64 `json:"heap_idle_mb"`
+		NumGC         uint32  `json:"num_gc"`
+		LastGCPauseMs float64 `json:"last_gc_pause_ms"`
+		AvgGCPauseMs  float64 `json:"avg_gc_pause_ms"`
+	} `json:"memory"`
+	Instances *multiinstance.InstanceStats `json:"instances"`
+	Queue     *queue.QueueStats            `json:"queue"`
+	Cache     *cache.CacheStats            `json:"cache"`
+	Webhook   *webhook.WebhookStats        `json:"webhook"`
+}
 
 type SystemOverview struct {
 	Health struct {
@@ -64,20 +102,71 @@ type Alert struct {
 	Message string `json:"message"`
 }
 
-func InitRestSystem(app fiber.Router) {
-	handler := &SystemHandler{}
-	
+// InitRestSystem wires the system diagnostic routes. instanceMgr, queueMgr,
+// and webhookMgr are the same manager instances constructed for their own
+// dedicated route groups, shared here purely for read-only aggregation.
+func InitRestSystem(app fiber.Router, instanceMgr *multiinstance.InstanceManager, queueMgr *queue.QueueManager, webhookMgr *webhook.WebhookManager) SystemHandler {
+	handler := SystemHandler{
+		startTime:   time.Now(),
+		instanceMgr: instanceMgr,
+		queueMgr:    queueMgr,
+		webhookMgr:  webhookMgr,
+	}
+
 	app.Get("/system/overview", handler.GetSystemOverview)
+	app.Get("/system/info", handler.GetSystemInfo)
+
+	return handler
+}
+
+// GetSystemInfo aggregates build, runtime, and resource data into a single
+// at-a-glance diagnostic response.
+func (h *SystemHandler) GetSystemInfo(c *fiber.Ctx) error {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	info := SystemInfo{
+		AppVersion: config.AppVersion,
+		GoVersion:  runtime.Version(),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		PID:        os.Getpid(),
+		Uptime:     formatDuration(time.Since(h.startTime)),
+		Goroutines: runtime.NumGoroutine(),
+		Instances:  h.instanceMgr.GetStats(),
+		Queue:      h.queueMgr.GetQueueStats(),
+		Cache:      cache.GetStats(),
+		Webhook:    h.webhookMgr.GetStats(),
+	}
+
+	info.Memory.HeapAllocMB = float64(m.HeapAlloc) / 1024 / 1024
+	info.Memory.HeapSysMB = float64(m.HeapSys) / 1024 / 1024
+	info.Memory.HeapInuseMB = float64(m.HeapInuse) / 1024 / 1024
+	info.Memory.HeapIdleMB = float64(m.HeapIdle) / 1024 / 1024
+	info.Memory.NumGC = m.NumGC
+
+	if m.NumGC > 0 {
+		lastPause := m.PauseNs[(m.NumGC+255)%256]
+		info.Memory.LastGCPauseMs = float64(lastPause) / float64(time.Millisecond)
+		info.Memory.AvgGCPauseMs = float64(m.PauseTotalNs) / float64(m.NumGC) / float64(time.Millisecond)
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "System info retrieved successfully",
+		Results: info,
+	})
 }
 
 func (h *SystemHandler) GetSystemOverview(c *fiber.Ctx) error {
 	// Get memory stats
 	var m runtime.MemStats
 	runtime.ReadMemStats(&m)
-	
+
 	// Calculate uptime (mock for now)
 	uptime := time.Since(time.Now().Add(-24 * time.Hour))
-	
+
 	// Calculate memory usage safely
 	memoryUsage := 0
 	if m.Sys > 0 {
@@ -105,7 +194,7 @@ func (h *SystemHandler) GetSystemOverview(c *fiber.Ctx) error {
 			Stopped   int `json:"stopped"`
 			Connected int `json:"connected"`
 		}{
-			Total:     5,  // Mock data
+			Total:     5, // Mock data
 			Running:   3,
 			Stopped:   2,
 			Connected: 3,
@@ -195,7 +284,7 @@ func (h *SystemHandler) GetSystemOverview(c *fiber.Ctx) error {
 			},
 		},
 	}
-	
+
 	return c.JSON(fiber.Map{
 		"code":    200,
 		"message": "System overview retrieved successfully",
@@ -207,7 +296,7 @@ func formatDuration(d time.Duration) string {
 	days := int(d.Hours()) / 24
 	hours := int(d.Hours()) % 24
 	minutes := int(d.Minutes()) % 60
-	
+
 	if days > 0 {
 		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
 	} else if hours > 0 {
@@ -215,4 +304,4 @@ func formatDuration(d time.Duration) string {
 	} else {
 		return fmt.Sprintf("%dm", minutes)
 	}
-}
\ No newline at end of file
+}
@@ -13,6 +13,19 @@ type Templates struct {
 	manager *templates.TemplateManager
 }
 
+// Manager exposes the underlying template manager for read-only aggregation by
+// other route groups (e.g. the auto-reply rules engine).
+func (handler *Templates) Manager() *templates.TemplateManager {
+	return handler.manager
+}
+
+// requestWorkspace resolves the caller's template workspace from the
+// X-Workspace header, falling back to the shared default workspace when
+// absent so existing integrations keep working unchanged.
+func requestWorkspace(c *fiber.Ctx) string {
+	return c.Get("X-Workspace")
+}
+
 func InitRestTemplates(app fiber.Router) Templates {
 	tm := templates.NewTemplateManager()
 	rest := Templates{manager: tm}
@@ -22,16 +35,24 @@ func InitRestTemplates(app fiber.Router) Templates {
 	app.Post("/templates/advanced", rest.CreateAdvancedTemplate)
 	app.Get("/templates", rest.ListTemplates)
 	app.Get("/templates/search", rest.SearchTemplates)
+	app.Get("/templates/pick", rest.PickByTag)
 	app.Get("/templates/:id", rest.GetTemplate)
+	app.Get("/templates/:id/schema", rest.GetTemplateSchema)
 	app.Put("/templates/:id", rest.UpdateTemplate)
 	app.Delete("/templates/:id", rest.DeleteTemplate)
 	app.Post("/templates/:id/render", rest.RenderTemplate)
 	app.Post("/templates/:id/render-advanced", rest.RenderAdvancedTemplate)
+	app.Post("/templates/:id/render-batch", rest.RenderBatch)
 	app.Post("/templates/:id/clone", rest.CloneTemplate)
 	app.Get("/templates/:id/versions", rest.GetTemplateVersions)
+	app.Get("/templates/:id/diff", rest.DiffVersions)
 	app.Post("/templates/:id/restore/:version", rest.RestoreTemplateVersion)
 	app.Put("/templates/bulk", rest.BulkUpdateTemplates)
+	app.Delete("/templates/bulk", rest.BulkDeleteTemplates)
+	app.Post("/templates/reload", rest.Reload)
 	app.Get("/templates/stats", rest.GetStats)
+	app.Get("/templates/:id/metrics", rest.GetRenderMetrics)
+	app.Get("/templates/metrics", rest.GetGlobalRenderMetrics)
 
 	return rest
 }
@@ -53,19 +74,15 @@ func (handler *Templates) CreateTemplate(c *fiber.Ctx) error {
 		})
 	}
 
-	template, err := handler.manager.CreateTemplate(
+	template, err := handler.manager.CreateTemplateInWorkspace(
+		requestWorkspace(c),
 		request.Name,
 		request.Description,
 		request.Content,
 		request.Category,
 	)
 	if err != nil {
-		return c.Status(400).JSON(utils.ResponseData{
-			Status:  400,
-			Code:    "CREATE_ERROR",
-			Message: err.Error(),
-			Results: nil,
-		})
+		return respondError(c, err, 400, "CREATE_ERROR")
 	}
 
 	return c.JSON(utils.ResponseData{
@@ -78,7 +95,9 @@ func (handler *Templates) CreateTemplate(c *fiber.Ctx) error {
 
 func (handler *Templates) ListTemplates(c *fiber.Ctx) error {
 	category := c.Query("category", "")
-	templates := handler.manager.ListTemplates(category)
+	sortBy := c.Query("sort", "created_at")
+	sortDir := c.Query("order", "asc")
+	templates := handler.manager.ListTemplates(requestWorkspace(c), category, sortBy, sortDir)
 
 	return c.JSON(utils.ResponseData{
 		Status:  200,
@@ -88,6 +107,8 @@ func (handler *Templates) ListTemplates(c *fiber.Ctx) error {
 			"templates": templates,
 			"count":     len(templates),
 			"category":  category,
+			"sort":      sortBy,
+			"order":     sortDir,
 		},
 	})
 }
@@ -105,22 +126,65 @@ func (handler *Templates) GetTemplate(c *fiber.Ctx) error {
 
 	template, err := handler.manager.GetTemplate(id)
 	if err != nil {
-		return c.Status(404).JSON(utils.ResponseData{
-			Status:  404,
-			Code:    "NOT_FOUND",
-			Message: err.Error(),
+		return respondError(c, err, 404, "NOT_FOUND")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Template retrieved successfully",
+		Results: template,
+	})
+}
+
+func (handler *Templates) PickByTag(c *fiber.Ctx) error {
+	tag := c.Query("tag", "")
+	if tag == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "tag query parameter is required",
 			Results: nil,
 		})
 	}
 
+	template, err := handler.manager.PickByTag(tag)
+	if err != nil {
+		return respondError(c, err, 404, "NOT_FOUND")
+	}
+
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Template retrieved successfully",
+		Message: "Template picked successfully",
 		Results: template,
 	})
 }
 
+func (handler *Templates) GetTemplateSchema(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Template ID is required",
+			Results: nil,
+		})
+	}
+
+	schema, err := handler.manager.GetTemplateSchema(id)
+	if err != nil {
+		return respondError(c, err, 404, "NOT_FOUND")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Template schema retrieved successfully",
+		Results: schema,
+	})
+}
+
 func (handler *Templates) UpdateTemplate(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
@@ -156,12 +220,7 @@ func (handler *Templates) UpdateTemplate(c *fiber.Ctx) error {
 		request.Category,
 	)
 	if err != nil {
-		return c.Status(400).JSON(utils.ResponseData{
-			Status:  400,
-			Code:    "UPDATE_ERROR",
-			Message: err.Error(),
-			Results: nil,
-		})
+		return respondError(c, err, 400, "UPDATE_ERROR")
 	}
 
 	return c.JSON(utils.ResponseData{
@@ -185,12 +244,7 @@ func (handler *Templates) DeleteTemplate(c *fiber.Ctx) error {
 
 	err := handler.manager.DeleteTemplate(id)
 	if err != nil {
-		return c.Status(404).JSON(utils.ResponseData{
-			Status:  404,
-			Code:    "DELETE_ERROR",
-			Message: err.Error(),
-			Results: nil,
-		})
+		return respondError(c, err, 404, "DELETE_ERROR")
 	}
 
 	return c.JSON(utils.ResponseData{
@@ -213,7 +267,8 @@ func (handler *Templates) RenderTemplate(c *fiber.Ctx) error {
 	}
 
 	var request struct {
-		Variables map[string]string `json:"variables"`
+		Variables    map[string]string `json:"variables"`
+		OutputFormat string            `json:"output_format"`
 	}
 
 	if err := c.BodyParser(&request); err != nil {
@@ -229,15 +284,26 @@ func (handler *Templates) RenderTemplate(c *fiber.Ctx) error {
 		request.Variables = make(map[string]string)
 	}
 
-	renderedContent, err := handler.manager.RenderTemplate(id, request.Variables)
-	if err != nil {
-		return c.Status(400).JSON(utils.ResponseData{
-			Status:  400,
-			Code:    "RENDER_ERROR",
-			Message: err.Error(),
-			Results: nil,
+	var renderedContent string
+	var err error
+	if request.OutputFormat == "" {
+		renderedContent, err = handler.manager.RenderTemplate(id, request.Variables)
+	} else {
+		vars := make(map[string]interface{}, len(request.Variables))
+		for k, v := range request.Variables {
+			vars[k] = v
+		}
+		renderedContent, err = handler.manager.RenderAdvancedTemplate(id, &templates.RenderContext{
+			Variables:    vars,
+			Timestamp:    time.Now(),
+			Language:     "en",
+			Sandboxed:    true,
+			OutputFormat: request.OutputFormat,
 		})
 	}
+	if err != nil {
+		return respondError(c, err, 400, "RENDER_ERROR")
+	}
 
 	return c.JSON(utils.ResponseData{
 		Status:  200,
@@ -263,14 +329,12 @@ func (handler *Templates) CreateAdvancedTemplate(c *fiber.Ctx) error {
 		})
 	}
 
+	if template.Workspace == "" {
+		template.Workspace = requestWorkspace(c)
+	}
 	createdTemplate, err := handler.manager.CreateAdvancedTemplate(&template)
 	if err != nil {
-		return c.Status(400).JSON(utils.ResponseData{
-			Status:  400,
-			Code:    "CREATE_ERROR",
-			Message: err.Error(),
-			Results: nil,
-		})
+		return respondError(c, err, 400, "CREATE_ERROR")
 	}
 
 	return c.JSON(utils.ResponseData{
@@ -283,7 +347,7 @@ func (handler *Templates) CreateAdvancedTemplate(c *fiber.Ctx) error {
 
 func (handler *Templates) SearchTemplates(c *fiber.Ctx) error {
 	query := c.Query("q", "")
-	
+
 	filters := make(map[string]interface{})
 	if category := c.Query("category"); category != "" {
 		filters["category"] = category
@@ -295,7 +359,7 @@ func (handler *Templates) SearchTemplates(c *fiber.Ctx) error {
 		filters["tags"] = strings.Split(tags, ",")
 	}
 
-	templates := handler.manager.SearchTemplates(query, filters)
+	templates := handler.manager.SearchTemplates(requestWorkspace(c), query, filters)
 
 	return c.JSON(utils.ResponseData{
 		Status:  200,
@@ -335,6 +399,10 @@ func (handler *Templates) RenderAdvancedTemplate(c *fiber.Ctx) error {
 	context.UserAgent = c.Get("User-Agent")
 	context.IP = c.IP()
 	context.Timestamp = time.Now()
+	// Sandboxed isn't a JSON field, so BodyParser can't have set it - force
+	// it here so every render reachable from this public endpoint runs with
+	// the restricted func set regardless.
+	context.Sandboxed = true
 
 	if context.Variables == nil {
 		context.Variables = make(map[string]interface{})
@@ -342,12 +410,7 @@ func (handler *Templates) RenderAdvancedTemplate(c *fiber.Ctx) error {
 
 	renderedContent, err := handler.manager.RenderAdvancedTemplate(id, &context)
 	if err != nil {
-		return c.Status(400).JSON(utils.ResponseData{
-			Status:  400,
-			Code:    "RENDER_ERROR",
-			Message: err.Error(),
-			Results: nil,
-		})
+		return respondError(c, err, 400, "RENDER_ERROR")
 	}
 
 	return c.JSON(utils.ResponseData{
@@ -397,12 +460,7 @@ func (handler *Templates) CloneTemplate(c *fiber.Ctx) error {
 
 	clonedTemplate, err := handler.manager.CloneTemplate(id, request.NewName)
 	if err != nil {
-		return c.Status(400).JSON(utils.ResponseData{
-			Status:  400,
-			Code:    "CLONE_ERROR",
-			Message: err.Error(),
-			Results: nil,
-		})
+		return respondError(c, err, 400, "CLONE_ERROR")
 	}
 
 	return c.JSON(utils.ResponseData{
@@ -426,12 +484,7 @@ func (handler *Templates) GetTemplateVersions(c *fiber.Ctx) error {
 
 	versions, err := handler.manager.GetTemplateVersions(id)
 	if err != nil {
-		return c.Status(404).JSON(utils.ResponseData{
-			Status:  404,
-			Code:    "NOT_FOUND",
-			Message: err.Error(),
-			Results: nil,
-		})
+		return respondError(c, err, 404, "NOT_FOUND")
 	}
 
 	return c.JSON(utils.ResponseData{
@@ -446,10 +499,50 @@ func (handler *Templates) GetTemplateVersions(c *fiber.Ctx) error {
 	})
 }
 
+func (handler *Templates) DiffVersions(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Template ID is required",
+			Results: nil,
+		})
+	}
+
+	from := c.Query("from", "")
+	to := c.Query("to", "")
+	if from == "" || to == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "from and to query parameters are required",
+			Results: nil,
+		})
+	}
+
+	diff, err := handler.manager.DiffVersions(id, from, to)
+	if err != nil {
+		return respondError(c, err, 404, "NOT_FOUND")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Template versions diffed successfully",
+		Results: map[string]interface{}{
+			"template_id": id,
+			"from":        from,
+			"to":          to,
+			"diff":        diff,
+		},
+	})
+}
+
 func (handler *Templates) RestoreTemplateVersion(c *fiber.Ctx) error {
 	id := c.Params("id")
 	version := c.Params("version")
-	
+
 	if id == "" || version == "" {
 		return c.Status(400).JSON(utils.ResponseData{
 			Status:  400,
@@ -461,12 +554,7 @@ func (handler *Templates) RestoreTemplateVersion(c *fiber.Ctx) error {
 
 	err := handler.manager.RestoreTemplateVersion(id, version)
 	if err != nil {
-		return c.Status(400).JSON(utils.ResponseData{
-			Status:  400,
-			Code:    "RESTORE_ERROR",
-			Message: err.Error(),
-			Results: nil,
-		})
+		return respondError(c, err, 400, "RESTORE_ERROR")
 	}
 
 	return c.JSON(utils.ResponseData{
@@ -494,24 +582,63 @@ func (handler *Templates) BulkUpdateTemplates(c *fiber.Ctx) error {
 
 	err := handler.manager.BulkUpdateTemplates(updates)
 	if err != nil {
+		return respondError(c, err, 400, "BULK_UPDATE_ERROR")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Templates updated successfully",
+		Results: map[string]interface{}{
+			"updated_count": len(updates),
+		},
+	})
+}
+
+func (handler *Templates) BulkDeleteTemplates(c *fiber.Ctx) error {
+	var request struct {
+		Filters map[string]interface{} `json:"filters"`
+		Confirm bool                   `json:"confirm"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
 		return c.Status(400).JSON(utils.ResponseData{
 			Status:  400,
-			Code:    "BULK_UPDATE_ERROR",
-			Message: err.Error(),
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
 			Results: nil,
 		})
 	}
 
+	deleted, err := handler.manager.DeleteTemplatesWhere(request.Filters, request.Confirm)
+	if err != nil {
+		return respondError(c, err, 400, "BULK_DELETE_ERROR")
+	}
+
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Templates updated successfully",
+		Message: "Templates deleted successfully",
 		Results: map[string]interface{}{
-			"updated_count": len(updates),
+			"deleted_count": deleted,
+			"filters":       request.Filters,
 		},
 	})
 }
 
+func (handler *Templates) Reload(c *fiber.Ctx) error {
+	if err := handler.manager.Reload(); err != nil {
+		return respondError(c, err, 500, "RELOAD_ERROR")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Templates reloaded from disk",
+		Results: nil,
+	})
+}
+
 func (handler *Templates) GetStats(c *fiber.Ctx) error {
 	stats := handler.manager.GetTemplateStats()
 
@@ -521,4 +648,97 @@ func (handler *Templates) GetStats(c *fiber.Ctx) error {
 		Message: "Template statistics retrieved",
 		Results: stats,
 	})
-}
\ No newline at end of file
+}
+
+// RenderBatch renders the same template against many contexts concurrently,
+// useful for personalizing a campaign to thousands of recipients without
+// paying the cost of rendering serially.
+func (handler *Templates) RenderBatch(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Template ID is required",
+			Results: nil,
+		})
+	}
+
+	var request struct {
+		Contexts    []templates.RenderContext `json:"contexts"`
+		Concurrency int                       `json:"concurrency"`
+	}
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	items := make([]templates.BatchRenderItem, len(request.Contexts))
+	for i := range request.Contexts {
+		context := request.Contexts[i]
+		if context.Variables == nil {
+			context.Variables = make(map[string]interface{})
+		}
+		context.UserAgent = c.Get("User-Agent")
+		context.IP = c.IP()
+		context.Timestamp = time.Now()
+
+		items[i] = templates.BatchRenderItem{ID: id, Context: &context}
+	}
+
+	results := handler.manager.RenderBatch(items, request.Concurrency)
+
+	rendered := make([]map[string]interface{}, len(results))
+	failures := 0
+	for i, result := range results {
+		entry := map[string]interface{}{"rendered_content": result.Content}
+		if result.Err != nil {
+			entry["error"] = result.Err.Error()
+			failures++
+		}
+		rendered[i] = entry
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Batch render completed",
+		Results: map[string]interface{}{
+			"template_id": id,
+			"count":       len(rendered),
+			"failures":    failures,
+			"results":     rendered,
+		},
+	})
+}
+
+func (handler *Templates) GetRenderMetrics(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	metrics, err := handler.manager.GetRenderMetrics(id)
+	if err != nil {
+		return respondError(c, err, 404, "NOT_FOUND")
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Template render metrics retrieved",
+		Results: metrics,
+	})
+}
+
+func (handler *Templates) GetGlobalRenderMetrics(c *fiber.Ctx) error {
+	metrics := handler.manager.GetGlobalRenderMetrics()
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Global template render metrics retrieved",
+		Results: metrics,
+	})
+}
@@ -1,6 +1,8 @@
 package rest
 
 import (
+	"time"
+
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/webhook"
 	"github.com/gofiber/fiber/v2"
@@ -10,19 +12,33 @@ type Webhook struct {
 	manager *webhook.WebhookManager
 }
 
+// Manager exposes the underlying webhook manager for read-only aggregation by
+// other route groups (e.g. GET /system/info).
+func (handler *Webhook) Manager() *webhook.WebhookManager {
+	return handler.manager
+}
+
 func InitRestWebhook(app fiber.Router) Webhook {
 	wm := webhook.NewWebhookManager()
 	rest := Webhook{manager: wm}
 
 	// Webhook management routes
 	app.Post("/webhooks", rest.AddEndpoint)
+	app.Put("/webhooks", rest.UpsertEndpoint)
 	app.Get("/webhooks", rest.ListEndpoints)
 	app.Get("/webhooks/:id", rest.GetEndpoint)
 	app.Put("/webhooks/:id", rest.UpdateEndpoint)
+	app.Post("/webhooks/:id/rotate-secret", rest.RotateSecret)
 	app.Delete("/webhooks/:id", rest.RemoveEndpoint)
 	app.Post("/webhooks/:id/test", rest.TestEndpoint)
+	app.Post("/webhooks/:id/debug", rest.DebugDeliver)
+	app.Post("/webhooks/test-all", rest.TestAllEndpoints)
 	app.Post("/webhooks/send", rest.SendEvent)
 	app.Get("/webhooks/stats", rest.GetStats)
+	app.Put("/webhooks/concurrency", rest.SetMaxConcurrentDeliveries)
+	app.Put("/webhooks/response-body-limit", rest.SetMaxResponseBodySize)
+	app.Put("/webhooks/retry-jitter", rest.SetRetryJitter)
+	app.Get("/webhooks/health", rest.GetHealth)
 	app.Get("/webhook/list", rest.ListEndpoints)       // Alias for compatibility
 	app.Get("/webhook/deliveries", rest.GetDeliveries) // Alias for compatibility
 
@@ -50,13 +66,15 @@ func (handler *Webhook) AddEndpoint(c *fiber.Ctx) error {
 		})
 	}
 
-	err := handler.manager.AddEndpoint(&endpoint)
+	validate := c.QueryBool("validate", false)
+
+	testDelivery, err := handler.manager.AddEndpointWithValidation(&endpoint, validate)
 	if err != nil {
 		return c.Status(400).JSON(utils.ResponseData{
 			Status:  400,
 			Code:    "WEBHOOK_ERROR",
 			Message: err.Error(),
-			Results: nil,
+			Results: testDelivery,
 		})
 	}
 
@@ -64,7 +82,52 @@ func (handler *Webhook) AddEndpoint(c *fiber.Ctx) error {
 		Status:  201,
 		Code:    "SUCCESS",
 		Message: "Webhook endpoint added successfully",
-		Results: endpoint,
+		Results: map[string]interface{}{
+			"endpoint":      endpoint,
+			"test_delivery": testDelivery,
+		},
+	})
+}
+
+// UpsertEndpoint declaratively creates or updates a webhook endpoint,
+// matching an existing one by ID or Name so repeated calls with the same
+// declaration converge instead of accumulating duplicates.
+func (handler *Webhook) UpsertEndpoint(c *fiber.Ctx) error {
+	var endpoint webhook.WebhookEndpoint
+
+	if err := c.BodyParser(&endpoint); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if endpoint.URL == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Webhook URL is required",
+			Results: nil,
+		})
+	}
+
+	result, err := handler.manager.UpsertEndpoint(&endpoint)
+	if err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "WEBHOOK_ERROR",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Webhook endpoint upserted successfully",
+		Results: result,
 	})
 }
 
@@ -153,6 +216,60 @@ func (handler *Webhook) UpdateEndpoint(c *fiber.Ctx) error {
 	})
 }
 
+func (handler *Webhook) RotateSecret(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Endpoint ID is required",
+			Results: nil,
+		})
+	}
+
+	var request struct {
+		NewSecret string `json:"new_secret"`
+		OverlapMs int64  `json:"overlap_ms"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if request.NewSecret == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "new_secret is required",
+			Results: nil,
+		})
+	}
+
+	overlap := time.Duration(request.OverlapMs) * time.Millisecond
+	if err := handler.manager.RotateSecret(id, request.NewSecret, overlap); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "ROTATE_ERROR",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	endpoint, _ := handler.manager.GetEndpoint(id)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Webhook secret rotated successfully",
+		Results: endpoint,
+	})
+}
+
 func (handler *Webhook) RemoveEndpoint(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
@@ -211,6 +328,68 @@ func (handler *Webhook) TestEndpoint(c *fiber.Ctx) error {
 	})
 }
 
+func (handler *Webhook) DebugDeliver(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Endpoint ID is required",
+			Results: nil,
+		})
+	}
+
+	var event webhook.WebhookEvent
+	if err := c.BodyParser(&event); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if event.Type == "" {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Event type is required",
+			Results: nil,
+		})
+	}
+
+	result, err := handler.manager.DebugDeliver(id, &event)
+	if err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "DEBUG_ERROR",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Webhook debug delivery completed",
+		Results: result,
+	})
+}
+
+func (handler *Webhook) TestAllEndpoints(c *fiber.Ctx) error {
+	results := handler.manager.TestAllEndpoints()
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Webhook endpoints tested",
+		Results: map[string]interface{}{
+			"deliveries": results,
+			"count":      len(results),
+		},
+	})
+}
+
 func (handler *Webhook) SendEvent(c *fiber.Ctx) error {
 	var event webhook.WebhookEvent
 
@@ -264,6 +443,108 @@ func (handler *Webhook) GetStats(c *fiber.Ctx) error {
 	})
 }
 
+func (handler *Webhook) SetMaxConcurrentDeliveries(c *fiber.Ctx) error {
+	var request struct {
+		Max int `json:"max"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if err := handler.manager.SetMaxConcurrentDeliveries(request.Max); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Max concurrent deliveries updated",
+		Results: map[string]interface{}{"max": request.Max},
+	})
+}
+
+func (handler *Webhook) SetMaxResponseBodySize(c *fiber.Ctx) error {
+	var request struct {
+		MaxBytes int64 `json:"max_bytes"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	if err := handler.manager.SetMaxResponseBodySize(request.MaxBytes); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: err.Error(),
+			Results: nil,
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Max captured response body size updated",
+		Results: map[string]interface{}{"max_bytes": request.MaxBytes},
+	})
+}
+
+func (handler *Webhook) SetRetryJitter(c *fiber.Ctx) error {
+	var request struct {
+		Enabled bool `json:"enabled"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	handler.manager.SetRetryJitter(request.Enabled)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Retry jitter updated",
+		Results: map[string]interface{}{"enabled": request.Enabled},
+	})
+}
+
+func (handler *Webhook) GetHealth(c *fiber.Ctx) error {
+	health := handler.manager.GetHealth()
+
+	status := 200
+	if !health.Healthy {
+		status = 503
+	}
+
+	return c.Status(status).JSON(utils.ResponseData{
+		Status:  status,
+		Code:    "SUCCESS",
+		Message: "Webhook health retrieved",
+		Results: health,
+	})
+}
+
 func (handler *Webhook) GetDeliveries(c *fiber.Ctx) error {
 	// For now, return empty deliveries (can be implemented later)
 	return c.JSON(utils.ResponseData{
@@ -14,14 +14,19 @@ import (
 )
 
 type Client struct {
-	ID           string                 `json:"id"`
-	Connection   *websocket.Conn        `json:"-"`
-	ConnectedAt  time.Time              `json:"connected_at"`
-	LastPing     time.Time              `json:"last_ping"`
-	LastPong     time.Time              `json:"last_pong"`
-	Subscriptions map[string]bool       `json:"subscriptions"`
-	Metadata     map[string]interface{} `json:"metadata"`
-	mutex        sync.RWMutex           `json:"-"`
+	ID            string          `json:"id"`
+	Connection    *websocket.Conn `json:"-"`
+	ConnectedAt   time.Time       `json:"connected_at"`
+	LastPing      time.Time       `json:"last_ping"`
+	LastPong      time.Time       `json:"last_pong"`
+	Subscriptions map[string]bool `json:"subscriptions"`
+	// ChannelFilters optionally narrows a channel subscription to messages
+	// whose BroadcastMessage.FilterValue matches, e.g. subscribing to
+	// "monitoring" with a job_type filter to only see one queue job type.
+	// A channel with no entry here receives every message on that channel.
+	ChannelFilters map[string]string      `json:"channel_filters"`
+	Metadata       map[string]interface{} `json:"metadata"`
+	mutex          sync.RWMutex           `json:"-"`
 }
 
 type BroadcastMessage struct {
@@ -31,6 +36,9 @@ type BroadcastMessage struct {
 	Timestamp time.Time              `json:"timestamp"`
 	Channel   string                 `json:"channel,omitempty"`
 	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	// FilterValue is matched against each subscribed client's ChannelFilters
+	// for Channel; it isn't sent to clients, only used to route the message.
+	FilterValue string `json:"-"`
 }
 
 type WebSocketMessage struct {
@@ -46,7 +54,7 @@ var (
 	Register   = make(chan *Client)
 	Broadcast  = make(chan BroadcastMessage)
 	Unregister = make(chan *Client)
-	
+
 	// Channels for different message types
 	channels = map[string]bool{
 		"whatsapp":   true,
@@ -60,10 +68,10 @@ var (
 func handleRegister(client *Client) {
 	clientsMux.Lock()
 	defer clientsMux.Unlock()
-	
+
 	Clients[client.ID] = client
 	logrus.Infof("[WS] Client registered: %s", client.ID)
-	
+
 	// Send welcome message
 	welcomeMsg := BroadcastMessage{
 		Code:      "CONNECTED",
@@ -76,30 +84,37 @@ func handleRegister(client *Client) {
 			"channels":     getAvailableChannels(),
 		},
 	}
-	
+
 	sendToClient(client, welcomeMsg)
 }
 
 func handleUnregister(client *Client) {
 	clientsMux.Lock()
 	defer clientsMux.Unlock()
-	
+
 	delete(Clients, client.ID)
 	logrus.Infof("[WS] Client unregistered: %s", client.ID)
 }
 
 func broadcastMessage(message BroadcastMessage) {
 	message.Timestamp = time.Now()
-	
+
 	clientsMux.RLock()
 	defer clientsMux.RUnlock()
-	
+
 	for _, client := range Clients {
 		// Check if client is subscribed to this channel
 		if message.Channel != "" && !client.IsSubscribed(message.Channel) {
 			continue
 		}
-		
+
+		// Check if the client narrowed this channel to a specific filter value
+		if message.FilterValue != "" {
+			if filter, ok := client.ChannelFilter(message.Channel); ok && filter != message.FilterValue {
+				continue
+			}
+		}
+
 		sendToClient(client, message)
 	}
 }
@@ -123,14 +138,14 @@ func closeConnection(client *Client) {
 	if err := client.Connection.WriteMessage(websocket.CloseMessage, closeMsg); err != nil {
 		logrus.Errorf("[WS] Write close message error for client %s: %v", client.ID, err)
 	}
-	
+
 	// Give the close message time to be sent
 	time.Sleep(100 * time.Millisecond)
-	
+
 	if err := client.Connection.Close(); err != nil {
 		logrus.Errorf("[WS] Close connection error for client %s: %v", client.ID, err)
 	}
-	
+
 	clientsMux.Lock()
 	delete(Clients, client.ID)
 	clientsMux.Unlock()
@@ -139,7 +154,7 @@ func closeConnection(client *Client) {
 func RunHub() {
 	// Start periodic ping to keep connections alive
 	go startPingTicker()
-	
+
 	for {
 		select {
 		case client := <-Register:
@@ -166,15 +181,16 @@ func RegisterRoutes(app fiber.Router, service domainApp.IAppUsecase) {
 	app.Get("/ws", websocket.New(func(conn *websocket.Conn) {
 		// Create new client
 		client := &Client{
-			ID:            generateClientID(),
-			Connection:    conn,
-			ConnectedAt:   time.Now(),
-			LastPing:      time.Now(),
-			LastPong:      time.Now(),
-			Subscriptions: make(map[string]bool),
-			Metadata:      make(map[string]interface{}),
+			ID:             generateClientID(),
+			Connection:     conn,
+			ConnectedAt:    time.Now(),
+			LastPing:       time.Now(),
+			LastPong:       time.Now(),
+			Subscriptions:  make(map[string]bool),
+			ChannelFilters: make(map[string]string),
+			Metadata:       make(map[string]interface{}),
 		}
-		
+
 		// Subscribe to default channels
 		client.Subscribe("system")
 		client.Subscribe("whatsapp")
@@ -244,6 +260,28 @@ func (c *Client) Unsubscribe(channel string) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 	delete(c.Subscriptions, channel)
+	delete(c.ChannelFilters, channel)
+}
+
+// SetChannelFilter narrows channel to only messages whose FilterValue equals
+// value. An empty value clears the filter, so the client goes back to
+// receiving every message on channel.
+func (c *Client) SetChannelFilter(channel, value string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if value == "" {
+		delete(c.ChannelFilters, channel)
+		return
+	}
+	c.ChannelFilters[channel] = value
+}
+
+// ChannelFilter returns the filter value set for channel, if any.
+func (c *Client) ChannelFilter(channel string) (string, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	filter, ok := c.ChannelFilters[channel]
+	return filter, ok
 }
 
 func (c *Client) IsSubscribed(channel string) bool {
@@ -266,9 +304,9 @@ func (c *Client) UpdateLastPong() {
 
 // Helper functions
 func generateClientID() string {
-	return time.Now().Format("20060102150405") + "_" + 
-		   string(rune(65 + time.Now().UnixNano()%26)) + 
-		   string(rune(65 + (time.Now().UnixNano()/1000)%26))
+	return time.Now().Format("20060102150405") + "_" +
+		string(rune(65+time.Now().UnixNano()%26)) +
+		string(rune(65+(time.Now().UnixNano()/1000)%26))
 }
 
 func getAvailableChannels() []string {
@@ -310,11 +348,18 @@ func handleClientMessage(client *Client, message []byte, service domainApp.IAppU
 		if channel, ok := wsMessage.Data["channel"].(string); ok {
 			if channels[channel] {
 				client.Subscribe(channel)
+
+				// e.g. {"channel": "monitoring", "job_type": "send_message"}
+				// only streams events for that queue job type on this channel.
+				if jobType, ok := wsMessage.Data["job_type"].(string); ok {
+					client.SetChannelFilter(channel, jobType)
+				}
+
 				response := BroadcastMessage{
 					Code:    "SUBSCRIBED",
 					Message: "Subscribed to channel: " + channel,
 					Channel: "system",
-					Result:  map[string]interface{}{"channel": channel},
+					Result:  map[string]interface{}{"channel": channel, "job_type": wsMessage.Data["job_type"]},
 				}
 				sendToClient(client, response)
 			}
@@ -390,7 +435,7 @@ func BroadcastToChannel(channel string, message BroadcastMessage) {
 func GetConnectedClients() map[string]*Client {
 	clientsMux.RLock()
 	defer clientsMux.RUnlock()
-	
+
 	result := make(map[string]*Client)
 	for id, client := range Clients {
 		result[id] = client